@@ -0,0 +1,58 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidCursorToken is returned when a cursor token is malformed or its
+// signature does not match.
+var ErrInvalidCursorToken = errors.New("invalid cursor token")
+
+// CursorCodec encodes pagination positions as opaque tokens signed with
+// HMAC-SHA256 so clients cannot forge or alter them.
+type CursorCodec struct {
+	secret []byte
+}
+
+func NewCursorCodec(secret []byte) *CursorCodec {
+	return &CursorCodec{secret: secret}
+}
+
+// Encode serializes v into a token of the form payload.signature
+func (c *CursorCodec) Encode(v any) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + c.sign(encoded), nil
+}
+
+// Decode verifies token and deserializes its payload into v
+func (c *CursorCodec) Decode(token string, v any) error {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(c.sign(encoded))) {
+		return ErrInvalidCursorToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidCursorToken
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return ErrInvalidCursorToken
+	}
+	return nil
+}
+
+func (c *CursorCodec) sign(encoded string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursorCodec(t *testing.T) {
+	type position struct {
+		Value string `json:"v"`
+		ID    uint   `json:"id"`
+	}
+
+	codec := NewCursorCodec([]byte("test-secret"))
+
+	t.Run("round trips a value", func(t *testing.T) {
+		token, err := codec.Encode(position{Value: "12.49", ID: 7})
+		assert.NoError(t, err)
+
+		var decoded position
+		err = codec.Decode(token, &decoded)
+
+		assert.NoError(t, err)
+		assert.Equal(t, position{Value: "12.49", ID: 7}, decoded)
+	})
+
+	t.Run("rejects a tampered payload", func(t *testing.T) {
+		token, _ := codec.Encode(position{Value: "12.49", ID: 7})
+		forged, _ := codec.Encode(position{Value: "0", ID: 1})
+		_, signature, _ := strings.Cut(token, ".")
+		payload, _, _ := strings.Cut(forged, ".")
+
+		var decoded position
+		err := codec.Decode(payload+"."+signature, &decoded)
+
+		assert.ErrorIs(t, err, ErrInvalidCursorToken)
+	})
+
+	t.Run("rejects tokens signed with another secret", func(t *testing.T) {
+		token, _ := NewCursorCodec([]byte("other-secret")).Encode(position{ID: 1})
+
+		var decoded position
+		err := codec.Decode(token, &decoded)
+
+		assert.ErrorIs(t, err, ErrInvalidCursorToken)
+	})
+
+	t.Run("rejects malformed tokens", func(t *testing.T) {
+		var decoded position
+
+		assert.ErrorIs(t, codec.Decode("not-a-token", &decoded), ErrInvalidCursorToken)
+		assert.ErrorIs(t, codec.Decode("", &decoded), ErrInvalidCursorToken)
+	})
+}
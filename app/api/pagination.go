@@ -0,0 +1,38 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+)
+
+const (
+	DefaultLimit = 10
+	MaxLimit     = 100
+)
+
+// ParsePagination reads the offset and limit query parameters.
+// Offset defaults to 0; limit defaults to DefaultLimit and is clamped to [1, MaxLimit].
+func ParsePagination(r *http.Request) (offset, limit int) {
+	offset = ParseIntParam(r, "offset", 0)
+	limit = ParseIntParam(r, "limit", DefaultLimit)
+
+	// Validate and normalize limit (min: 1, max: 100)
+	if limit < 1 {
+		limit = 1
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	return offset, limit
+}
+
+// ParseIntParam parses an integer query parameter with a default value
+func ParseIntParam(r *http.Request, key string, defaultValue int) int {
+	if valueStr := r.URL.Query().Get(key); valueStr != "" {
+		if value, err := strconv.Atoi(valueStr); err == nil {
+			return value
+		}
+	}
+	return defaultValue
+}
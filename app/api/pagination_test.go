@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePagination(t *testing.T) {
+	cases := []struct {
+		name          string
+		query         string
+		offset, limit int
+	}{
+		{"defaults", "", 0, DefaultLimit},
+		{"custom values", "?offset=5&limit=20", 5, 20},
+		{"limit below minimum", "?limit=0", 0, 1},
+		{"limit above maximum", "?limit=500", 0, MaxLimit},
+		{"invalid values fall back to defaults", "?offset=abc&limit=xyz", 0, DefaultLimit},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/"+tc.query, nil)
+
+			offset, limit := ParsePagination(req)
+
+			assert.Equal(t, tc.offset, offset)
+			assert.Equal(t, tc.limit, limit)
+		})
+	}
+}
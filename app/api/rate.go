@@ -0,0 +1,26 @@
+package api
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// Rate is a fraction such as a VAT rate, e.g. 0.19 for 19%. Unlike prices it
+// has a single representation in every API version: a JSON number written
+// with the exact decimal digits of the rate. Numeric strings are accepted
+// when decoding.
+type Rate struct {
+	decimal.Decimal
+}
+
+// NewRate wraps a fraction
+func NewRate(d decimal.Decimal) Rate {
+	return Rate{Decimal: d}
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.Decimal.String()), nil
+}
+
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	return r.Decimal.UnmarshalJSON(data)
+}
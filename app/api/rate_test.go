@@ -0,0 +1,26 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRate(t *testing.T) {
+	t.Run("renders an exact JSON number", func(t *testing.T) {
+		body, err := json.Marshal(map[string]Rate{"rate": NewRate(decimal.RequireFromString("0.19"))})
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"rate":0.19}`, string(body))
+	})
+
+	t.Run("decodes numbers and numeric strings", func(t *testing.T) {
+		for _, body := range []string{`0.07`, `"0.07"`} {
+			var rate Rate
+			assert.NoError(t, json.Unmarshal([]byte(body), &rate), body)
+			assert.Equal(t, "0.07", rate.String(), body)
+		}
+	})
+}
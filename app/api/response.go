@@ -23,6 +23,17 @@ func CreatedResponse(w http.ResponseWriter, data any) {
 	}
 }
 
+// JSONResponse writes data with a status other than the success statuses,
+// e.g. a report explaining why a request was rejected
+func JSONResponse(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func ErrorResponse(w http.ResponseWriter, status int, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -32,3 +43,7 @@ func ErrorResponse(w http.ResponseWriter, status int, message string) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
+
+func NoContentResponse(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
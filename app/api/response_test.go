@@ -49,6 +49,17 @@ func TestCreatedResponse(t *testing.T) {
 	})
 }
 
+func TestJSONResponse(t *testing.T) {
+	t.Run("json response with a given http status code", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		JSONResponse(recorder, http.StatusUnprocessableEntity, map[string]int{"failed": 2})
+
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"failed":2}`, recorder.Body.String())
+	})
+}
+
 func TestErrorResponse(t *testing.T) {
 	t.Run("json response for a given http status code", func(t *testing.T) {
 		recorder := httptest.NewRecorder()
@@ -61,3 +72,13 @@ func TestErrorResponse(t *testing.T) {
 		assert.JSONEq(t, expected, recorder.Body.String(), "Response body does not match expected")
 	})
 }
+
+func TestNoContentResponse(t *testing.T) {
+	t.Run("empty http204 response", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		NoContentResponse(recorder)
+
+		assert.Equal(t, http.StatusNoContent, recorder.Code, "Expected status code 204 No Content")
+		assert.Empty(t, recorder.Body.String(), "Expected empty response body")
+	})
+}
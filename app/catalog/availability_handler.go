@@ -0,0 +1,133 @@
+package catalog
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// AvailabilityResponse is the stock breakdown of a product. Without a
+// warehouse, availability is the total over the active warehouses.
+type AvailabilityResponse struct {
+	Code      string                        `json:"code"`
+	Warehouse string                        `json:"warehouse,omitempty"`
+	Available int                           `json:"available"`
+	InStock   bool                          `json:"in_stock"`
+	Variants  []VariantAvailabilityResponse `json:"variants"`
+}
+
+// VariantAvailabilityResponse is the stock of a variant per warehouse
+type VariantAvailabilityResponse struct {
+	SKU        string                   `json:"sku"`
+	Name       string                   `json:"name"`
+	Available  int                      `json:"available"`
+	InStock    bool                     `json:"in_stock"`
+	Warehouses []WarehouseStockResponse `json:"warehouses"`
+}
+
+// WarehouseStockResponse is the stock of a variant in one warehouse
+type WarehouseStockResponse struct {
+	Warehouse string `json:"warehouse"`
+	Name      string `json:"name"`
+	Active    bool   `json:"active"`
+	OnHand    int    `json:"on_hand"`
+	Reserved  int    `json:"reserved"`
+	Available int    `json:"available"`
+}
+
+// HandleGetAvailability handles GET /catalog/{code}/availability?warehouse=
+func (h *CatalogHandler) HandleGetAvailability(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	warehouse := strings.TrimSpace(r.URL.Query().Get("warehouse"))
+
+	slog.Info("Fetching product availability", "code", code, "warehouse", warehouse)
+
+	product, err := h.repo.GetProductByCode(code)
+	if err != nil {
+		writeProductError(w, code, err)
+		return
+	}
+
+	skus := make([]string, len(product.Variants))
+	for i, v := range product.Variants {
+		skus[i] = v.SKU
+	}
+
+	levels, err := h.repo.GetStockLevels(skus, warehouse)
+	if err != nil {
+		if errors.Is(err, models.ErrWarehouseNotFound) {
+			slog.Warn("Warehouse not found", "code", code, "warehouse", warehouse)
+			api.ErrorResponse(w, http.StatusNotFound, "Warehouse not found")
+			return
+		}
+		slog.Error("Failed to fetch stock levels", "code", code, "error", err)
+		api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	api.OKResponse(w, mapAvailabilityResponse(product, levels, warehouse))
+}
+
+// mapAvailabilityResponse groups stock levels by variant. When a warehouse
+// is requested its levels are reported as is, even if it is inactive.
+func mapAvailabilityResponse(product *models.Product, levels []models.StockLevel, warehouse string) AvailabilityResponse {
+	bySKU := make(map[string][]models.StockLevel, len(product.Variants))
+	for _, level := range levels {
+		bySKU[level.SKU] = append(bySKU[level.SKU], level)
+	}
+
+	response := AvailabilityResponse{
+		Code:      product.Code,
+		Warehouse: warehouse,
+		Variants:  make([]VariantAvailabilityResponse, len(product.Variants)),
+	}
+	for i, v := range product.Variants {
+		variantLevels := bySKU[v.SKU]
+
+		available := 0
+		if warehouse != "" {
+			for j := range variantLevels {
+				available += variantLevels[j].Available()
+			}
+		} else {
+			total := models.AggregateStock(v.SKU, variantLevels)
+			available = total.Available()
+		}
+
+		warehouses := make([]WarehouseStockResponse, len(variantLevels))
+		for j := range variantLevels {
+			warehouses[j] = mapWarehouseStockResponse(&variantLevels[j])
+		}
+
+		response.Variants[i] = VariantAvailabilityResponse{
+			SKU:        v.SKU,
+			Name:       v.Name,
+			Available:  available,
+			InStock:    available > 0,
+			Warehouses: warehouses,
+		}
+		response.Available += available
+	}
+	response.InStock = response.Available > 0
+
+	return response
+}
+
+// mapWarehouseStockResponse maps a stock level with its warehouse to its response
+func mapWarehouseStockResponse(level *models.StockLevel) WarehouseStockResponse {
+	response := WarehouseStockResponse{
+		OnHand:    level.OnHand,
+		Reserved:  level.Reserved,
+		Available: level.Available(),
+	}
+	if level.Warehouse != nil {
+		response.Warehouse = level.Warehouse.Code
+		response.Name = level.Warehouse.Name
+		response.Active = level.Warehouse.Active
+	}
+	return response
+}
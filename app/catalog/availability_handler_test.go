@@ -0,0 +1,90 @@
+package catalog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapAvailabilityResponse(t *testing.T) {
+	product := &models.Product{
+		Code:     "PROD",
+		Variants: []models.Variant{{Name: "Small", SKU: "S"}, {Name: "Large", SKU: "L"}},
+	}
+	muc := &models.Warehouse{Code: "MUC", Active: true}
+	nyc := &models.Warehouse{Code: "NYC", Active: false}
+	levels := []models.StockLevel{
+		{SKU: "S", Warehouse: muc, OnHand: 5, Reserved: 1},
+		{SKU: "S", Warehouse: nyc, OnHand: 3},
+		{SKU: "L", Warehouse: nyc, OnHand: 2},
+	}
+
+	t.Run("totals only count active warehouses", func(t *testing.T) {
+		response := mapAvailabilityResponse(product, levels, "")
+
+		assert.Equal(t, 4, response.Available)
+		assert.True(t, response.InStock)
+		assert.Equal(t, 4, response.Variants[0].Available)
+		assert.Len(t, response.Variants[0].Warehouses, 2)
+		assert.Equal(t, 0, response.Variants[1].Available)
+		assert.False(t, response.Variants[1].InStock)
+	})
+
+	t.Run("a requested warehouse is reported even when inactive", func(t *testing.T) {
+		response := mapAvailabilityResponse(product, []models.StockLevel{levels[1], levels[2]}, "NYC")
+
+		assert.Equal(t, "NYC", response.Warehouse)
+		assert.Equal(t, 5, response.Available)
+		assert.Equal(t, 2, response.Variants[1].Available)
+	})
+}
+
+func TestAvailabilityEndpoint(t *testing.T) {
+	mux, _ := setupTestServer()
+
+	t.Run("GET /catalog/{code}/availability returns the warehouse breakdown", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog/PROD001/availability", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response AvailabilityResponse
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.Equal(t, "PROD001", response.Code)
+		assert.NotEmpty(t, response.Variants)
+		for _, variant := range response.Variants {
+			assert.NotNil(t, variant.Warehouses)
+		}
+	})
+
+	t.Run("GET /catalog/{code}/availability?warehouse= filters to one warehouse", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog/PROD001/availability?warehouse=LEJ", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response AvailabilityResponse
+		json.NewDecoder(w.Body).Decode(&response)
+		assert.Equal(t, "LEJ", response.Warehouse)
+		for _, variant := range response.Variants {
+			for _, level := range variant.Warehouses {
+				assert.Equal(t, "LEJ", level.Warehouse)
+			}
+		}
+	})
+
+	t.Run("returns 404 for unknown warehouse or product", func(t *testing.T) {
+		for _, target := range []string{"/catalog/PROD001/availability?warehouse=NOPE", "/catalog/NOPE/availability"} {
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code, target)
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+// invalidSortMessage lists the sort keys whitelisted by the repository
+const invalidSortMessage = "Invalid sort: must be one of price, code, created_at, variant_count, optionally prefixed with -"
+
+// maxSearchQueryLength bounds the q parameter of full-text searches
+const maxSearchQueryLength = 200
+
+// attributeParamPrefix prefixes attribute filters such as ?attr.color=black
+const attributeParamPrefix = "attr."
+
+// maxAttributeFilters bounds the number of attr.<code> parameters of a request
+const maxAttributeFilters = 10
+
+// parseProductFilters builds repository filters from the catalog query
+// parameters. The returned error message is safe to send to the client.
+func parseProductFilters(r *http.Request) (models.ProductFilters, error) {
+	query := r.URL.Query()
+	offset, limit := api.ParsePagination(r)
+
+	filters := models.ProductFilters{
+		Offset:        offset,
+		Limit:         limit,
+		CategoryCodes: parseListParam(query, "category"),
+		Query:         strings.TrimSpace(query.Get("q")),
+		Sort:          query.Get("sort"),
+	}
+
+	if len(filters.Query) > maxSearchQueryLength {
+		return filters, fmt.Errorf("Search query too long: maximum %d characters", maxSearchQueryLength)
+	}
+
+	var err error
+	if filters.PriceLessThan, err = parsePriceParam(query, "priceLessThan"); err != nil {
+		return filters, err
+	}
+	if filters.PriceGreaterThan, err = parsePriceParam(query, "priceGreaterThan"); err != nil {
+		return filters, err
+	}
+	if filters.PriceInclusive, err = parseBoolParam(query, "priceInclusive"); err != nil {
+		return filters, err
+	}
+	if filters.IncludeSubcategories, err = parseBoolParam(query, "includeSubcategories"); err != nil {
+		return filters, err
+	}
+
+	if lt, gt := filters.PriceLessThan, filters.PriceGreaterThan; lt != nil && gt != nil {
+		if gt.GreaterThan(*lt) || (!filters.PriceInclusive && gt.Equal(*lt)) {
+			return filters, errors.New("Invalid price range: priceGreaterThan must be lower than priceLessThan")
+		}
+	}
+
+	switch mode := models.PriceMode(query.Get("priceMode")); mode {
+	case "", models.PriceModeProduct, models.PriceModeVariant:
+		filters.PriceMode = mode
+	default:
+		return filters, fmt.Errorf("Invalid priceMode: must be %s or %s", models.PriceModeProduct, models.PriceModeVariant)
+	}
+
+	if query.Get("hasVariants") != "" {
+		hasVariants, err := parseBoolParam(query, "hasVariants")
+		if err != nil {
+			return filters, err
+		}
+		filters.HasVariants = &hasVariants
+	}
+
+	if query.Get("inStock") != "" {
+		inStock, err := parseBoolParam(query, "inStock")
+		if err != nil {
+			return filters, err
+		}
+		filters.InStock = &inStock
+	}
+
+	if filters.Attributes, err = parseAttributeParams(query); err != nil {
+		return filters, err
+	}
+
+	return filters, nil
+}
+
+// parseFacetRequest parses ?facets=, a comma separated list of category,
+// price and attr.<code> facets
+func parseFacetRequest(query url.Values) (models.FacetRequest, error) {
+	var request models.FacetRequest
+	seen := make(map[string]bool)
+	for _, facet := range parseListParam(query, "facets") {
+		if seen[facet] {
+			continue
+		}
+		seen[facet] = true
+
+		switch code, isAttribute := strings.CutPrefix(facet, attributeParamPrefix); {
+		case facet == "category":
+			request.Categories = true
+		case facet == "price":
+			request.Price = true
+		case isAttribute && code != "" && len(code) <= 32:
+			request.Attributes = append(request.Attributes, code)
+		default:
+			return request, fmt.Errorf("Invalid facet %s: must be category, price or attr.<code>", facet)
+		}
+	}
+
+	if len(request.Attributes) > maxAttributeFilters {
+		return request, fmt.Errorf("Too many attribute facets: maximum %d", maxAttributeFilters)
+	}
+	return request, nil
+}
+
+// parseAttributeParams collects the attr.<code> parameters. Each takes a
+// comma separated list of accepted values; repeating a parameter adds values.
+func parseAttributeParams(query url.Values) (map[string][]string, error) {
+	var attributes map[string][]string
+	for key := range query {
+		code, ok := strings.CutPrefix(key, attributeParamPrefix)
+		if !ok {
+			continue
+		}
+		if code == "" || len(code) > 32 {
+			return nil, fmt.Errorf("Invalid attribute filter %s: attribute code must be 1 to 32 characters", key)
+		}
+
+		var values []string
+		for _, param := range query[key] {
+			values = append(values, splitList(param)...)
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("Invalid attribute filter %s: at least one value is required", key)
+		}
+
+		if attributes == nil {
+			attributes = make(map[string][]string)
+		}
+		attributes[code] = values
+	}
+
+	if len(attributes) > maxAttributeFilters {
+		return nil, fmt.Errorf("Too many attribute filters: maximum %d", maxAttributeFilters)
+	}
+	return attributes, nil
+}
+
+// parsePriceParam parses an optional non-negative decimal query parameter
+func parsePriceParam(query url.Values, key string) (*decimal.Decimal, error) {
+	value := query.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	price, err := decimal.NewFromString(value)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s format: must be a valid number", key)
+	}
+	if price.IsNegative() {
+		return nil, fmt.Errorf("Invalid %s: must be a positive number", key)
+	}
+	return &price, nil
+}
+
+// parseBoolParam parses an optional boolean query parameter, defaulting to false
+func parseBoolParam(query url.Values, key string) (bool, error) {
+	value := query.Get(key)
+	if value == "" {
+		return false, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("Invalid %s: must be true or false", key)
+	}
+	return parsed, nil
+}
+
+// parseListParam splits a comma separated query parameter, dropping empty items
+func parseListParam(query url.Values, key string) []string {
+	return splitList(query.Get(key))
+}
+
+// splitList splits a comma separated list, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,111 @@
+package catalog
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProductFilters(t *testing.T) {
+	t.Run("parses price range, categories and variant filters", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/catalog?category=SHOES,%20CLOTHING,&priceGreaterThan=5&priceLessThan=20&priceInclusive=true&priceMode=variant&hasVariants=false&inStock=true", nil)
+
+		filters, err := parseProductFilters(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"SHOES", "CLOTHING"}, filters.CategoryCodes)
+		assert.Equal(t, "5", filters.PriceGreaterThan.String())
+		assert.Equal(t, "20", filters.PriceLessThan.String())
+		assert.True(t, filters.PriceInclusive)
+		assert.Equal(t, models.PriceModeVariant, filters.PriceMode)
+		if assert.NotNil(t, filters.HasVariants) {
+			assert.False(t, *filters.HasVariants)
+		}
+		if assert.NotNil(t, filters.InStock) {
+			assert.True(t, *filters.InStock)
+		}
+	})
+
+	t.Run("leaves optional filters unset", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/catalog", nil)
+
+		filters, err := parseProductFilters(req)
+
+		assert.NoError(t, err)
+		assert.Empty(t, filters.CategoryCodes)
+		assert.Nil(t, filters.PriceLessThan)
+		assert.Nil(t, filters.PriceGreaterThan)
+		assert.Nil(t, filters.HasVariants)
+		assert.Nil(t, filters.InStock)
+		assert.Nil(t, filters.Attributes)
+	})
+
+	t.Run("collects attribute filters", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/catalog?attr.color=black,%20white&attr.size=42&attr.size=43", nil)
+
+		filters, err := parseProductFilters(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]string{
+			"color": {"black", "white"},
+			"size":  {"42", "43"},
+		}, filters.Attributes)
+	})
+
+	cases := map[string]string{
+		"invalid priceGreaterThan":    "priceGreaterThan=abc",
+		"negative priceGreaterThan":   "priceGreaterThan=-1",
+		"inverted price range":        "priceGreaterThan=20&priceLessThan=10",
+		"empty exclusive price range": "priceGreaterThan=10&priceLessThan=10",
+		"unknown priceMode":           "priceMode=cheapest",
+		"invalid hasVariants":         "hasVariants=maybe",
+		"invalid inStock":             "inStock=yes",
+		"empty attribute code":        "attr.=black",
+		"empty attribute value":       "attr.color=,",
+	}
+
+	for name, query := range cases {
+		t.Run("rejects "+name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/catalog?"+query, nil)
+
+			_, err := parseProductFilters(req)
+
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("accepts equal bounds when inclusive", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/catalog?priceGreaterThan=10&priceLessThan=10&priceInclusive=true", nil)
+
+		_, err := parseProductFilters(req)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestParseFacetRequest(t *testing.T) {
+	t.Run("parses category, price and attribute facets", func(t *testing.T) {
+		request, err := parseFacetRequest(url.Values{"facets": {"category, price,attr.color,attr.color"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.FacetRequest{Categories: true, Price: true, Attributes: []string{"color"}}, request)
+	})
+
+	t.Run("no facets by default", func(t *testing.T) {
+		request, err := parseFacetRequest(url.Values{})
+
+		assert.NoError(t, err)
+		assert.True(t, request.Empty())
+	})
+
+	for _, facets := range []string{"brand", "attr.", "Category"} {
+		t.Run("rejects "+facets, func(t *testing.T) {
+			_, err := parseFacetRequest(url.Values{"facets": {facets}})
+
+			assert.Error(t, err)
+		})
+	}
+}
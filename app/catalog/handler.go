@@ -4,22 +4,62 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
-	"strconv"
 
 	"github.com/mytheresa/go-hiring-challenge/app/api"
 	"github.com/mytheresa/go-hiring-challenge/models"
-	"github.com/shopspring/decimal"
 )
 
 type Response struct {
-	Products []Product `json:"products"`
-	Total    int64     `json:"total"`
+	Products   []Product       `json:"products"`
+	Currency   string          `json:"currency"`
+	Total      int64           `json:"total"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	PrevCursor string          `json:"prev_cursor,omitempty"`
+	Facets     *FacetsResponse `json:"facets,omitempty"`
 }
 
+// FacetsResponse holds the facets requested with ?facets=, computed over all
+// products matching the listing filters rather than the current page.
+// Attributes is keyed by attribute code.
+type FacetsResponse struct {
+	Category   []CategoryFacet         `json:"category,omitempty"`
+	Price      []PriceBucket           `json:"price,omitempty"`
+	Attributes map[string][]FacetValue `json:"attributes,omitempty"`
+}
+
+// CategoryFacet counts the matching products of a category
+type CategoryFacet struct {
+	Code  string `json:"code"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// PriceBucket counts the matching products priced from From up to, but
+// excluding, To. Bounds are in the base currency, like the price filters,
+// and rendered in the price format of the response.
+type PriceBucket struct {
+	From  Price `json:"from"`
+	To    Price `json:"to"`
+	Count int64 `json:"count"`
+}
+
+// FacetValue counts the matching products having a variant with an option value
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// Product represents a product in a listing. Price is the current price:
+// SalePrice while a scheduled sale is active, OriginalPrice otherwise,
+// discounted by the listed Promotions.
 type Product struct {
-	Code     string   `json:"code"`
-	Price    float64  `json:"price"`
-	Category Category `json:"category"`
+	Code          string             `json:"code"`
+	Price         Price              `json:"price"`
+	OriginalPrice Price              `json:"original_price"`
+	SalePrice     *Price             `json:"sale_price,omitempty"`
+	Promotions    []AppliedPromotion `json:"promotions,omitempty"`
+	Tax           *TaxAmounts        `json:"tax,omitempty"`
+	Category      Category           `json:"category"`
 }
 
 type Category struct {
@@ -27,115 +67,283 @@ type Category struct {
 	Name string `json:"name"`
 }
 
-// ProductDetailsResponse represents a single product with full details
+// ProductDetailsResponse represents a single product with full details.
+// Options is the option matrix of the variants, one axis per attribute.
 type ProductDetailsResponse struct {
-	Code     string            `json:"code"`
-	Price    float64           `json:"price"`
-	Category Category          `json:"category"`
-	Variants []VariantResponse `json:"variants"`
+	Code          string             `json:"code"`
+	Price         Price              `json:"price"`
+	OriginalPrice Price              `json:"original_price"`
+	SalePrice     *Price             `json:"sale_price,omitempty"`
+	Promotions    []AppliedPromotion `json:"promotions,omitempty"`
+	Tax           *TaxAmounts        `json:"tax,omitempty"`
+	TaxClass      string             `json:"tax_class,omitempty"`
+	Currency      string             `json:"currency"`
+	Category      Category           `json:"category"`
+	Breadcrumb    []Category         `json:"breadcrumb,omitempty"`
+	Options       []OptionAxis       `json:"options,omitempty"`
+	Variants      []VariantResponse  `json:"variants"`
+}
+
+// OptionAxis is an attribute such as size along which the variants of a
+// product differ, with the values the variants take
+type OptionAxis struct {
+	Code   string        `json:"code"`
+	Name   string        `json:"name"`
+	Type   string        `json:"type"`
+	Values []OptionValue `json:"values"`
+}
+
+// OptionValue is a value of an option axis and the SKUs of the variants
+// having it
+type OptionValue struct {
+	Value string   `json:"value"`
+	SKUs  []string `json:"skus"`
 }
 
-// VariantResponse represents a product variant
+// VariantResponse represents a product variant with its stock availability.
+// Prices follow the same rules as Product.
 type VariantResponse struct {
-	Name  string  `json:"name"`
-	SKU   string  `json:"sku"`
-	Price float64 `json:"price"`
+	Name          string             `json:"name"`
+	SKU           string             `json:"sku"`
+	Price         Price              `json:"price"`
+	OriginalPrice Price              `json:"original_price"`
+	SalePrice     *Price             `json:"sale_price,omitempty"`
+	Promotions    []AppliedPromotion `json:"promotions,omitempty"`
+	Tax           *TaxAmounts        `json:"tax,omitempty"`
+	Options       map[string]string  `json:"options,omitempty"`
+	Available     int                `json:"available"`
+	InStock       bool               `json:"in_stock"`
 }
 
 type CatalogHandler struct {
-	repo *models.ProductsRepository
+	repo       *models.ProductsRepository
+	currencies *models.CurrenciesRepository
+	taxes      *models.TaxesRepository
+	cursors    *api.CursorCodec
+
+	// defaultCountry is the VAT country of quotes without ?country=
+	defaultCountry string
 }
 
-func NewCatalogHandler(r *models.ProductsRepository) *CatalogHandler {
+// NewCatalogHandler creates the catalog handler. Quotes without ?country=
+// report the VAT of defaultCountry; an empty defaultCountry reports no tax.
+func NewCatalogHandler(r *models.ProductsRepository, currencies *models.CurrenciesRepository, taxes *models.TaxesRepository, cursors *api.CursorCodec, defaultCountry string) *CatalogHandler {
 	return &CatalogHandler{
-		repo: r,
+		repo:           r,
+		currencies:     currencies,
+		taxes:          taxes,
+		cursors:        cursors,
+		defaultCountry: defaultCountry,
 	}
 }
 
 func (h *CatalogHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
-	// Parse query parameters
-	offset := parseIntParam(r, "offset", 0)
-	limit := parseIntParam(r, "limit", 10)
+	filters, err := parseProductFilters(r)
+	if err != nil {
+		slog.Warn("Invalid catalog query parameters", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slog.Info("Fetching catalog products",
+		"offset", filters.Offset,
+		"limit", filters.Limit,
+		"category", filters.CategoryCodes,
+		"includeSubcategories", filters.IncludeSubcategories,
+		"priceLessThan", filters.PriceLessThan,
+		"priceGreaterThan", filters.PriceGreaterThan,
+		"priceInclusive", filters.PriceInclusive,
+		"priceMode", filters.PriceMode,
+		"hasVariants", filters.HasVariants,
+		"inStock", filters.InStock,
+		"attributes", filters.Attributes,
+		"sort", filters.Sort)
+
+	h.list(w, r, filters)
+}
+
+// HandleSearch handles GET /catalog/search?q= - full-text search ranked by
+// relevance. It accepts the same filters and pagination as HandleGet.
+func (h *CatalogHandler) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	filters, err := parseProductFilters(r)
+	if err != nil {
+		slog.Warn("Invalid search query parameters", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filters.Query == "" {
+		slog.Warn("Search query missing in request")
+		api.ErrorResponse(w, http.StatusBadRequest, "Search query q is required")
+		return
+	}
+
+	slog.Info("Searching catalog products",
+		"q", filters.Query,
+		"offset", filters.Offset,
+		"limit", filters.Limit)
+
+	h.list(w, r, filters)
+}
+
+// list fetches the products matching filters and writes the listing response.
+// Keyset pagination is used when the request carries an after/before cursor
+// or pagination=cursor; otherwise the offset/limit pagination applies.
+// Prices are served in ?currency=, while price filters and sorting keep
+// working on the stored base currency prices. ?facets= adds aggregations
+// over all matching products.
+func (h *CatalogHandler) list(w http.ResponseWriter, r *http.Request, filters models.ProductFilters) {
+	pricing, ok := RequestPricing(w, r, h.currencies, h.taxes)
+	if !ok {
+		return
+	}
 
-	// Validate and normalize limit (min: 1, max: 100)
-	if limit < 1 {
-		limit = 1
+	facetRequest, err := parseFacetRequest(r.URL.Query())
+	if err != nil {
+		slog.Warn("Invalid facets parameter", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
 	}
-	if limit > 100 {
-		limit = 100
+
+	cursorMode, err := h.parseCursors(r, &filters)
+	if err != nil {
+		slog.Warn("Invalid cursor parameter", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	// Parse filter parameters
-	categoryCode := r.URL.Query().Get("category")
-	var priceLessThan *decimal.Decimal
-	if priceStr := r.URL.Query().Get("priceLessThan"); priceStr != "" {
-		price, err := decimal.NewFromString(priceStr)
+	var response Response
+	if cursorMode {
+		page, err := h.repo.GetProductsPage(filters)
 		if err != nil {
-			slog.Warn("Invalid priceLessThan parameter",
-				"error", err,
-				"value", priceStr)
-			api.ErrorResponse(w, http.StatusBadRequest, "Invalid priceLessThan format: must be a valid number")
+			writeListError(w, filters, err)
 			return
 		}
-		if price.IsNegative() {
-			slog.Warn("Negative priceLessThan parameter",
-				"value", price)
-			api.ErrorResponse(w, http.StatusBadRequest, "Invalid priceLessThan: must be a positive number")
+
+		response = MapProductsResponse(page.Products, page.Total, pricing)
+		if response.NextCursor, err = h.encodeCursor(page.Next); err == nil {
+			response.PrevCursor, err = h.encodeCursor(page.Prev)
+		}
+		if err != nil {
+			slog.Error("Failed to encode cursor", "error", err)
+			api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
 			return
 		}
-		priceLessThan = &price
-	}
-
-	// Build filters
-	filters := models.ProductFilters{
-		Offset:        offset,
-		Limit:         limit,
-		CategoryCode:  categoryCode,
-		PriceLessThan: priceLessThan,
+	} else {
+		products, total, err := h.repo.GetProductsWithFilters(filters)
+		if err != nil {
+			writeListError(w, filters, err)
+			return
+		}
+		response = MapProductsResponse(products, total, pricing)
 	}
 
-	slog.Info("Fetching catalog products",
-		"offset", offset,
-		"limit", limit,
-		"category", categoryCode,
-		"priceLessThan", priceLessThan)
-
-	// Fetch products with filters
-	products, total, err := h.repo.GetProductsWithFilters(filters)
-	if err != nil {
-		slog.Error("Failed to fetch products",
-			"error", err,
-			"filters", filters)
-		api.ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
+	if !facetRequest.Empty() {
+		facets, err := h.repo.GetProductFacets(filters, facetRequest)
+		if err != nil {
+			writeListError(w, filters, err)
+			return
+		}
+		response.Facets = mapFacetsResponse(facets, pricing.Format)
 	}
 
 	slog.Info("Successfully fetched catalog products",
-		"count", len(products),
-		"total", total)
+		"count", len(response.Products),
+		"total", response.Total,
+		"cursor", cursorMode)
 
-	// Map response
-	response := mapProductsResponse(products, total)
 	api.OKResponse(w, response)
 }
 
-// parseIntParam parses an integer query parameter with a default value
-func parseIntParam(r *http.Request, key string, defaultValue int) int {
-	if valueStr := r.URL.Query().Get(key); valueStr != "" {
-		if value, err := strconv.Atoi(valueStr); err == nil {
-			return value
+// mapFacetsResponse maps repository facets to their API response
+func mapFacetsResponse(facets *models.Facets, format PriceFormat) *FacetsResponse {
+	base := Pricing{Format: format}
+	response := &FacetsResponse{}
+	for _, c := range facets.Categories {
+		response.Category = append(response.Category, CategoryFacet{Code: c.Code, Name: c.Name, Count: c.Count})
+	}
+	for _, b := range facets.Price {
+		response.Price = append(response.Price, PriceBucket{From: base.price(b.From), To: base.price(b.To), Count: b.Count})
+	}
+	if len(facets.Attributes) > 0 {
+		response.Attributes = make(map[string][]FacetValue, len(facets.Attributes))
+	}
+	for code, values := range facets.Attributes {
+		mapped := make([]FacetValue, len(values))
+		for i, v := range values {
+			mapped[i] = FacetValue{Value: v.Value, Count: v.Count}
 		}
+		response.Attributes[code] = mapped
+	}
+	return response
+}
+
+// parseCursors decodes the after/before tokens into filters and reports
+// whether keyset pagination was requested.
+func (h *CatalogHandler) parseCursors(r *http.Request, filters *models.ProductFilters) (bool, error) {
+	query := r.URL.Query()
+	after, before := query.Get("after"), query.Get("before")
+
+	switch pagination := query.Get("pagination"); {
+	case after != "" && before != "":
+		return false, errors.New("Invalid cursor: after and before cannot be combined")
+	case pagination != "" && pagination != "offset" && pagination != "cursor":
+		return false, errors.New("Invalid pagination: must be offset or cursor")
+	case after == "" && before == "":
+		return pagination == "cursor", nil
+	}
+
+	token, target := after, &filters.After
+	if before != "" {
+		token, target = before, &filters.Before
+	}
+
+	var cursor models.Cursor
+	if err := h.cursors.Decode(token, &cursor); err != nil {
+		return false, errors.New("Invalid cursor: token is malformed or has been tampered with")
+	}
+	*target = &cursor
+	return true, nil
+}
+
+// encodeCursor turns a cursor into an opaque token; nil yields an empty token
+func (h *CatalogHandler) encodeCursor(cursor *models.Cursor) (string, error) {
+	if cursor == nil {
+		return "", nil
+	}
+	return h.cursors.Encode(cursor)
+}
+
+// writeListError maps repository errors of product listings to HTTP responses
+func writeListError(w http.ResponseWriter, filters models.ProductFilters, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidSort):
+		slog.Warn("Invalid sort parameter", "value", filters.Sort)
+		api.ErrorResponse(w, http.StatusBadRequest, invalidSortMessage)
+	case errors.Is(err, models.ErrInvalidCursor):
+		slog.Warn("Invalid cursor for listing", "sort", filters.Sort, "q", filters.Query)
+		api.ErrorResponse(w, http.StatusBadRequest,
+			"Invalid cursor: it does not match the requested sort and filters, or search results are not sorted explicitly")
+	default:
+		slog.Error("Failed to fetch products",
+			"error", err,
+			"filters", filters)
+		api.ErrorResponse(w, http.StatusInternalServerError, err.Error())
 	}
-	return defaultValue
 }
 
-// mapProductsResponse maps domain models to API response
-func mapProductsResponse(products []models.Product, total int64) Response {
+// MapProductsResponse maps domain models to API response, rendering prices with pricing
+func MapProductsResponse(products []models.Product, total int64, pricing Pricing) Response {
 	responseProducts := make([]Product, len(products))
-	for i, p := range products {
+	for i := range products {
+		p := &products[i]
+		prices := pricing.product(p)
 		responseProducts[i] = Product{
-			Code:  p.Code,
-			Price: p.Price.InexactFloat64(),
+			Code:          p.Code,
+			Price:         prices.current,
+			OriginalPrice: prices.original,
+			SalePrice:     prices.sale,
+			Promotions:    prices.promotions,
+			Tax:           prices.tax,
 			Category: Category{
 				Code: p.Category.Code,
 				Name: p.Category.Name,
@@ -145,6 +353,7 @@ func mapProductsResponse(products []models.Product, total int64) Response {
 
 	return Response{
 		Products: responseProducts,
+		Currency: pricing.CurrencyCode(),
 		Total:    total,
 	}
 }
@@ -159,7 +368,12 @@ func (h *CatalogHandler) HandleGetDetails(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	slog.Info("Fetching product details", "code", code)
+	pricing, ok := RequestPricing(w, r, h.currencies, h.taxes)
+	if !ok {
+		return
+	}
+
+	slog.Info("Fetching product details", "code", code, "currency", pricing.CurrencyCode())
 
 	// Fetch product by code from repository
 	product, err := h.repo.GetProductByCode(code)
@@ -182,38 +396,61 @@ func (h *CatalogHandler) HandleGetDetails(w http.ResponseWriter, r *http.Request
 		"code", code,
 		"variantCount", len(product.Variants))
 
+	path, err := h.repo.GetCategoryPath(product.CategoryID)
+	if err != nil {
+		slog.Error("Failed to fetch category path",
+			"code", code,
+			"error", err)
+		api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
 	// Map to response with variant price inheritance
-	response := mapProductDetailsResponse(product)
+	response := mapProductDetailsResponse(product, pricing)
+	response.Breadcrumb = mapBreadcrumb(path)
 	api.OKResponse(w, response)
 }
 
 // mapProductDetailsResponse maps product model to details response
 // Implements variant price inheritance: variants with zero/null price inherit from product
-func mapProductDetailsResponse(product *models.Product) ProductDetailsResponse {
+func mapProductDetailsResponse(product *models.Product, pricing Pricing) ProductDetailsResponse {
 	variants := make([]VariantResponse, len(product.Variants))
 
-	for i, v := range product.Variants {
-		price := v.Price
-
+	for i := range product.Variants {
 		// Price inheritance logic: if variant price is zero (NULL in DB), inherit from product
-		if price.IsZero() {
-			price = product.Price
-		}
-
-		variants[i] = VariantResponse{
-			Name:  v.Name,
-			SKU:   v.SKU,
-			Price: price.InexactFloat64(),
-		}
+		variants[i] = mapVariantResponse(product, &product.Variants[i], pricing)
 	}
 
-	return ProductDetailsResponse{
-		Code:  product.Code,
-		Price: product.Price.InexactFloat64(),
+	prices := pricing.product(product)
+	response := ProductDetailsResponse{
+		Code:          product.Code,
+		Price:         prices.current,
+		OriginalPrice: prices.original,
+		SalePrice:     prices.sale,
+		Promotions:    prices.promotions,
+		Tax:           prices.tax,
+		Currency:      pricing.CurrencyCode(),
 		Category: Category{
 			Code: product.Category.Code,
 			Name: product.Category.Name,
 		},
+		Options:  mapOptionMatrix(product),
 		Variants: variants,
 	}
+	if product.EffectiveTaxClass != nil {
+		response.TaxClass = product.EffectiveTaxClass.Code
+	}
+	return response
+}
+
+// mapBreadcrumb maps a category path (root first) to response categories
+func mapBreadcrumb(path []models.Category) []Category {
+	breadcrumb := make([]Category, len(path))
+	for i, c := range path {
+		breadcrumb[i] = Category{
+			Code: c.Code,
+			Name: c.Name,
+		}
+	}
+	return breadcrumb
 }
@@ -6,8 +6,10 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/mytheresa/go-hiring-challenge/app/api"
 	"github.com/mytheresa/go-hiring-challenge/internal/testutil"
 	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 )
@@ -16,11 +18,28 @@ func setupTestServer() (*http.ServeMux, *gorm.DB) {
 	db := testutil.SetupTestDB()
 
 	repo := models.NewProductsRepository(db)
-	handler := NewCatalogHandler(repo)
+	handler := NewCatalogHandler(repo, models.NewCurrenciesRepository(db), models.NewTaxesRepository(db), api.NewCursorCodec([]byte("test-secret")), "DE")
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /catalog", handler.HandleGet)
+	mux.HandleFunc("POST /catalog", handler.HandleCreate)
+	mux.HandleFunc("GET /catalog/search", handler.HandleSearch)
 	mux.HandleFunc("GET /catalog/{code}", handler.HandleGetDetails)
+	mux.HandleFunc("PUT /catalog/{code}", handler.HandleReplace)
+	mux.HandleFunc("PATCH /catalog/{code}", handler.HandlePatch)
+	mux.HandleFunc("DELETE /catalog/{code}", handler.HandleDelete)
+	mux.HandleFunc("GET /catalog/{code}/availability", handler.HandleGetAvailability)
+	mux.HandleFunc("GET /catalog/{code}/price-history", handler.HandleGetPriceHistory)
+	mux.HandleFunc("GET /catalog/{code}/scheduled-prices", handler.HandleListScheduledPrices)
+	mux.HandleFunc("POST /catalog/{code}/scheduled-prices", handler.HandleCreateScheduledPrice)
+	mux.HandleFunc("DELETE /catalog/{code}/scheduled-prices/{id}", handler.HandleDeleteScheduledPrice)
+	mux.HandleFunc("GET /catalog/{code}/variants", handler.HandleListVariants)
+	mux.HandleFunc("POST /catalog/{code}/variants", handler.HandleCreateVariant)
+	mux.HandleFunc("GET /catalog/{code}/variants/{sku}", handler.HandleGetVariant)
+	mux.HandleFunc("PATCH /catalog/{code}/variants/{sku}", handler.HandlePatchVariant)
+	mux.HandleFunc("DELETE /catalog/{code}/variants/{sku}", handler.HandleDeleteVariant)
+	mux.HandleFunc("POST /quotes", handler.HandleQuote)
+	mux.HandleFunc("POST /imports", handler.HandleImport)
 
 	return mux, db
 }
@@ -49,7 +68,7 @@ func TestCatalogEndpoint_DefaultPagination(t *testing.T) {
 		if len(response.Products) > 0 {
 			product := response.Products[0]
 			assert.NotEmpty(t, product.Code, "Product should have code")
-			assert.Greater(t, product.Price, 0.0, "Product should have price")
+			assert.Greater(t, product.Price.Float(), 0.0, "Product should have price")
 			assert.NotEmpty(t, product.Category.Code, "Product should have category code")
 			assert.NotEmpty(t, product.Category.Name, "Product should have category name")
 		}
@@ -224,7 +243,7 @@ func TestCatalogEndpoint_PriceFilter(t *testing.T) {
 
 		// Verify all returned products are less than $15
 		for _, product := range response.Products {
-			assert.Less(t, product.Price, 15.00, "All products should be less than $15")
+			assert.Less(t, product.Price.Float(), 15.00, "All products should be less than $15")
 		}
 	})
 
@@ -291,7 +310,7 @@ func TestCatalogEndpoint_CombinedFilters(t *testing.T) {
 		// Verify all returned products match both filters
 		for _, product := range response.Products {
 			assert.Equal(t, "SHOES", product.Category.Code, "All products should be in SHOES category")
-			assert.Less(t, product.Price, 10.00, "All products should be less than $10")
+			assert.Less(t, product.Price.Float(), 10.00, "All products should be less than $10")
 		}
 	})
 
@@ -312,7 +331,7 @@ func TestCatalogEndpoint_CombinedFilters(t *testing.T) {
 
 		for _, product := range response.Products {
 			assert.Equal(t, "CLOTHING", product.Category.Code, "All products should be in CLOTHING category")
-			assert.Less(t, product.Price, 20.00, "All products should be less than $20")
+			assert.Less(t, product.Price.Float(), 20.00, "All products should be less than $20")
 		}
 	})
 }
@@ -343,7 +362,7 @@ func TestCatalogEndpoint_ResponseFormat(t *testing.T) {
 
 			// Product fields
 			assert.NotEmpty(t, product.Code, "Product should have code")
-			assert.Greater(t, product.Price, 0.0, "Product should have price")
+			assert.Greater(t, product.Price.Float(), 0.0, "Product should have price")
 
 			// Category nested structure
 			assert.NotEmpty(t, product.Category.Code, "Product should have category code")
@@ -373,7 +392,7 @@ func TestProductDetailsEndpoint_Success(t *testing.T) {
 
 		// Verify product data
 		assert.Equal(t, "PROD001", response.Code)
-		assert.Greater(t, response.Price, 0.0, "Product should have price")
+		assert.Greater(t, response.Price.Float(), 0.0, "Product should have price")
 
 		// Verify category
 		assert.NotEmpty(t, response.Category.Code, "Product should have category code")
@@ -386,7 +405,7 @@ func TestProductDetailsEndpoint_Success(t *testing.T) {
 		for _, variant := range response.Variants {
 			assert.NotEmpty(t, variant.Name, "Variant should have name")
 			assert.NotEmpty(t, variant.SKU, "Variant should have SKU")
-			assert.Greater(t, variant.Price, 0.0, "Variant should have price")
+			assert.Greater(t, variant.Price.Float(), 0.0, "Variant should have price")
 		}
 	})
 }
@@ -433,3 +452,424 @@ func TestProductDetailsEndpoint_CategoryIncluded(t *testing.T) {
 		assert.Equal(t, "Shoes", response.Category.Name, "Category name should be Shoes")
 	})
 }
+
+func TestProductDetailsEndpoint_Breadcrumb(t *testing.T) {
+	mux, _ := setupTestServer()
+
+	t.Run("product details include category breadcrumb", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog/PROD002", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response ProductDetailsResponse
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+
+		if assert.NotEmpty(t, response.Breadcrumb) {
+			assert.Equal(t, response.Category.Code, response.Breadcrumb[len(response.Breadcrumb)-1].Code,
+				"Breadcrumb should end with the product category")
+		}
+	})
+}
+
+func TestCatalogEndpoint_IncludeSubcategories(t *testing.T) {
+	mux, _ := setupTestServer()
+
+	t.Run("GET /catalog returns 400 for invalid includeSubcategories", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog?category=SHOES&includeSubcategories=maybe", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("GET /catalog includes products of the category itself", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog?category=SHOES&includeSubcategories=true", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Response
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.Greater(t, response.Total, int64(0))
+	})
+}
+
+func TestCatalogSearchEndpoint(t *testing.T) {
+	mux, _ := setupTestServer()
+
+	t.Run("GET /catalog/search matches variant SKU", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog/search?q=SKU004B", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Response
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		if assert.Equal(t, int64(1), response.Total) {
+			assert.Equal(t, "PROD004", response.Products[0].Code)
+		}
+	})
+
+	t.Run("GET /catalog/search matches category name", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog/search?q=shoes&limit=100", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Response
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.Greater(t, response.Total, int64(0))
+		for _, p := range response.Products {
+			assert.Equal(t, "SHOES", p.Category.Code)
+		}
+	})
+
+	t.Run("GET /catalog/search returns empty result for unknown term", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog/search?q=doesnotexistanywhere", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Response
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), response.Total)
+		assert.Empty(t, response.Products)
+	})
+
+	t.Run("GET /catalog/search returns 400 without q", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog/search", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestCatalogEndpoint_Sort(t *testing.T) {
+	mux, _ := setupTestServer()
+
+	t.Run("GET /catalog?sort=-price returns products by descending price", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog?sort=-price&limit=100", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Response
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		for i := 1; i < len(response.Products); i++ {
+			assert.GreaterOrEqual(t, response.Products[i-1].Price.Float(), response.Products[i].Price.Float())
+		}
+	})
+
+	t.Run("GET /catalog returns 400 for unknown sort key", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog?sort=name", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var errorResponse map[string]string
+		err := json.NewDecoder(w.Body).Decode(&errorResponse)
+		assert.NoError(t, err)
+		assert.Contains(t, errorResponse["error"], "sort")
+	})
+}
+
+func TestCatalogEndpoint_CursorPagination(t *testing.T) {
+	mux, _ := setupTestServer()
+
+	get := func(t *testing.T, url string) (*httptest.ResponseRecorder, Response) {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		var response Response
+		if w.Code == http.StatusOK {
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		}
+		return w, response
+	}
+
+	t.Run("GET /catalog?pagination=cursor returns next cursor", func(t *testing.T) {
+		w, first := get(t, "/catalog?pagination=cursor&limit=2&sort=code")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, first.Products, 2)
+		assert.NotEmpty(t, first.NextCursor)
+		assert.Empty(t, first.PrevCursor)
+
+		w, second := get(t, "/catalog?limit=2&sort=code&after="+first.NextCursor)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEmpty(t, second.PrevCursor)
+		assert.Greater(t, second.Products[0].Code, first.Products[1].Code)
+	})
+
+	t.Run("GET /catalog returns 400 for a tampered cursor", func(t *testing.T) {
+		w, _ := get(t, "/catalog?after=eyJzIjoiIiwidiI6IjEiLCJpZCI6MX0.forged")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("GET /catalog returns 400 for a cursor of other filters", func(t *testing.T) {
+		w, first := get(t, "/catalog?pagination=cursor&limit=1&sort=code&category=SHOES")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEmpty(t, first.NextCursor)
+
+		w, _ = get(t, "/catalog?limit=1&sort=code&category=SHOES&after="+first.NextCursor)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w, _ = get(t, "/catalog?limit=1&sort=code&category=CLOTHING&after="+first.NextCursor)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("GET /catalog returns 400 when combining after and before", func(t *testing.T) {
+		w, _ := get(t, "/catalog?after=a&before=b")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("GET /catalog keeps offset pagination without cursors", func(t *testing.T) {
+		w, response := get(t, "/catalog?offset=1&limit=2")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, response.NextCursor)
+	})
+}
+
+func TestCatalogEndpoint_Currency(t *testing.T) {
+	mux, _ := setupTestServer()
+
+	t.Run("GET /catalog?currency=USD converts prices", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog?sort=code&limit=1", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		var base Response
+		json.NewDecoder(w.Body).Decode(&base)
+
+		req = httptest.NewRequest(http.MethodGet, "/catalog?sort=code&limit=1&currency=usd", nil)
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response Response
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.Equal(t, "EUR", base.Currency)
+		assert.Equal(t, "USD", response.Currency)
+		if assert.Len(t, response.Products, 1) && assert.Len(t, base.Products, 1) {
+			expected := base.Products[0].Price.Amount.Mul(decimal.RequireFromString("1.08")).Round(2)
+			assert.True(t, expected.Equal(response.Products[0].Price.Amount))
+		}
+	})
+
+	t.Run("GET /catalog rejects unsupported currency", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog?currency=XXX", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestCatalogEndpoint_Country(t *testing.T) {
+	mux, _ := setupTestServer()
+
+	t.Run("GET /catalog/{code}?country=DE splits the price into net and VAT", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog/PROD001?country=de", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response ProductDetailsResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		if assert.NotNil(t, response.Tax) {
+			assert.Equal(t, "DE", response.Tax.Country)
+			assert.Equal(t, "STANDARD", response.Tax.TaxClass)
+			assert.Equal(t, "0.19", response.Tax.Rate.String())
+			assert.True(t, response.Tax.Net.Amount.Add(response.Tax.Tax.Amount).Equal(response.Price.Amount))
+		}
+	})
+
+	t.Run("GET /catalog without a country has no tax split", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog/PROD001", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		var response ProductDetailsResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Nil(t, response.Tax)
+	})
+
+	t.Run("GET /catalog rejects unsupported country", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog?country=XX", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestCatalogEndpoint_AttributeFilter(t *testing.T) {
+	mux, _ := setupTestServer()
+
+	codes := func(t *testing.T, query string) []string {
+		req := httptest.NewRequest(http.MethodGet, "/catalog?limit=100&"+query, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response Response
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		var codes []string
+		for _, p := range response.Products {
+			codes = append(codes, p.Code)
+		}
+		return codes
+	}
+
+	t.Run("GET /catalog?attr.color=white matches any variant value", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"PROD001", "PROD004"}, codes(t, "attr.color=White"))
+	})
+
+	t.Run("GET /catalog with several attributes requires a single matching variant", func(t *testing.T) {
+		assert.Equal(t, []string{"PROD002"}, codes(t, "attr.color=black&attr.size=42"))
+		assert.Equal(t, []string{"PROD007"}, codes(t, "attr.color=black&attr.size=L"))
+	})
+
+	t.Run("GET /catalog?attr.size= accepts a list of values", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"PROD001", "PROD004", "PROD007"}, codes(t, "attr.size=S,XS"))
+	})
+
+	t.Run("GET /catalog?attr.size= compares number attributes numerically", func(t *testing.T) {
+		assert.Equal(t, []string{"PROD002"}, codes(t, "attr.size=41.0"))
+		assert.Equal(t, []string{"PROD002"}, codes(t, "attr.size=042,M&attr.color=black&category=SHOES"))
+	})
+}
+
+func TestCatalogEndpoint_Facets(t *testing.T) {
+	mux, _ := setupTestServer()
+
+	get := func(t *testing.T, query string) Response {
+		req := httptest.NewRequest(http.MethodGet, "/catalog?"+query, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response Response
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		return response
+	}
+
+	t.Run("GET /catalog without facets omits them", func(t *testing.T) {
+		assert.Nil(t, get(t, "limit=1").Facets)
+	})
+
+	t.Run("GET /catalog?facets= counts all matching products, not only the page", func(t *testing.T) {
+		response := get(t, "limit=1&category=SHOES,ACCESSORIES&facets=category,price,attr.color")
+
+		if assert.NotNil(t, response.Facets) {
+			var categories, buckets int64
+			for _, c := range response.Facets.Category {
+				assert.Contains(t, []string{"SHOES", "ACCESSORIES"}, c.Code)
+				categories += c.Count
+			}
+			for _, b := range response.Facets.Price {
+				assert.True(t, b.To.Amount.GreaterThan(b.From.Amount))
+				buckets += b.Count
+			}
+			assert.Equal(t, response.Total, categories)
+			assert.Equal(t, response.Total, buckets)
+			assert.Contains(t, response.Facets.Attributes["color"], FacetValue{Value: "black", Count: 3})
+		}
+	})
+
+	t.Run("GET /catalog?facets= follows attribute filters", func(t *testing.T) {
+		response := get(t, "attr.color=white&facets=attr.size")
+
+		if assert.NotNil(t, response.Facets) {
+			assert.ElementsMatch(t, []FacetValue{{Value: "S", Count: 2}, {Value: "M", Count: 2}, {Value: "L", Count: 1}},
+				response.Facets.Attributes["size"])
+		}
+	})
+
+	t.Run("GET /catalog rejects unknown facets", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog?facets=brand", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestMapFacetsResponse(t *testing.T) {
+	facets := &models.Facets{Price: []models.PriceBucket{
+		{From: decimal.RequireFromString("100"), To: decimal.RequireFromString("200"), Count: 3},
+	}}
+
+	t.Run("price bounds are numbers like the other v1 prices", func(t *testing.T) {
+		body, err := json.Marshal(mapFacetsResponse(facets, PriceFormatNumber))
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"price":[{"from":100,"to":200,"count":3}]}`, string(body))
+	})
+
+	t.Run("price bounds are exact amounts in the string format", func(t *testing.T) {
+		body, err := json.Marshal(mapFacetsResponse(facets, PriceFormatString))
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"price":[{"from":{"amount":"100.00","currency":"EUR"},"to":{"amount":"200.00","currency":"EUR"},"count":3}]}`, string(body))
+	})
+}
+
+func TestCatalogEndpoint_InStockFilter(t *testing.T) {
+	mux, _ := setupTestServer()
+
+	t.Run("GET /catalog?inStock=true only returns products with available variants", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog?inStock=true&limit=100", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response Response
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+
+		for _, product := range response.Products {
+			req := httptest.NewRequest(http.MethodGet, "/catalog/"+product.Code, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			var details ProductDetailsResponse
+			json.NewDecoder(w.Body).Decode(&details)
+
+			inStock := false
+			for _, variant := range details.Variants {
+				assert.Equal(t, variant.Available > 0, variant.InStock)
+				inStock = inStock || variant.InStock
+			}
+			assert.True(t, inStock, "Product %s should have a variant in stock", product.Code)
+		}
+	})
+}
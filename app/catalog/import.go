@@ -0,0 +1,285 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+// ImportColumns is the header of an import CSV. Columns may come in any
+// order. A row with empty variant columns imports the product only; an
+// empty variant_price means the variant inherits the product price.
+var ImportColumns = []string{"product_code", "price", "category", "variant_name", "sku", "variant_price"}
+
+// maxImportRows bounds the data rows of an import file
+const maxImportRows = 50000
+
+// ErrInvalidImport is returned for files that cannot be read as an import CSV
+var ErrInvalidImport = errors.New("Invalid import file")
+
+// ImportReport is the outcome of an import. Errors lists the rejected rows
+// ordered by row.
+type ImportReport struct {
+	Rows     int              `json:"rows"`
+	Products int              `json:"products"`
+	Created  int              `json:"created"`
+	Updated  int              `json:"updated"`
+	Errors   []ImportRowError `json:"errors"`
+}
+
+// ImportRowError reports a rejected row. Row is the line of the row in the
+// file, the header being line 1.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Code    string `json:"product_code,omitempty"`
+	SKU     string `json:"sku,omitempty"`
+	Message string `json:"message"`
+}
+
+// importRow is a data row of an import file
+type importRow struct {
+	line int
+	sku  string
+}
+
+// importProduct groups the rows of a product. request holds the product
+// fields of the first valid row and the variants of all valid rows.
+type importProduct struct {
+	request ProductRequest
+	rows    []importRow
+	first   int
+	invalid bool
+}
+
+// Import reads an import CSV and upserts its products, see
+// models.ProductsRepository.ImportProducts for chunkSize. Rows are
+// validated with the rules of the product write handlers and a product is
+// only imported when all its rows are valid. Rejected rows are reported,
+// not returned as errors; an import with a chunkSize of 0 writes nothing
+// when a row is rejected.
+func Import(repo models.ProductRepository, r io.Reader, chunkSize int) (*ImportReport, error) {
+	products, rows, rowErrors, err := parseImport(r)
+	if err != nil {
+		return nil, err
+	}
+	report := &ImportReport{Rows: rows, Products: len(products), Errors: append([]ImportRowError{}, rowErrors...)}
+
+	// Rows of a product with a rejected row are skipped with it
+	rejected := make(map[int]bool, len(rowErrors))
+	for _, e := range rowErrors {
+		rejected[e.Row] = true
+	}
+	var valid []*importProduct
+	for _, p := range products {
+		if !p.invalid {
+			valid = append(valid, p)
+			continue
+		}
+		for _, row := range p.rows {
+			if !rejected[row.line] {
+				report.Errors = append(report.Errors, ImportRowError{
+					Row:     row.line,
+					Code:    p.request.Code,
+					SKU:     row.sku,
+					Message: fmt.Sprintf("Skipped: another row of product %s is invalid", p.request.Code),
+				})
+			}
+		}
+	}
+
+	if len(valid) > 0 && (chunkSize > 0 || len(report.Errors) == 0) {
+		batch := make([]models.Product, len(valid))
+		for i, p := range valid {
+			batch[i] = *p.request.toModel()
+		}
+		result, err := repo.ImportProducts(batch, chunkSize)
+		if err != nil {
+			return nil, err
+		}
+		report.Created, report.Updated = result.Created, result.Updated
+
+		for i, err := range result.Errors {
+			for _, row := range valid[i].rows {
+				report.Errors = append(report.Errors, ImportRowError{
+					Row:     row.line,
+					Code:    valid[i].request.Code,
+					SKU:     row.sku,
+					Message: importErrorMessage(err),
+				})
+			}
+		}
+	}
+
+	sort.Slice(report.Errors, func(i, j int) bool {
+		return report.Errors[i].Row < report.Errors[j].Row
+	})
+	return report, nil
+}
+
+// parseImport reads the rows of an import file grouped by product code, in
+// order of first appearance, and reports the invalid rows
+func parseImport(r io.Reader) ([]*importProduct, int, []ImportRowError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, 0, nil, fmt.Errorf("%w: the file is empty", ErrInvalidImport)
+	}
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	columns, err := importColumns(header)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	var (
+		products  []*importProduct
+		rowErrors []ImportRowError
+		rows      int
+	)
+	byCode := make(map[string]*importProduct)
+	skus := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, 0, nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+		}
+		rows++
+		if rows > maxImportRows {
+			return nil, 0, nil, fmt.Errorf("%w: more than %d rows", ErrInvalidImport, maxImportRows)
+		}
+
+		field := func(name string) string {
+			return strings.TrimSpace(record[columns[name]])
+		}
+		line, _ := reader.FieldPos(0)
+		row := importRow{line: line, sku: field("sku")}
+		code := field("product_code")
+
+		product := byCode[code]
+		if product == nil && code != "" {
+			product = &importProduct{request: ProductRequest{Code: code}}
+			byCode[code] = product
+			products = append(products, product)
+		}
+
+		req, variant, err := parseImportRow(field)
+		switch {
+		case err != nil:
+		case product.first != 0 && (!req.Price.Equal(product.request.Price) || req.Category != product.request.Category):
+			err = fmt.Errorf("Price and category must match row %d of product %s", product.first, code)
+		case variant != nil && skus[variant.SKU] != 0:
+			err = fmt.Errorf("Duplicate SKU %s: already in row %d", variant.SKU, skus[variant.SKU])
+		}
+		if product != nil {
+			product.rows = append(product.rows, row)
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, ImportRowError{Row: row.line, Code: code, SKU: row.sku, Message: err.Error()})
+			if product != nil {
+				product.invalid = true
+			}
+			continue
+		}
+
+		if product.first == 0 {
+			product.first = row.line
+			product.request.Price = req.Price
+			product.request.Category = req.Category
+		}
+		if variant != nil {
+			skus[variant.SKU] = row.line
+			product.request.Variants = append(product.request.Variants, *variant)
+		}
+	}
+
+	return products, rows, rowErrors, nil
+}
+
+// importColumns maps the column names of an import header to their index
+func importColumns(header []string) (map[string]int, error) {
+	known := make(map[string]bool, len(ImportColumns))
+	for _, name := range ImportColumns {
+		known[name] = true
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// Spreadsheet exports often start with a byte order mark
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !known[name] {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidImport, name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("%w: duplicate column %q", ErrInvalidImport, name)
+		}
+		columns[name] = i
+	}
+	for _, name := range ImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", ErrInvalidImport, name)
+		}
+	}
+	return columns, nil
+}
+
+// parseImportRow validates a row with the rules of the product write
+// handlers. The variant is nil for rows without variant columns.
+func parseImportRow(field func(name string) string) (ProductRequest, *VariantRequest, error) {
+	req := ProductRequest{Code: field("product_code"), Category: field("category")}
+	if value := field("price"); value != "" {
+		price, err := decimal.NewFromString(value)
+		if err != nil {
+			return req, nil, errors.New("Invalid price: must be a number")
+		}
+		req.Price = price
+	}
+	if err := validateProductRequest(req); err != nil {
+		return req, nil, err
+	}
+
+	name, sku, price := field("variant_name"), field("sku"), field("variant_price")
+	if name == "" && sku == "" && price == "" {
+		return req, nil, nil
+	}
+
+	variant := &VariantRequest{Name: name, SKU: sku}
+	if price != "" {
+		d, err := decimal.NewFromString(price)
+		if err != nil {
+			return req, nil, errors.New("Variant: Invalid price: must be a number")
+		}
+		variant.Price = decimal.NewNullDecimal(d)
+	}
+	if err := validateVariantRequest(*variant); err != nil {
+		return req, nil, fmt.Errorf("Variant: %w", err)
+	}
+	return req, variant, nil
+}
+
+// importErrorMessage describes why the repository rejected a product
+func importErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, models.ErrCategoryNotFound):
+		return "Category not found"
+	case errors.Is(err, models.ErrProductCodeExists):
+		return "Product code already exists"
+	case errors.Is(err, models.ErrVariantSKUExists):
+		return "Variant SKU already exists on another product"
+	case errors.Is(err, models.ErrCurrencyNotFound):
+		return "Currency not found"
+	default:
+		return err.Error()
+	}
+}
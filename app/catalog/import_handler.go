@@ -0,0 +1,71 @@
+package catalog
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+)
+
+// maxImportSize bounds the size of an uploaded import request
+const maxImportSize = 32 << 20
+
+// HandleImport handles POST /imports - upserts products and variants from
+// the CSV uploaded in the multipart field "file", see ImportColumns.
+// Without ?chunk_size= the file is imported in a single transaction and a
+// rejected row fails the whole import with 422. With it every chunk_size
+// products are committed separately and rejected products are skipped.
+// The response reports the rejected rows either way.
+func (h *CatalogHandler) HandleImport(w http.ResponseWriter, r *http.Request) {
+	chunkSize := 0
+	if value := r.URL.Query().Get("chunk_size"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 1 {
+			slog.Warn("Invalid chunk_size parameter", "chunk_size", value)
+			api.ErrorResponse(w, http.StatusBadRequest, "Invalid chunk_size: must be a positive integer")
+			return
+		}
+		chunkSize = size
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			slog.Warn("Import file too large", "limit", maxImportSize)
+			api.ErrorResponse(w, http.StatusRequestEntityTooLarge, "Import file too large: maximum 32 MiB")
+			return
+		}
+		slog.Warn("Missing import file", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, "A CSV file is required in the multipart field file")
+		return
+	}
+	defer file.Close()
+
+	actor := requestActor(r)
+	slog.Info("Importing products", "actor", actor, "chunkSize", chunkSize)
+
+	report, err := Import(h.repo.WithActor(actor), file, chunkSize)
+	if err != nil {
+		if errors.Is(err, ErrInvalidImport) {
+			slog.Warn("Invalid import file", "error", err)
+			api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		slog.Error("Failed to import products", "error", err)
+		api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	slog.Info("Imported products", "rows", report.Rows, "created", report.Created,
+		"updated", report.Updated, "rejected", len(report.Errors))
+
+	if chunkSize == 0 && len(report.Errors) > 0 {
+		api.JSONResponse(w, http.StatusUnprocessableEntity, report)
+		return
+	}
+	api.OKResponse(w, report)
+}
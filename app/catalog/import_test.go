@@ -0,0 +1,191 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/testutil"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+const importHeader = "product_code,price,category,variant_name,sku,variant_price\n"
+
+func doImport(mux *http.ServeMux, query, content string) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "import.csv")
+	part.Write([]byte(content))
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/imports"+query, &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	return w
+}
+
+func TestParseImport(t *testing.T) {
+	t.Run("groups rows by product code", func(t *testing.T) {
+		products, rows, rowErrors, err := parseImport(strings.NewReader(
+			"sku, variant_name ,product_code,price,category,variant_price\n" +
+				"IMP_A_S,Small,IMP_A,10.00,SHOES,12.50\n" +
+				"IMP_B_S,Small,IMP_B,20,CLOTHING,\n" +
+				"IMP_A_L,Large,IMP_A,10,SHOES,\n" +
+				",,IMP_C,5.5,SHOES,\n"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, rows)
+		assert.Empty(t, rowErrors)
+		if assert.Len(t, products, 3) {
+			a := products[0].request
+			assert.Equal(t, "IMP_A", a.Code)
+			assert.Equal(t, "SHOES", a.Category)
+			assert.True(t, a.Price.Equal(decimal.RequireFromString("10")))
+			assert.Equal(t, []VariantRequest{
+				{Name: "Small", SKU: "IMP_A_S", Price: decimal.NewNullDecimal(decimal.RequireFromString("12.50"))},
+				{Name: "Large", SKU: "IMP_A_L"},
+			}, a.Variants)
+			assert.Equal(t, []importRow{{line: 2, sku: "IMP_A_S"}, {line: 4, sku: "IMP_A_L"}}, products[0].rows)
+			assert.Empty(t, products[2].request.Variants, "a row without variant columns imports the product only")
+		}
+	})
+
+	t.Run("reports invalid rows and marks their products", func(t *testing.T) {
+		products, _, rowErrors, err := parseImport(strings.NewReader(importHeader +
+			"IMP_A,10,SHOES,Small,IMP_A_S,\n" +
+			"IMP_A,11,SHOES,Large,IMP_A_L,\n" +
+			"IMP_B,abc,SHOES,Small,IMP_B_S,\n" +
+			"IMP_C,10,SHOES,Small,IMP_A_S,\n" +
+			"IMP_D,10,SHOES,Small,IMP_D_S,1.999\n" +
+			",10,SHOES,Small,IMP_E_S,\n" +
+			"IMP_F,10,SHOES,,IMP_F_S,\n"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []ImportRowError{
+			{Row: 3, Code: "IMP_A", SKU: "IMP_A_L", Message: "Price and category must match row 2 of product IMP_A"},
+			{Row: 4, Code: "IMP_B", SKU: "IMP_B_S", Message: "Invalid price: must be a number"},
+			{Row: 5, Code: "IMP_C", SKU: "IMP_A_S", Message: "Duplicate SKU IMP_A_S: already in row 2"},
+			{Row: 6, Code: "IMP_D", SKU: "IMP_D_S", Message: "Variant: Invalid price: at most 2 decimal places allowed"},
+			{Row: 7, SKU: "IMP_E_S", Message: "Code and category are required"},
+			{Row: 8, Code: "IMP_F", SKU: "IMP_F_S", Message: "Variant: name and SKU cannot be empty or whitespace only"},
+		}, rowErrors)
+		for _, p := range products {
+			assert.True(t, p.invalid, p.request.Code)
+		}
+	})
+
+	t.Run("rejects unreadable files", func(t *testing.T) {
+		for name, content := range map[string]string{
+			"empty file":     "",
+			"missing column": "product_code,price,category,variant_name,sku\n",
+			"unknown column": strings.TrimSuffix(importHeader, "\n") + ",color\n",
+			"wrong fields":   importHeader + "IMP_A,10,SHOES\n",
+		} {
+			_, _, _, err := parseImport(strings.NewReader(content))
+			assert.ErrorIs(t, err, ErrInvalidImport, name)
+		}
+	})
+}
+
+func TestImportEndpoint(t *testing.T) {
+	mux, db := setupTestServer()
+
+	t.Run("POST /imports creates and updates products", func(t *testing.T) {
+		testutil.CleanupProduct(t, db, "TEST_IMP_NEW")
+		testutil.CleanupProduct(t, db, "TEST_IMP_OLD")
+
+		w := testutil.DoJSON(mux, http.MethodPost, "/catalog", map[string]any{
+			"code":     "TEST_IMP_OLD",
+			"price":    "10.00",
+			"category": "SHOES",
+			"variants": []map[string]any{
+				{"name": "Small", "sku": "TEST_IMP_OLD_S"},
+				{"name": "Large", "sku": "TEST_IMP_OLD_L"},
+			},
+		})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = doImport(mux, "", importHeader+
+			"TEST_IMP_NEW,20.00,CLOTHING,Small,TEST_IMP_NEW_S,21.00\n"+
+			"TEST_IMP_NEW,20.00,CLOTHING,Large,TEST_IMP_NEW_L,\n"+
+			"TEST_IMP_OLD,15.00,CLOTHING,Small (EU),TEST_IMP_OLD_S,16.00\n")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var report ImportReport
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+		assert.Equal(t, ImportReport{Rows: 3, Products: 2, Created: 1, Updated: 1, Errors: []ImportRowError{}}, report)
+
+		repo := models.NewProductsRepository(db)
+		created, err := repo.GetProductByCode("TEST_IMP_NEW")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "CLOTHING", created.Category.Code)
+			assert.Len(t, created.Variants, 2)
+		}
+		updated, err := repo.GetProductByCode("TEST_IMP_OLD")
+		if assert.NoError(t, err) {
+			assert.True(t, updated.Price.Equal(decimal.RequireFromString("15")))
+			assert.Equal(t, "CLOTHING", updated.Category.Code)
+			assert.Len(t, updated.Variants, 2, "variants missing from the file are kept")
+			for _, v := range updated.Variants {
+				if v.SKU == "TEST_IMP_OLD_S" {
+					assert.Equal(t, "Small (EU)", v.Name)
+				}
+			}
+		}
+	})
+
+	t.Run("POST /imports writes nothing when a row is rejected", func(t *testing.T) {
+		testutil.CleanupProduct(t, db, "TEST_IMP_ATOMIC")
+
+		w := doImport(mux, "", importHeader+
+			"TEST_IMP_ATOMIC,20.00,SHOES,Small,TEST_IMP_ATOMIC_S,\n"+
+			"TEST_IMP_MISSING,20.00,NO_SUCH_CATEGORY,Small,TEST_IMP_MISSING_S,\n")
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		var report ImportReport
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+		assert.Equal(t, 0, report.Created)
+		assert.Equal(t, []ImportRowError{
+			{Row: 3, Code: "TEST_IMP_MISSING", SKU: "TEST_IMP_MISSING_S", Message: "Category not found"},
+		}, report.Errors)
+
+		_, err := models.NewProductsRepository(db).GetProductByCode("TEST_IMP_ATOMIC")
+		assert.ErrorIs(t, err, models.ErrProductNotFound)
+	})
+
+	t.Run("POST /imports?chunk_size= skips rejected products", func(t *testing.T) {
+		testutil.CleanupProduct(t, db, "TEST_IMP_CHUNK")
+
+		w := doImport(mux, "?chunk_size=1", importHeader+
+			"TEST_IMP_CHUNK,20.00,SHOES,Small,TEST_IMP_CHUNK_S,\n"+
+			"TEST_IMP_MISSING,20.00,NO_SUCH_CATEGORY,Small,TEST_IMP_MISSING_S,\n"+
+			"TEST_IMP_BAD,-1,SHOES,Small,TEST_IMP_BAD_S,\n")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var report ImportReport
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+		assert.Equal(t, 1, report.Created)
+		assert.Len(t, report.Errors, 2)
+
+		_, err := models.NewProductsRepository(db).GetProductByCode("TEST_IMP_CHUNK")
+		assert.NoError(t, err)
+	})
+
+	t.Run("POST /imports rejects invalid requests", func(t *testing.T) {
+		w := doImport(mux, "", "code,price\n")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = doImport(mux, "?chunk_size=0", importHeader)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = testutil.DoJSON(mux, http.MethodPost, "/imports", map[string]any{})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
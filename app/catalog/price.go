@@ -0,0 +1,328 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+// BaseCurrency is the currency all stored prices are expressed in
+const BaseCurrency = models.BaseCurrency
+
+// PriceFormat selects how prices are serialized in responses
+type PriceFormat int
+
+const (
+	// PriceFormatNumber serializes prices as JSON numbers (API v1 default).
+	// Clients decoding them as binary floats may see approximations.
+	PriceFormatNumber PriceFormat = iota
+	// PriceFormatString serializes prices as {"amount": "12.49", "currency": "EUR"}
+	// keeping the exact decimal value (API v2 default).
+	PriceFormatString
+)
+
+// Price is a monetary amount in a response. It keeps the exact decimal
+// amount and is rendered according to its Format.
+type Price struct {
+	Amount   decimal.Decimal
+	Currency string
+	Format   PriceFormat
+}
+
+// exactPrice is the JSON shape of PriceFormatString
+type exactPrice struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// Pricing renders the prices of a response in a currency and format.
+// A nil Currency serves the stored base currency prices. Scheduled prices
+// are resolved at At, or at the current time when At is zero. With VAT the
+// current prices are split into net amount and tax for that country.
+type Pricing struct {
+	Currency *models.Currency
+	Format   PriceFormat
+	At       time.Time
+	VAT      *models.CountryVAT
+}
+
+// AppliedPromotion is a promotion applied to a price, with the amount it took off
+type AppliedPromotion struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Discount Price  `json:"discount"`
+}
+
+// TaxAmounts splits a gross price into its net amount and the VAT of a
+// country. Rate is rendered as a JSON number in every format, see api.Rate.
+type TaxAmounts struct {
+	Country  string   `json:"country"`
+	TaxClass string   `json:"tax_class,omitempty"`
+	Rate     api.Rate `json:"rate"`
+	Net      Price    `json:"net"`
+	Tax      Price    `json:"tax"`
+	Gross    Price    `json:"gross"`
+}
+
+// priceSet holds the prices of a product or variant: the current price, the
+// regular price, the scheduled sale price when one is active, the
+// promotions applied on top of it and the VAT split of the current price
+type priceSet struct {
+	current    Price
+	original   Price
+	sale       *Price
+	promotions []AppliedPromotion
+	tax        *TaxAmounts
+}
+
+// CurrencyCode returns the code of the currency prices are served in
+func (p Pricing) CurrencyCode() string {
+	if p.Currency == nil {
+		return BaseCurrency
+	}
+	return p.Currency.Code
+}
+
+// product returns the prices of product, applying currency overrides and conversion
+func (p Pricing) product(product *models.Product) priceSet {
+	amount := product.Price
+	if p.Currency != nil {
+		amount = p.Currency.ProductPrice(product)
+	}
+	sale, onSale := product.SalePrice(p.at())
+	set := p.prices(amount, sale, onSale, product.ApplicablePromotions(p.at()))
+	set.tax = p.tax(product, set.current)
+	return set
+}
+
+// variant returns the prices of a variant of product applying price inheritance
+func (p Pricing) variant(product *models.Product, v *models.Variant) priceSet {
+	amount := v.EffectivePrice(product.Price)
+	if p.Currency != nil {
+		amount = p.Currency.VariantPrice(product, v)
+	}
+	sale, onSale := product.VariantSalePrice(v, p.at())
+	set := p.prices(amount, sale, onSale, product.ApplicablePromotions(p.at()))
+	set.tax = p.tax(product, set.current)
+	return set
+}
+
+// prices builds a price set from a regular amount in the served currency and
+// an optional base currency sale amount. Sale prices are always converted;
+// currency overrides only apply to regular prices. Promotions discount the
+// resulting current price in order.
+func (p Pricing) prices(regular, sale decimal.Decimal, onSale bool, promotions []models.Promotion) priceSet {
+	set := priceSet{original: p.price(regular)}
+	set.current = set.original
+	if onSale {
+		if p.Currency != nil {
+			sale = p.Currency.Convert(sale)
+		}
+		salePrice := p.price(sale)
+		set.current, set.sale = salePrice, &salePrice
+	}
+
+	amount := set.current.Amount
+	for i := range promotions {
+		promotion := &promotions[i]
+		fixed := promotion.Value
+		if p.Currency != nil {
+			fixed = p.Currency.Convert(fixed)
+		}
+		discounted := p.round(promotion.Apply(amount, fixed))
+		set.promotions = append(set.promotions, AppliedPromotion{
+			Code:     promotion.Code,
+			Name:     promotion.Name,
+			Discount: p.price(amount.Sub(discounted)),
+		})
+		amount = discounted
+	}
+	set.current = p.price(amount)
+	return set
+}
+
+// tax splits the gross price of product for the VAT country, if any
+func (p Pricing) tax(product *models.Product, gross Price) *TaxAmounts {
+	if p.VAT == nil {
+		return nil
+	}
+
+	rate := p.VAT.Rate(product.EffectiveTaxClass)
+	vat := models.IncludedVAT(gross.Amount, rate)
+	amounts := &TaxAmounts{
+		Country: p.VAT.Country,
+		Rate:    api.NewRate(rate),
+		Net:     p.price(gross.Amount.Sub(vat)),
+		Tax:     p.price(vat),
+		Gross:   gross,
+	}
+	if product.EffectiveTaxClass != nil {
+		amounts.TaxClass = product.EffectiveTaxClass.Code
+	}
+	return amounts
+}
+
+// round rounds a discounted amount with the rules of the served currency
+func (p Pricing) round(amount decimal.Decimal) decimal.Decimal {
+	if p.Currency != nil {
+		return p.Currency.Round(amount)
+	}
+	return amount.Round(2)
+}
+
+// price wraps an amount in the served currency and format
+func (p Pricing) price(amount decimal.Decimal) Price {
+	return Price{Amount: amount, Currency: p.CurrencyCode(), Format: p.Format}
+}
+
+// at returns the time scheduled prices are resolved at
+func (p Pricing) at() time.Time {
+	if p.At.IsZero() {
+		return time.Now()
+	}
+	return p.At
+}
+
+// Float returns the amount as a float64, as rendered by PriceFormatNumber
+func (p Price) Float() float64 {
+	return p.Amount.InexactFloat64()
+}
+
+func (p Price) MarshalJSON() ([]byte, error) {
+	if p.Format == PriceFormatString {
+		return json.Marshal(exactPrice{
+			Amount:   p.Amount.StringFixed(2),
+			Currency: p.Currency,
+		})
+	}
+	return json.Marshal(p.Amount.InexactFloat64())
+}
+
+// UnmarshalJSON accepts both formats so Go clients can decode any response
+func (p *Price) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var exact exactPrice
+		if err := json.Unmarshal(data, &exact); err != nil {
+			return err
+		}
+		amount, err := decimal.NewFromString(exact.Amount)
+		if err != nil {
+			return err
+		}
+		*p = Price{Amount: amount, Currency: exact.Currency, Format: PriceFormatString}
+		return nil
+	}
+
+	var amount decimal.Decimal
+	if err := amount.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*p = Price{Amount: amount, Currency: BaseCurrency, Format: PriceFormatNumber}
+	return nil
+}
+
+type priceFormatKey struct{}
+
+// V2 serves next with API v2 semantics: prices are always exact decimal strings
+func V2(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), priceFormatKey{}, PriceFormatString)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// ParsePriceFormat determines the price format of a request. API v2 routes
+// always use exact prices; v1 routes default to numbers and opt in with
+// ?priceFormat=string or the X-Price-Format: string header.
+func ParsePriceFormat(r *http.Request) (PriceFormat, error) {
+	if format, ok := r.Context().Value(priceFormatKey{}).(PriceFormat); ok {
+		return format, nil
+	}
+
+	value := r.URL.Query().Get("priceFormat")
+	if value == "" {
+		value = r.Header.Get("X-Price-Format")
+	}
+
+	switch value {
+	case "", "number":
+		return PriceFormatNumber, nil
+	case "string":
+		return PriceFormatString, nil
+	default:
+		return PriceFormatNumber, errors.New("Invalid priceFormat: must be number or string")
+	}
+}
+
+// RequestPricing parses the price format, ?currency= and ?country= of r,
+// writing a 400 response and reporting false when any is invalid
+func RequestPricing(w http.ResponseWriter, r *http.Request, currencies *models.CurrenciesRepository, taxes *models.TaxesRepository) (Pricing, bool) {
+	format, ok := requestPriceFormat(w, r)
+	if !ok {
+		return Pricing{}, false
+	}
+	pricing := Pricing{Format: format}
+
+	code := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("currency")))
+	if code != "" && code != BaseCurrency {
+		currency, err := currencies.GetCurrency(code)
+		if err != nil {
+			if errors.Is(err, models.ErrCurrencyNotFound) {
+				slog.Warn("Unsupported currency", "currency", code)
+				api.ErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid currency: %s is not supported", code))
+				return Pricing{}, false
+			}
+			slog.Error("Failed to fetch currency", "currency", code, "error", err)
+			api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+			return Pricing{}, false
+		}
+		pricing.Currency = currency
+	}
+
+	if country := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("country"))); country != "" {
+		if pricing.VAT, ok = countryVAT(w, taxes, country); !ok {
+			return Pricing{}, false
+		}
+	}
+
+	return pricing, true
+}
+
+// countryVAT loads the VAT rates of country, writing a 400 response and
+// reporting false when the country is not supported
+func countryVAT(w http.ResponseWriter, taxes *models.TaxesRepository, country string) (*models.CountryVAT, bool) {
+	vat, err := taxes.GetCountryVAT(country)
+	if err != nil {
+		if errors.Is(err, models.ErrCountryNotSupported) {
+			slog.Warn("Unsupported country", "country", country)
+			api.ErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid country: %s is not supported", country))
+			return nil, false
+		}
+		slog.Error("Failed to fetch VAT rates", "country", country, "error", err)
+		api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+		return nil, false
+	}
+	return vat, true
+}
+
+// requestPriceFormat parses the price format of r, writing a 400 response
+// and reporting false when it is invalid
+func requestPriceFormat(w http.ResponseWriter, r *http.Request) (PriceFormat, bool) {
+	format, err := ParsePriceFormat(r)
+	if err != nil {
+		slog.Warn("Invalid price format", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return format, false
+	}
+	return format, true
+}
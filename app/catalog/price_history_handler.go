@@ -0,0 +1,155 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+// ActorHeader carries a label of the client making a write, recorded in the
+// price history. The API has no authentication, so the label is whatever the
+// client sends: it tells changes apart but does not prove who made them.
+const ActorHeader = "X-Actor"
+
+// anonymousActor is recorded for writes without an ActorHeader
+const anonymousActor = "anonymous"
+
+// maxActorLength bounds the actor to the size of the price_changes column
+const maxActorLength = 256
+
+// historyDateLayout is accepted besides RFC 3339 by the from and to parameters
+const historyDateLayout = time.DateOnly
+
+// PriceChangeResponse represents a recorded price change. Prices are in the
+// base currency; a null old_price marks the initial price and a null variant
+// price means the variant inherited the product price. ReportedBy is the
+// unverified ActorHeader of the write, or the name of the writing command.
+type PriceChangeResponse struct {
+	SKU        string    `json:"sku,omitempty"`
+	OldPrice   *Price    `json:"old_price"`
+	NewPrice   *Price    `json:"new_price"`
+	ReportedBy string    `json:"reported_by"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+// HandleGetPriceHistory handles GET /catalog/{code}/price-history.
+// The optional from and to parameters take RFC 3339 timestamps or dates;
+// from is inclusive, to is exclusive for timestamps and covers the whole day for dates.
+func (h *CatalogHandler) HandleGetPriceHistory(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+
+	format, ok := requestPriceFormat(w, r)
+	if !ok {
+		return
+	}
+
+	filters, err := parsePriceHistoryFilters(r.URL.Query())
+	if err != nil {
+		slog.Warn("Invalid price history filters", "code", code, "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slog.Info("Fetching price history", "code", code, "from", filters.From, "to", filters.To)
+
+	changes, err := h.repo.GetPriceHistory(code, filters)
+	if err != nil {
+		if errors.Is(err, models.ErrProductNotFound) {
+			slog.Warn("Product not found", "code", code)
+			api.ErrorResponse(w, http.StatusNotFound, "Product not found")
+			return
+		}
+		slog.Error("Failed to fetch price history", "code", code, "error", err)
+		api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	pricing := Pricing{Format: format}
+	response := make([]PriceChangeResponse, len(changes))
+	for i, change := range changes {
+		response[i] = PriceChangeResponse{
+			OldPrice:   pricing.nullablePrice(change.OldPrice),
+			NewPrice:   pricing.nullablePrice(change.NewPrice),
+			ReportedBy: change.Actor,
+			ChangedAt:  change.ChangedAt,
+		}
+		if change.SKU != nil {
+			response[i].SKU = *change.SKU
+		}
+	}
+
+	api.OKResponse(w, response)
+}
+
+// nullablePrice formats a price that may be unset
+func (p Pricing) nullablePrice(amount decimal.NullDecimal) *Price {
+	if !amount.Valid {
+		return nil
+	}
+	price := p.price(amount.Decimal)
+	return &price
+}
+
+// parsePriceHistoryFilters reads the date range of the price history.
+// The returned error message is safe to send to the client.
+func parsePriceHistoryFilters(query url.Values) (models.PriceHistoryFilters, error) {
+	var filters models.PriceHistoryFilters
+	var err error
+	if filters.From, err = parseHistoryBound(query, "from", false); err != nil {
+		return filters, err
+	}
+	if filters.To, err = parseHistoryBound(query, "to", true); err != nil {
+		return filters, err
+	}
+	if !filters.From.IsZero() && !filters.To.IsZero() && !filters.To.After(filters.From) {
+		return filters, errors.New("Invalid date range: to must be after from")
+	}
+	return filters, nil
+}
+
+// parseHistoryBound parses an RFC 3339 timestamp or a date. A date used as
+// the upper bound is moved to the end of that day.
+func parseHistoryBound(query url.Values, name string, upper bool) (time.Time, error) {
+	value := strings.TrimSpace(query.Get(name))
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(historyDateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid %s: must be an RFC 3339 timestamp or a YYYY-MM-DD date", name)
+	}
+	if upper {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// requestActor returns the client supplied ActorHeader of a write. It is an
+// untrusted label and must not be used for authorization.
+func requestActor(r *http.Request) string {
+	actor := strings.TrimSpace(r.Header.Get(ActorHeader))
+	if actor == "" {
+		return anonymousActor
+	}
+	if len(actor) > maxActorLength {
+		// Cut on a rune boundary, the database rejects invalid UTF-8
+		cut := maxActorLength
+		for cut > 0 && !utf8.RuneStart(actor[cut]) {
+			cut--
+		}
+		actor = actor[:cut]
+	}
+	return actor
+}
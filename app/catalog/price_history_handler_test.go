@@ -0,0 +1,158 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/testutil"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePriceHistoryFilters(t *testing.T) {
+	t.Run("dates cover whole days", func(t *testing.T) {
+		filters, err := parsePriceHistoryFilters(url.Values{"from": {"2025-01-01"}, "to": {"2025-01-31"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), filters.From)
+		assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), filters.To)
+	})
+
+	t.Run("timestamps are used as given", func(t *testing.T) {
+		filters, err := parsePriceHistoryFilters(url.Values{"to": {"2025-01-31T10:00:00+01:00"}})
+
+		assert.NoError(t, err)
+		assert.True(t, filters.From.IsZero())
+		assert.True(t, time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC).Equal(filters.To))
+	})
+
+	t.Run("rejects invalid values and ranges", func(t *testing.T) {
+		for _, query := range []url.Values{
+			{"from": {"yesterday"}},
+			{"to": {"2025-13-01"}},
+			{"from": {"2025-02-01"}, "to": {"2025-01-01"}},
+		} {
+			_, err := parsePriceHistoryFilters(query)
+			assert.Error(t, err, query)
+		}
+	})
+}
+
+func TestRequestActor(t *testing.T) {
+	request := func(actor string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/catalog", nil)
+		req.Header.Set(ActorHeader, actor)
+		return req
+	}
+
+	assert.Equal(t, anonymousActor, requestActor(request("  ")))
+	assert.Equal(t, "merchandiser@example.com", requestActor(request(" merchandiser@example.com ")))
+
+	t.Run("truncates long actors on a rune boundary", func(t *testing.T) {
+		// 'ü' takes 2 bytes, so byte 256 falls in the middle of a rune
+		actor := requestActor(request("x" + strings.Repeat("ü", 200)))
+
+		assert.True(t, utf8.ValidString(actor))
+		assert.Equal(t, "x"+strings.Repeat("ü", 127), actor)
+	})
+}
+
+func TestPriceHistoryEndpoint(t *testing.T) {
+	mux, db := setupTestServer()
+
+	testutil.CleanupProduct(t, db, "TEST_PROD_HISTORY")
+	// The history outlives the product, so earlier runs leave theirs behind
+	db.Where("product_code = ?", "TEST_PROD_HISTORY").Delete(&models.PriceChange{})
+	t.Cleanup(func() {
+		db.Where("product_code = ?", "TEST_PROD_HISTORY").Delete(&models.PriceChange{})
+	})
+	w := testutil.DoJSON(mux, http.MethodPost, "/catalog", map[string]any{
+		"code":     "TEST_PROD_HISTORY",
+		"price":    "20.00",
+		"category": "CLOTHING",
+		"variants": []map[string]any{{"name": "A", "sku": "TEST_PROD_HISTORY_A"}},
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	patch := func(path, actor string, body map[string]any) {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPatch, path, bytes.NewBuffer(payload))
+		req.Header.Set(ActorHeader, actor)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	patch("/catalog/TEST_PROD_HISTORY", "merchandiser@example.com", map[string]any{"price": "25.00"})
+	patch("/catalog/TEST_PROD_HISTORY/variants/TEST_PROD_HISTORY_A", "merchandiser@example.com", map[string]any{"price": "27.50"})
+	// Writing the same price again is not a change, a truncated multi-byte
+	// actor must still be accepted by the database
+	patch("/catalog/TEST_PROD_HISTORY", strings.Repeat("ü", 200), map[string]any{"price": "25.00"})
+
+	getHistory := func(t *testing.T, query string) (int, []PriceChangeResponse) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog/TEST_PROD_HISTORY/price-history"+query, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		var changes []PriceChangeResponse
+		json.NewDecoder(w.Body).Decode(&changes)
+		return w.Code, changes
+	}
+
+	t.Run("GET /catalog/{code}/price-history lists changes oldest first", func(t *testing.T) {
+		status, changes := getHistory(t, "")
+
+		assert.Equal(t, http.StatusOK, status)
+		if assert.Len(t, changes, 3) {
+			assert.Nil(t, changes[0].OldPrice)
+			assert.Equal(t, 20.0, changes[0].NewPrice.Float())
+			assert.Equal(t, anonymousActor, changes[0].ReportedBy)
+
+			assert.Equal(t, 20.0, changes[1].OldPrice.Float())
+			assert.Equal(t, 25.0, changes[1].NewPrice.Float())
+			assert.Equal(t, "merchandiser@example.com", changes[1].ReportedBy)
+
+			assert.Equal(t, "TEST_PROD_HISTORY_A", changes[2].SKU)
+			assert.Nil(t, changes[2].OldPrice)
+			assert.Equal(t, 27.5, changes[2].NewPrice.Float())
+		}
+	})
+
+	t.Run("date range filters changes", func(t *testing.T) {
+		_, changes := getHistory(t, "?to="+time.Now().Add(-time.Hour).UTC().Format(time.RFC3339))
+		assert.Empty(t, changes)
+
+		_, changes = getHistory(t, "?from="+time.Now().UTC().Format(time.DateOnly))
+		assert.Len(t, changes, 3)
+	})
+
+	t.Run("returns 400 for an invalid range and 404 for unknown product", func(t *testing.T) {
+		status, _ := getHistory(t, "?from=soon")
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		req := httptest.NewRequest(http.MethodGet, "/catalog/NONEXISTENT/price-history", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("history outlives the deleted product", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/catalog/TEST_PROD_HISTORY", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		status, changes := getHistory(t, "")
+		assert.Equal(t, http.StatusOK, status)
+		if assert.Len(t, changes, 3) {
+			assert.Equal(t, "merchandiser@example.com", changes[1].ReportedBy)
+			assert.Equal(t, "TEST_PROD_HISTORY_A", changes[2].SKU)
+		}
+	})
+}
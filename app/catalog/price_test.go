@@ -0,0 +1,151 @@
+package catalog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceJSON(t *testing.T) {
+	amount := decimal.RequireFromString("12.49")
+
+	t.Run("number format renders a JSON number", func(t *testing.T) {
+		data, err := json.Marshal(Price{Amount: amount, Currency: BaseCurrency, Format: PriceFormatNumber})
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `12.49`, string(data))
+	})
+
+	t.Run("string format renders an exact amount with currency", func(t *testing.T) {
+		data, err := json.Marshal(Price{Amount: decimal.RequireFromString("10"), Currency: BaseCurrency, Format: PriceFormatString})
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"amount":"10.00","currency":"EUR"}`, string(data))
+	})
+
+	t.Run("decodes both formats", func(t *testing.T) {
+		var number, exact Price
+		assert.NoError(t, json.Unmarshal([]byte(`12.49`), &number))
+		assert.NoError(t, json.Unmarshal([]byte(`{"amount":"12.49","currency":"EUR"}`), &exact))
+
+		assert.True(t, amount.Equal(number.Amount))
+		assert.Equal(t, PriceFormatNumber, number.Format)
+		assert.True(t, amount.Equal(exact.Amount))
+		assert.Equal(t, "EUR", exact.Currency)
+		assert.Equal(t, PriceFormatString, exact.Format)
+	})
+}
+
+func TestParsePriceFormat(t *testing.T) {
+	cases := []struct {
+		name   string
+		target string
+		header string
+		format PriceFormat
+	}{
+		{"defaults to number", "/catalog", "", PriceFormatNumber},
+		{"query parameter", "/catalog?priceFormat=string", "", PriceFormatString},
+		{"header", "/catalog", "string", PriceFormatString},
+		{"query parameter wins over header", "/catalog?priceFormat=number", "string", PriceFormatNumber},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.header != "" {
+				req.Header.Set("X-Price-Format", tc.header)
+			}
+
+			format, err := ParsePriceFormat(req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.format, format)
+		})
+	}
+
+	t.Run("rejects unknown formats", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog?priceFormat=float", nil)
+
+		_, err := ParsePriceFormat(req)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("V2 always uses string format", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v2/catalog?priceFormat=number", nil)
+
+		var format PriceFormat
+		V2(func(w http.ResponseWriter, r *http.Request) {
+			format, _ = ParsePriceFormat(r)
+		})(httptest.NewRecorder(), req)
+
+		assert.Equal(t, PriceFormatString, format)
+	})
+}
+
+func TestPricing_Promotions(t *testing.T) {
+	product := &models.Product{
+		Price: decimal.RequireFromString("100.00"),
+		Promotions: []models.Promotion{
+			{ID: 1, Code: "PCT", Name: "20% off", DiscountType: models.DiscountPercentage, Value: decimal.RequireFromString("20"), Priority: 2, Stackable: true},
+			{ID: 2, Code: "FIX", Name: "5 off", DiscountType: models.DiscountFixed, Value: decimal.RequireFromString("5"), Priority: 1, Stackable: true},
+		},
+	}
+
+	t.Run("promotions discount the current price in order", func(t *testing.T) {
+		prices := Pricing{}.product(product)
+
+		assert.Equal(t, 75.0, prices.current.Float())
+		assert.Equal(t, 100.0, prices.original.Float())
+		if assert.Len(t, prices.promotions, 2) {
+			assert.Equal(t, "PCT", prices.promotions[0].Code)
+			assert.Equal(t, 20.0, prices.promotions[0].Discount.Float())
+			assert.Equal(t, 5.0, prices.promotions[1].Discount.Float())
+		}
+	})
+
+	t.Run("fixed discounts are converted to the served currency", func(t *testing.T) {
+		gbp := &models.Currency{
+			Code:              "GBP",
+			RoundingIncrement: decimal.RequireFromString("0.01"),
+			RoundingMode:      models.RoundingHalfUp,
+			ExchangeRate:      &models.ExchangeRate{CurrencyCode: "GBP", Rate: decimal.RequireFromString("0.5")},
+		}
+
+		prices := Pricing{Currency: gbp}.product(product)
+
+		assert.Equal(t, 37.5, prices.current.Float())
+		assert.Equal(t, 2.5, prices.promotions[1].Discount.Float())
+	})
+}
+
+func TestPricing_Tax(t *testing.T) {
+	reduced := &models.TaxClass{ID: 2, Code: "REDUCED"}
+	product := &models.Product{Price: decimal.RequireFromString("107.00"), EffectiveTaxClass: reduced}
+	vat := &models.CountryVAT{Country: "DE", Rates: map[uint]decimal.Decimal{
+		1:          decimal.RequireFromString("0.19"),
+		reduced.ID: decimal.RequireFromString("0.07"),
+	}}
+
+	t.Run("without a country prices carry no tax split", func(t *testing.T) {
+		assert.Nil(t, Pricing{}.product(product).tax)
+	})
+
+	t.Run("the current price is split at the rate of the tax class", func(t *testing.T) {
+		tax := Pricing{VAT: vat}.product(product).tax
+
+		if assert.NotNil(t, tax) {
+			assert.Equal(t, "DE", tax.Country)
+			assert.Equal(t, "REDUCED", tax.TaxClass)
+			assert.Equal(t, "0.07", tax.Rate.String())
+			assert.Equal(t, 100.0, tax.Net.Float())
+			assert.Equal(t, 7.0, tax.Tax.Float())
+			assert.Equal(t, 107.0, tax.Gross.Float())
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+// maxQuoteItems bounds the line items of a single quote request
+const maxQuoteItems = 100
+
+// QuoteRequest is the body accepted by POST /quotes
+type QuoteRequest struct {
+	Items []QuoteItemRequest `json:"items"`
+}
+
+// QuoteItemRequest asks for a quantity of a SKU
+type QuoteItemRequest struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+// QuoteResponse prices a cart. Amounts are always exact decimals.
+// Subtotal is the sum at regular prices, Discount what sales and promotions
+// take off it and Total what is charged. Prices include VAT; Tax is the
+// VAT of Country contained in Total.
+type QuoteResponse struct {
+	Currency string              `json:"currency"`
+	Country  string              `json:"country,omitempty"`
+	Items    []QuoteLineResponse `json:"items"`
+	Subtotal Price               `json:"subtotal"`
+	Discount Price               `json:"discount"`
+	Tax      Price               `json:"tax"`
+	Total    Price               `json:"total"`
+}
+
+// QuoteLineResponse is a priced line item. Unit prices and the applied
+// promotions are per unit; Discount, Tax and Total cover the whole line.
+// TaxRate is a JSON number like every rate, see api.Rate.
+type QuoteLineResponse struct {
+	SKU               string             `json:"sku"`
+	Product           string             `json:"product"`
+	Name              string             `json:"name"`
+	Quantity          int                `json:"quantity"`
+	UnitPrice         Price              `json:"unit_price"`
+	OriginalUnitPrice Price              `json:"original_unit_price"`
+	Promotions        []AppliedPromotion `json:"promotions,omitempty"`
+	TaxRate           api.Rate           `json:"tax_rate"`
+	Discount          Price              `json:"discount"`
+	Tax               Price              `json:"tax"`
+	Total             Price              `json:"total"`
+}
+
+// HandleQuote handles POST /quotes - prices a list of SKUs and quantities
+// with the same rules as the catalog responses. VAT is reported for
+// ?country=, or for the default country of the handler.
+func (h *CatalogHandler) HandleQuote(w http.ResponseWriter, r *http.Request) {
+	pricing, ok := RequestPricing(w, r, h.currencies, h.taxes)
+	if !ok {
+		return
+	}
+	pricing.Format = PriceFormatString
+	if pricing.VAT == nil && h.defaultCountry != "" {
+		if pricing.VAT, ok = countryVAT(w, h.taxes, h.defaultCountry); !ok {
+			return
+		}
+	}
+
+	var req QuoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Warn("Invalid request body", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := validateQuoteRequest(req); err != nil {
+		slog.Warn("Invalid quote request", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	skus := make([]string, len(req.Items))
+	for i, item := range req.Items {
+		skus[i] = item.SKU
+	}
+
+	slog.Info("Pricing quote", "items", len(req.Items), "currency", pricing.CurrencyCode())
+
+	products, err := h.repo.GetProductsBySKUs(skus)
+	if err != nil {
+		slog.Error("Failed to fetch quote products", "error", err)
+		api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	response, err := buildQuote(req.Items, products, pricing)
+	if err != nil {
+		slog.Warn("Quote references unknown SKU", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api.OKResponse(w, response)
+}
+
+// validateQuoteRequest checks the items of a quote request.
+// The returned error message is safe to send to the client.
+func validateQuoteRequest(req QuoteRequest) error {
+	if len(req.Items) == 0 {
+		return errors.New("At least one item is required")
+	}
+	if len(req.Items) > maxQuoteItems {
+		return fmt.Errorf("Too many items: maximum %d per quote", maxQuoteItems)
+	}
+	seen := make(map[string]bool, len(req.Items))
+	for _, item := range req.Items {
+		if strings.TrimSpace(item.SKU) == "" {
+			return errors.New("Item SKU cannot be empty or whitespace only")
+		}
+		if item.Quantity <= 0 {
+			return errors.New("Item quantity must be a positive integer")
+		}
+		if seen[item.SKU] {
+			return fmt.Errorf("Duplicate item SKU: %s", item.SKU)
+		}
+		seen[item.SKU] = true
+	}
+	return nil
+}
+
+// buildQuote prices the items with the variants of products. The returned
+// error message names the first unknown SKU and is safe to send to the client.
+func buildQuote(items []QuoteItemRequest, products []models.Product, pricing Pricing) (QuoteResponse, error) {
+	type line struct {
+		product *models.Product
+		variant *models.Variant
+	}
+	bySKU := make(map[string]line)
+	for i := range products {
+		p := &products[i]
+		for j := range p.Variants {
+			bySKU[p.Variants[j].SKU] = line{product: p, variant: &p.Variants[j]}
+		}
+	}
+
+	subtotal, discount, tax, total := decimal.Zero, decimal.Zero, decimal.Zero, decimal.Zero
+	lines := make([]QuoteLineResponse, len(items))
+	for i, item := range items {
+		l, ok := bySKU[item.SKU]
+		if !ok {
+			return QuoteResponse{}, fmt.Errorf("Variant not found: %s", item.SKU)
+		}
+
+		prices := pricing.variant(l.product, l.variant)
+		quantity := decimal.NewFromInt(int64(item.Quantity))
+		lineSubtotal := prices.original.Amount.Mul(quantity)
+		lineTotal := prices.current.Amount.Mul(quantity)
+		rate := decimal.Zero
+		if prices.tax != nil {
+			rate = prices.tax.Rate.Decimal
+		}
+		lineTax := models.IncludedVAT(lineTotal, rate)
+
+		lines[i] = QuoteLineResponse{
+			SKU:               item.SKU,
+			Product:           l.product.Code,
+			Name:              l.variant.Name,
+			Quantity:          item.Quantity,
+			UnitPrice:         prices.current,
+			OriginalUnitPrice: prices.original,
+			Promotions:        prices.promotions,
+			TaxRate:           api.NewRate(rate),
+			Discount:          pricing.price(lineSubtotal.Sub(lineTotal)),
+			Tax:               pricing.price(lineTax),
+			Total:             pricing.price(lineTotal),
+		}
+
+		subtotal = subtotal.Add(lineSubtotal)
+		discount = discount.Add(lineSubtotal.Sub(lineTotal))
+		tax = tax.Add(lineTax)
+		total = total.Add(lineTotal)
+	}
+
+	response := QuoteResponse{
+		Currency: pricing.CurrencyCode(),
+		Items:    lines,
+		Subtotal: pricing.price(subtotal),
+		Discount: pricing.price(discount),
+		Tax:      pricing.price(tax),
+		Total:    pricing.price(total),
+	}
+	if pricing.VAT != nil {
+		response.Country = pricing.VAT.Country
+	}
+	return response, nil
+}
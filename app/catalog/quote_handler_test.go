@@ -0,0 +1,101 @@
+package catalog
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/testutil"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildQuote(t *testing.T) {
+	products := []models.Product{{
+		Code:  "PROD",
+		Price: decimal.RequireFromString("10.00"),
+		Variants: []models.Variant{
+			{ID: 1, Name: "Inherits", SKU: "INHERIT"},
+			{ID: 2, Name: "Own price", SKU: "OWN", Price: decimal.NewNullDecimal(decimal.RequireFromString("20.00"))},
+		},
+		Promotions: []models.Promotion{
+			{ID: 1, Code: "TEN", Name: "10% off", DiscountType: models.DiscountPercentage, Value: decimal.RequireFromString("10")},
+		},
+	}}
+	standard := &models.TaxClass{ID: 1, Code: "STANDARD"}
+	products[0].EffectiveTaxClass = standard
+	pricing := Pricing{
+		Format: PriceFormatString,
+		VAT:    &models.CountryVAT{Country: "DE", Rates: map[uint]decimal.Decimal{standard.ID: decimal.RequireFromString("0.19")}},
+	}
+
+	t.Run("prices lines with inheritance, promotions and included VAT", func(t *testing.T) {
+		quote, err := buildQuote([]QuoteItemRequest{{SKU: "INHERIT", Quantity: 3}, {SKU: "OWN", Quantity: 1}},
+			products, pricing)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "EUR", quote.Currency)
+		assert.Equal(t, "DE", quote.Country)
+		if assert.Len(t, quote.Items, 2) {
+			assert.Equal(t, "9", quote.Items[0].UnitPrice.Amount.String())
+			assert.Equal(t, "27", quote.Items[0].Total.Amount.String())
+			assert.Equal(t, "3", quote.Items[0].Discount.Amount.String())
+			assert.Equal(t, "4.31", quote.Items[0].Tax.Amount.String())
+			assert.Equal(t, "18", quote.Items[1].Total.Amount.String())
+		}
+		assert.Equal(t, "50", quote.Subtotal.Amount.String())
+		assert.Equal(t, "5", quote.Discount.Amount.String())
+		assert.Equal(t, "45", quote.Total.Amount.String())
+		assert.Equal(t, "7.18", quote.Tax.Amount.String())
+	})
+
+	t.Run("rejects unknown SKUs", func(t *testing.T) {
+		_, err := buildQuote([]QuoteItemRequest{{SKU: "NOPE", Quantity: 1}}, products, pricing)
+
+		assert.EqualError(t, err, "Variant not found: NOPE")
+	})
+}
+
+func TestValidateQuoteRequest(t *testing.T) {
+	assert.NoError(t, validateQuoteRequest(QuoteRequest{Items: []QuoteItemRequest{{SKU: "A", Quantity: 1}}}))
+
+	for name, req := range map[string]QuoteRequest{
+		"no items":      {},
+		"empty sku":     {Items: []QuoteItemRequest{{SKU: " ", Quantity: 1}}},
+		"zero quantity": {Items: []QuoteItemRequest{{SKU: "A"}}},
+		"duplicate sku": {Items: []QuoteItemRequest{{SKU: "A", Quantity: 1}, {SKU: "A", Quantity: 2}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, validateQuoteRequest(req))
+		})
+	}
+}
+
+func TestQuoteEndpoint(t *testing.T) {
+	mux, _ := setupTestServer()
+
+	t.Run("POST /quotes returns exact line items and totals", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPost, "/quotes", map[string]any{
+			"items": []map[string]any{{"sku": "SKU001A", "quantity": 2}},
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var raw map[string]any
+		err := json.NewDecoder(w.Body).Decode(&raw)
+		assert.NoError(t, err)
+		assert.Equal(t, "EUR", raw["currency"])
+		total, ok := raw["total"].(map[string]any)
+		if assert.True(t, ok, "totals are exact decimals") {
+			assert.IsType(t, "", total["amount"])
+		}
+	})
+
+	t.Run("POST /quotes returns 400 for unknown SKUs", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPost, "/quotes", map[string]any{
+			"items": []map[string]any{{"sku": "NONEXISTENT", "quantity": 1}},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,171 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+// ScheduledPriceRequest is the body accepted by POST /catalog/{code}/scheduled-prices.
+// Without a SKU the price applies to the product and the variants inheriting
+// its price. A missing valid_to keeps the price until the entry is deleted.
+type ScheduledPriceRequest struct {
+	SKU       string          `json:"sku"`
+	Price     decimal.Decimal `json:"price"`
+	ValidFrom time.Time       `json:"valid_from"`
+	ValidTo   *time.Time      `json:"valid_to"`
+}
+
+// ScheduledPriceResponse represents a scheduled price in the base currency
+type ScheduledPriceResponse struct {
+	ID        uint       `json:"id"`
+	SKU       string     `json:"sku,omitempty"`
+	Price     Price      `json:"price"`
+	ValidFrom time.Time  `json:"valid_from"`
+	ValidTo   *time.Time `json:"valid_to,omitempty"`
+	Active    bool       `json:"active"`
+}
+
+// HandleListScheduledPrices handles GET /catalog/{code}/scheduled-prices
+func (h *CatalogHandler) HandleListScheduledPrices(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+
+	format, ok := requestPriceFormat(w, r)
+	if !ok {
+		return
+	}
+
+	slog.Info("Fetching scheduled prices", "code", code)
+
+	prices, err := h.repo.GetScheduledPrices(code)
+	if err != nil {
+		writeScheduledPriceError(w, code, err)
+		return
+	}
+
+	now := time.Now()
+	response := make([]ScheduledPriceResponse, len(prices))
+	for i := range prices {
+		response[i] = mapScheduledPriceResponse(&prices[i], format, now)
+	}
+
+	api.OKResponse(w, response)
+}
+
+// HandleCreateScheduledPrice handles POST /catalog/{code}/scheduled-prices
+func (h *CatalogHandler) HandleCreateScheduledPrice(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+
+	format, ok := requestPriceFormat(w, r)
+	if !ok {
+		return
+	}
+
+	var req ScheduledPriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Warn("Invalid request body", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := validateScheduledPriceRequest(req); err != nil {
+		slog.Warn("Invalid scheduled price request", "code", code, "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slog.Info("Scheduling price", "code", code, "sku", req.SKU, "validFrom", req.ValidFrom, "validTo", req.ValidTo)
+
+	price := &models.ScheduledPrice{
+		Price:     req.Price,
+		ValidFrom: req.ValidFrom,
+		ValidTo:   req.ValidTo,
+	}
+	if err := h.repo.CreateScheduledPrice(code, strings.TrimSpace(req.SKU), price); err != nil {
+		writeScheduledPriceError(w, code, err)
+		return
+	}
+
+	slog.Info("Successfully scheduled price", "code", code, "id", price.ID)
+
+	api.CreatedResponse(w, mapScheduledPriceResponse(price, format, time.Now()))
+}
+
+// HandleDeleteScheduledPrice handles DELETE /catalog/{code}/scheduled-prices/{id}
+func (h *CatalogHandler) HandleDeleteScheduledPrice(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		writeScheduledPriceError(w, code, models.ErrScheduledPriceNotFound)
+		return
+	}
+
+	slog.Info("Deleting scheduled price", "code", code, "id", id)
+
+	if err := h.repo.DeleteScheduledPrice(code, uint(id)); err != nil {
+		writeScheduledPriceError(w, code, err)
+		return
+	}
+
+	slog.Info("Successfully deleted scheduled price", "code", code, "id", id)
+
+	api.NoContentResponse(w)
+}
+
+// validateScheduledPriceRequest checks the price and validity window of a scheduled price
+func validateScheduledPriceRequest(req ScheduledPriceRequest) error {
+	if err := validatePrice("price", req.Price); err != nil {
+		return err
+	}
+	if req.ValidFrom.IsZero() {
+		return errors.New("valid_from is required")
+	}
+	if req.ValidTo != nil && !req.ValidTo.After(req.ValidFrom) {
+		return errors.New("Invalid validity window: valid_to must be after valid_from")
+	}
+	return nil
+}
+
+// writeScheduledPriceError maps repository errors of scheduled prices to HTTP responses
+func writeScheduledPriceError(w http.ResponseWriter, code string, err error) {
+	switch {
+	case errors.Is(err, models.ErrProductNotFound):
+		slog.Warn("Product not found", "code", code)
+		api.ErrorResponse(w, http.StatusNotFound, "Product not found")
+	case errors.Is(err, models.ErrVariantNotFound):
+		slog.Warn("Variant not found", "code", code)
+		api.ErrorResponse(w, http.StatusBadRequest, "Variant not found")
+	case errors.Is(err, models.ErrScheduledPriceNotFound):
+		slog.Warn("Scheduled price not found", "code", code)
+		api.ErrorResponse(w, http.StatusNotFound, "Scheduled price not found")
+	case errors.Is(err, models.ErrInvalidScheduledPrice):
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid scheduled price")
+	default:
+		slog.Error("Failed to process scheduled price", "code", code, "error", err)
+		api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+// mapScheduledPriceResponse maps a scheduled price to its API response
+func mapScheduledPriceResponse(price *models.ScheduledPrice, format PriceFormat, now time.Time) ScheduledPriceResponse {
+	response := ScheduledPriceResponse{
+		ID:        price.ID,
+		Price:     Pricing{Format: format}.price(price.Price),
+		ValidFrom: price.ValidFrom,
+		ValidTo:   price.ValidTo,
+		Active:    price.ActiveAt(now),
+	}
+	if price.Variant != nil {
+		response.SKU = price.Variant.SKU
+	}
+	return response
+}
@@ -0,0 +1,125 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduledPriceEndpoints(t *testing.T) {
+	mux, db := setupTestServer()
+
+	testutil.CleanupProduct(t, db, "TEST_PROD_SALE")
+	w := testutil.DoJSON(mux, http.MethodPost, "/catalog", map[string]any{
+		"code":     "TEST_PROD_SALE",
+		"price":    "100.00",
+		"category": "CLOTHING",
+		"variants": []map[string]any{
+			{"name": "A", "sku": "TEST_PROD_SALE_A"},
+			{"name": "B", "sku": "TEST_PROD_SALE_B", "price": "120.00"},
+		},
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	getDetails := func(t *testing.T) ProductDetailsResponse {
+		req := httptest.NewRequest(http.MethodGet, "/catalog/TEST_PROD_SALE", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response ProductDetailsResponse
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		return response
+	}
+
+	var created ScheduledPriceResponse
+
+	t.Run("POST /catalog/{code}/scheduled-prices starts a sale", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPost, "/catalog/TEST_PROD_SALE/scheduled-prices", map[string]any{
+			"price":      "79.99",
+			"valid_from": time.Now().Add(-time.Hour).Format(time.RFC3339),
+			"valid_to":   time.Now().Add(time.Hour).Format(time.RFC3339),
+		})
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		err := json.NewDecoder(w.Body).Decode(&created)
+		assert.NoError(t, err)
+		assert.True(t, created.Active)
+
+		response := getDetails(t)
+		assert.Equal(t, 79.99, response.Price.Float())
+		assert.Equal(t, 100.0, response.OriginalPrice.Float())
+		if assert.NotNil(t, response.SalePrice) {
+			assert.Equal(t, 79.99, response.SalePrice.Float())
+		}
+		for _, variant := range response.Variants {
+			if variant.SKU == "TEST_PROD_SALE_A" {
+				assert.NotNil(t, variant.SalePrice, "inheriting variant is on sale")
+			} else {
+				assert.Nil(t, variant.SalePrice, "variant with its own price is not on sale")
+				assert.Equal(t, 120.0, variant.Price.Float())
+			}
+		}
+	})
+
+	t.Run("future entries are listed but not applied", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPost, "/catalog/TEST_PROD_SALE/scheduled-prices", map[string]any{
+			"sku":        "TEST_PROD_SALE_B",
+			"price":      "90.00",
+			"valid_from": time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+		})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		req := httptest.NewRequest(http.MethodGet, "/catalog/TEST_PROD_SALE/scheduled-prices", nil)
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var prices []ScheduledPriceResponse
+		err := json.NewDecoder(w.Body).Decode(&prices)
+		assert.NoError(t, err)
+		assert.Len(t, prices, 2)
+		assert.Equal(t, "TEST_PROD_SALE_B", prices[1].SKU)
+		assert.False(t, prices[1].Active)
+	})
+
+	t.Run("DELETE /catalog/{code}/scheduled-prices/{id} ends the sale", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodDelete, fmt.Sprintf("/catalog/TEST_PROD_SALE/scheduled-prices/%d", created.ID), nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		response := getDetails(t)
+		assert.Equal(t, 100.0, response.Price.Float())
+		assert.Nil(t, response.SalePrice)
+
+		w = testutil.DoJSON(mux, http.MethodDelete, fmt.Sprintf("/catalog/TEST_PROD_SALE/scheduled-prices/%d", created.ID), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("returns 400 for invalid windows and unknown variants", func(t *testing.T) {
+		from := time.Now()
+		for _, body := range []map[string]any{
+			{"price": "10.00"},
+			{"price": "0", "valid_from": from.Format(time.RFC3339)},
+			{"price": "10.00", "valid_from": from.Format(time.RFC3339), "valid_to": from.Add(-time.Hour).Format(time.RFC3339)},
+			{"price": "10.00", "valid_from": from.Format(time.RFC3339), "sku": "NOPE"},
+		} {
+			w := testutil.DoJSON(mux, http.MethodPost, "/catalog/TEST_PROD_SALE/scheduled-prices", body)
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		}
+	})
+
+	t.Run("returns 404 for unknown product", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalog/NONEXISTENT/scheduled-prices", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
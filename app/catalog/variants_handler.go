@@ -0,0 +1,315 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sort"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+// NullablePrice distinguishes a price omitted from a PATCH body from an
+// explicit null, which clears the variant price.
+type NullablePrice struct {
+	Set   bool
+	Value decimal.NullDecimal
+}
+
+func (p *NullablePrice) UnmarshalJSON(data []byte) error {
+	p.Set = true
+	return p.Value.UnmarshalJSON(data)
+}
+
+// PatchVariantRequest is the body accepted by PATCH /catalog/{code}/variants/{sku}.
+// Sending "price": null clears the variant price so it inherits the product price.
+// Options replace all options of the variant.
+type PatchVariantRequest struct {
+	Name    *string         `json:"name"`
+	SKU     *string         `json:"sku"`
+	Price   NullablePrice   `json:"price"`
+	Prices  *PriceOverrides `json:"prices"`
+	Options *VariantOptions `json:"options"`
+}
+
+// HandleListVariants handles GET /catalog/{code}/variants
+func (h *CatalogHandler) HandleListVariants(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+
+	format, ok := requestPriceFormat(w, r)
+	if !ok {
+		return
+	}
+
+	slog.Info("Fetching product variants", "code", code)
+
+	product, err := h.repo.GetProductByCode(code)
+	if err != nil {
+		writeVariantError(w, code, "", err)
+		return
+	}
+
+	api.OKResponse(w, mapProductDetailsResponse(product, Pricing{Format: format}).Variants)
+}
+
+// HandleGetVariant handles GET /catalog/{code}/variants/{sku}
+func (h *CatalogHandler) HandleGetVariant(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	sku := r.PathValue("sku")
+
+	format, ok := requestPriceFormat(w, r)
+	if !ok {
+		return
+	}
+
+	slog.Info("Fetching product variant", "code", code, "sku", sku)
+
+	product, err := h.repo.GetProductByCode(code)
+	if err != nil {
+		writeVariantError(w, code, sku, err)
+		return
+	}
+
+	variant := findVariantBySKU(product, sku)
+	if variant == nil {
+		writeVariantError(w, code, sku, models.ErrVariantNotFound)
+		return
+	}
+
+	api.OKResponse(w, mapVariantResponse(product, variant, Pricing{Format: format}))
+}
+
+// HandleCreateVariant handles POST /catalog/{code}/variants
+func (h *CatalogHandler) HandleCreateVariant(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+
+	format, ok := requestPriceFormat(w, r)
+	if !ok {
+		return
+	}
+
+	var req VariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Warn("Invalid request body", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := validateVariantRequest(req); err != nil {
+		slog.Warn("Invalid variant request", "code", code, "sku", req.SKU, "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	product, err := h.repo.GetProductByCode(code)
+	if err != nil {
+		writeVariantError(w, code, req.SKU, err)
+		return
+	}
+
+	slog.Info("Creating product variant", "code", code, "sku", req.SKU)
+
+	variant := req.toModel()
+	if err := h.repo.WithActor(requestActor(r)).CreateVariant(code, &variant); err != nil {
+		writeVariantError(w, code, req.SKU, err)
+		return
+	}
+
+	slog.Info("Successfully created product variant", "code", code, "sku", variant.SKU)
+
+	api.CreatedResponse(w, mapVariantResponse(product, &variant, Pricing{Format: format}))
+}
+
+// HandlePatchVariant handles PATCH /catalog/{code}/variants/{sku}
+func (h *CatalogHandler) HandlePatchVariant(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	sku := r.PathValue("sku")
+
+	format, ok := requestPriceFormat(w, r)
+	if !ok {
+		return
+	}
+
+	var patch PatchVariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		slog.Warn("Invalid request body", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	product, err := h.repo.GetProductByCode(code)
+	if err != nil {
+		writeVariantError(w, code, sku, err)
+		return
+	}
+
+	existing := findVariantBySKU(product, sku)
+	if existing == nil {
+		writeVariantError(w, code, sku, models.ErrVariantNotFound)
+		return
+	}
+
+	req := variantRequestFromModel(existing)
+	if patch.Name != nil {
+		req.Name = *patch.Name
+	}
+	if patch.SKU != nil {
+		req.SKU = *patch.SKU
+	}
+	if patch.Price.Set {
+		req.Price = patch.Price.Value
+	}
+	if patch.Prices != nil {
+		req.Prices = *patch.Prices
+	}
+	if patch.Options != nil {
+		req.Options = *patch.Options
+	}
+
+	if err := validateVariantRequest(req); err != nil {
+		slog.Warn("Invalid variant request", "code", code, "sku", sku, "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slog.Info("Updating product variant", "code", code, "sku", sku, "clearPrice", patch.Price.Set && !patch.Price.Value.Valid)
+
+	variant := req.toModel()
+	if err := h.repo.WithActor(requestActor(r)).UpdateVariant(code, sku, &variant); err != nil {
+		writeVariantError(w, code, sku, err)
+		return
+	}
+
+	slog.Info("Successfully updated product variant", "code", code, "sku", variant.SKU)
+
+	api.OKResponse(w, mapVariantResponse(product, &variant, Pricing{Format: format}))
+}
+
+// HandleDeleteVariant handles DELETE /catalog/{code}/variants/{sku}
+func (h *CatalogHandler) HandleDeleteVariant(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	sku := r.PathValue("sku")
+
+	slog.Info("Deleting product variant", "code", code, "sku", sku)
+
+	if err := h.repo.WithActor(requestActor(r)).DeleteVariant(code, sku); err != nil {
+		writeVariantError(w, code, sku, err)
+		return
+	}
+
+	slog.Info("Successfully deleted product variant", "code", code, "sku", sku)
+
+	api.NoContentResponse(w)
+}
+
+// writeVariantError maps repository errors of variant operations to HTTP responses
+func writeVariantError(w http.ResponseWriter, code, sku string, err error) {
+	switch {
+	case errors.Is(err, models.ErrProductNotFound):
+		slog.Warn("Product not found", "code", code)
+		api.ErrorResponse(w, http.StatusNotFound, "Product not found")
+	case errors.Is(err, models.ErrVariantNotFound):
+		slog.Warn("Variant not found", "code", code, "sku", sku)
+		api.ErrorResponse(w, http.StatusNotFound, "Variant not found")
+	case errors.Is(err, models.ErrVariantSKUExists):
+		slog.Warn("Duplicate variant SKU", "code", code, "sku", sku)
+		api.ErrorResponse(w, http.StatusConflict, "Variant SKU already exists")
+	case errors.Is(err, models.ErrVariantReserved):
+		slog.Warn("Variant held by pending reservations", "code", code, "sku", sku)
+		api.ErrorResponse(w, http.StatusConflict, "Variant is held by pending reservations")
+	case errors.Is(err, models.ErrCurrencyNotFound):
+		slog.Warn("Price override currency not found", "code", code, "sku", sku)
+		api.ErrorResponse(w, http.StatusBadRequest, "Currency not found")
+	case errors.Is(err, models.ErrInvalidVariant), errors.Is(err, models.ErrInvalidVariantOption):
+		slog.Warn("Invalid variant", "code", code, "sku", sku, "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+	default:
+		slog.Error("Failed to process variant", "code", code, "sku", sku, "error", err)
+		api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+// findVariantBySKU returns the variant of product with the given SKU, or nil
+func findVariantBySKU(product *models.Product, sku string) *models.Variant {
+	for i := range product.Variants {
+		if product.Variants[i].SKU == sku {
+			return &product.Variants[i]
+		}
+	}
+	return nil
+}
+
+// mapVariantResponse maps a variant to its response applying price inheritance
+func mapVariantResponse(product *models.Product, v *models.Variant, pricing Pricing) VariantResponse {
+	prices := pricing.variant(product, v)
+	return VariantResponse{
+		Name:          v.Name,
+		SKU:           v.SKU,
+		Price:         prices.current,
+		OriginalPrice: prices.original,
+		SalePrice:     prices.sale,
+		Promotions:    prices.promotions,
+		Tax:           prices.tax,
+		Options:       mapVariantOptions(v),
+		Available:     v.Stock.Available(),
+		InStock:       v.Stock.Available() > 0,
+	}
+}
+
+// mapVariantOptions maps the options of a variant by attribute code
+func mapVariantOptions(v *models.Variant) map[string]string {
+	if len(v.Options) == 0 {
+		return nil
+	}
+	options := make(map[string]string, len(v.Options))
+	for _, option := range v.Options {
+		if option.Attribute != nil {
+			options[option.Attribute.Code] = option.Value
+		}
+	}
+	return options
+}
+
+// mapOptionMatrix builds the option axes of a product from the options of
+// its variants. Axes follow the attribute order and leave out attributes no
+// variant has a value for; text values keep the order of the variants and
+// number values are sorted numerically.
+func mapOptionMatrix(product *models.Product) []OptionAxis {
+	var axes []OptionAxis
+	for i := range product.Attributes {
+		attribute := &product.Attributes[i]
+		axis := OptionAxis{Code: attribute.Code, Name: attribute.Name, Type: attribute.DataType}
+
+		index := make(map[string]int)
+		for j := range product.Variants {
+			v := &product.Variants[j]
+			value, ok := v.OptionValue(attribute.Code)
+			if !ok {
+				continue
+			}
+			k, seen := index[value]
+			if !seen {
+				k = len(axis.Values)
+				index[value] = k
+				axis.Values = append(axis.Values, OptionValue{Value: value})
+			}
+			axis.Values[k].SKUs = append(axis.Values[k].SKUs, v.SKU)
+		}
+		if len(axis.Values) == 0 {
+			continue
+		}
+
+		if attribute.DataType == models.AttributeNumber {
+			sort.SliceStable(axis.Values, func(a, b int) bool {
+				x, errX := decimal.NewFromString(axis.Values[a].Value)
+				y, errY := decimal.NewFromString(axis.Values[b].Value)
+				return errX == nil && errY == nil && x.LessThan(y)
+			})
+		}
+		axes = append(axes, axis)
+	}
+	return axes
+}
@@ -0,0 +1,230 @@
+package catalog
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/testutil"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullablePrice(t *testing.T) {
+	t.Run("distinguishes absent, null and value", func(t *testing.T) {
+		var absent, null, value PatchVariantRequest
+
+		assert.NoError(t, json.Unmarshal([]byte(`{}`), &absent))
+		assert.NoError(t, json.Unmarshal([]byte(`{"price":null}`), &null))
+		assert.NoError(t, json.Unmarshal([]byte(`{"price":"9.99"}`), &value))
+
+		assert.False(t, absent.Price.Set)
+		assert.True(t, null.Price.Set)
+		assert.False(t, null.Price.Value.Valid)
+		assert.True(t, value.Price.Set)
+		assert.True(t, value.Price.Value.Valid)
+		assert.Equal(t, "9.99", value.Price.Value.Decimal.String())
+	})
+}
+
+func TestVariantEndpoints(t *testing.T) {
+	mux, db := setupTestServer()
+
+	testutil.CleanupProduct(t, db, "TEST_VARIANTS")
+	w := testutil.DoJSON(mux, http.MethodPost, "/catalog", map[string]any{
+		"code":     "TEST_VARIANTS",
+		"price":    "40.00",
+		"category": "ACCESSORIES",
+		"variants": []map[string]any{{"name": "A", "sku": "TEST_VARIANTS_A"}},
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	t.Run("GET /catalog/{code}/variants lists variants", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodGet, "/catalog/TEST_VARIANTS/variants", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []VariantResponse
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+		assert.Equal(t, 40.0, response[0].Price.Float())
+	})
+
+	t.Run("GET /catalog/{code}/variants returns 404 for unknown product", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodGet, "/catalog/NONEXISTENT/variants", nil)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("POST /catalog/{code}/variants creates variant", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPost, "/catalog/TEST_VARIANTS/variants", map[string]any{
+			"name":  "B",
+			"sku":   "TEST_VARIANTS_B",
+			"price": "45.00",
+		})
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response VariantResponse
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.Equal(t, "TEST_VARIANTS_B", response.SKU)
+		assert.Equal(t, 45.0, response.Price.Float())
+	})
+
+	t.Run("POST /catalog/{code}/variants returns 409 for duplicate SKU", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPost, "/catalog/TEST_VARIANTS/variants", map[string]any{
+			"name": "Dup",
+			"sku":  "SKU001A",
+		})
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("POST /catalog/{code}/variants returns 400 for missing SKU", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPost, "/catalog/TEST_VARIANTS/variants", map[string]any{
+			"name": "No SKU",
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("GET /catalog/{code}/variants/{sku} returns variant", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodGet, "/catalog/TEST_VARIANTS/variants/TEST_VARIANTS_B", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("GET /catalog/{code}/variants/{sku} returns 404 for unknown SKU", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodGet, "/catalog/TEST_VARIANTS/variants/NONEXISTENT", nil)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("PATCH /catalog/{code}/variants/{sku} with null price inherits product price", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPatch, "/catalog/TEST_VARIANTS/variants/TEST_VARIANTS_B", map[string]any{
+			"price": nil,
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response VariantResponse
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.Equal(t, "B", response.Name, "Name should be unchanged")
+		assert.Equal(t, 40.0, response.Price.Float())
+
+		var stored models.Variant
+		db.Where("sku = ?", "TEST_VARIANTS_B").First(&stored)
+		assert.False(t, stored.Price.Valid, "Price should be stored as NULL")
+	})
+
+	t.Run("DELETE /catalog/{code}/variants/{sku} removes variant", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodDelete, "/catalog/TEST_VARIANTS/variants/TEST_VARIANTS_B", nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		w = testutil.DoJSON(mux, http.MethodGet, "/catalog/TEST_VARIANTS/variants/TEST_VARIANTS_B", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestMapOptionMatrix(t *testing.T) {
+	size := models.Attribute{ID: 1, Code: "size", Name: "Size", DataType: models.AttributeNumber, Position: 1}
+	color := models.Attribute{ID: 2, Code: "color", Name: "Color", DataType: models.AttributeText, Position: 2}
+	material := models.Attribute{ID: 3, Code: "material", Name: "Material", DataType: models.AttributeText, Position: 3}
+	variant := func(sku, sizeValue, colorValue string) models.Variant {
+		return models.Variant{SKU: sku, Options: []models.VariantOption{
+			{AttributeID: size.ID, Attribute: &size, Value: sizeValue},
+			{AttributeID: color.ID, Attribute: &color, Value: colorValue},
+		}}
+	}
+	product := &models.Product{
+		Attributes: []models.Attribute{size, color, material},
+		Variants: []models.Variant{
+			variant("A", "43", "white"),
+			variant("B", "9", "black"),
+			variant("C", "43", "black"),
+		},
+	}
+
+	axes := mapOptionMatrix(product)
+
+	assert.Equal(t, []OptionAxis{
+		{Code: "size", Name: "Size", Type: "number", Values: []OptionValue{
+			{Value: "9", SKUs: []string{"B"}},
+			{Value: "43", SKUs: []string{"A", "C"}},
+		}},
+		{Code: "color", Name: "Color", Type: "text", Values: []OptionValue{
+			{Value: "white", SKUs: []string{"A"}},
+			{Value: "black", SKUs: []string{"B", "C"}},
+		}},
+	}, axes, "numbers sort numerically, text keeps variant order and unused attributes are left out")
+	assert.Equal(t, map[string]string{"size": "9", "color": "black"}, mapVariantOptions(&product.Variants[1]))
+}
+
+func TestVariantOptionEndpoints(t *testing.T) {
+	mux, db := setupTestServer()
+
+	testutil.CleanupProduct(t, db, "TEST_OPTIONS")
+	w := testutil.DoJSON(mux, http.MethodPost, "/catalog", map[string]any{
+		"code":     "TEST_OPTIONS",
+		"price":    "80.00",
+		"category": "SHOES",
+		"variants": []map[string]any{
+			{"name": "41 black", "sku": "TEST_OPTIONS_41", "options": map[string]string{"size": "41.0", "color": "black"}},
+		},
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	t.Run("POST /catalog stores normalized options", func(t *testing.T) {
+		var response ProductDetailsResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		if assert.Len(t, response.Variants, 1) {
+			assert.Equal(t, map[string]string{"size": "41", "color": "black"}, response.Variants[0].Options)
+		}
+	})
+
+	t.Run("POST /catalog/{code}/variants rejects options of unknown attributes", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPost, "/catalog/TEST_OPTIONS/variants", map[string]any{
+			"name":    "Leather",
+			"sku":     "TEST_OPTIONS_LEATHER",
+			"options": map[string]string{"material": "leather"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("POST /catalog/{code}/variants rejects non-numeric sizes", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPost, "/catalog/TEST_OPTIONS/variants", map[string]any{
+			"name":    "XL",
+			"sku":     "TEST_OPTIONS_XL",
+			"options": map[string]string{"size": "XL"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("PATCH /catalog/{code}/variants/{sku} replaces options", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPatch, "/catalog/TEST_OPTIONS/variants/TEST_OPTIONS_41", map[string]any{
+			"options": map[string]string{"size": "42", "color": "white"},
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response VariantResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, map[string]string{"size": "42", "color": "white"}, response.Options)
+	})
+
+	t.Run("GET /catalog/{code} returns the option matrix", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodGet, "/catalog/TEST_OPTIONS", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response ProductDetailsResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		if assert.Len(t, response.Options, 2) {
+			assert.Equal(t, "size", response.Options[0].Code)
+			assert.Equal(t, []OptionValue{{Value: "42", SKUs: []string{"TEST_OPTIONS_41"}}}, response.Options[0].Values)
+		}
+	})
+}
@@ -0,0 +1,434 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+// maxPrice is the exclusive upper bound allowed by the decimal(10,2) price columns
+var maxPrice = decimal.New(1, 8)
+
+// PriceOverrides maps currency codes to explicit prices that replace the
+// converted base price, e.g. {"GBP": "10.99"}
+type PriceOverrides map[string]decimal.Decimal
+
+// ProductRequest is the body accepted by POST /catalog and PUT /catalog/{code}.
+// Without a tax class the product inherits the class of its category tree.
+type ProductRequest struct {
+	Code     string           `json:"code"`
+	Price    decimal.Decimal  `json:"price"`
+	Prices   PriceOverrides   `json:"prices"`
+	Category string           `json:"category"`
+	TaxClass string           `json:"tax_class"`
+	Variants []VariantRequest `json:"variants"`
+}
+
+// VariantOptions maps attribute codes to the option values of a variant,
+// e.g. {"size": "42", "color": "black"}
+type VariantOptions map[string]string
+
+// VariantRequest represents a variant inside a product write request.
+// A null or missing price means the variant inherits the product price.
+// Options must reference attributes of the product category tree.
+type VariantRequest struct {
+	Name    string              `json:"name"`
+	SKU     string              `json:"sku"`
+	Price   decimal.NullDecimal `json:"price"`
+	Prices  PriceOverrides      `json:"prices"`
+	Options VariantOptions      `json:"options,omitempty"`
+}
+
+// PatchProductRequest is the body accepted by PATCH /catalog/{code}.
+// Only the fields present in the body are changed.
+type PatchProductRequest struct {
+	Code     *string           `json:"code"`
+	Price    *decimal.Decimal  `json:"price"`
+	Prices   *PriceOverrides   `json:"prices"`
+	Category *string           `json:"category"`
+	TaxClass *string           `json:"tax_class"`
+	Variants *[]VariantRequest `json:"variants"`
+}
+
+// HandleCreate handles POST /catalog - creates a product with its variants
+func (h *CatalogHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	format, ok := requestPriceFormat(w, r)
+	if !ok {
+		return
+	}
+
+	var req ProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Warn("Invalid request body", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := validateProductRequest(req); err != nil {
+		slog.Warn("Invalid product request", "code", req.Code, "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slog.Info("Creating product", "code", req.Code, "category", req.Category)
+
+	product := req.toModel()
+	if err := h.repo.WithActor(requestActor(r)).CreateProduct(product); err != nil {
+		writeProductError(w, req.Code, err)
+		return
+	}
+
+	slog.Info("Successfully created product", "code", product.Code)
+
+	api.CreatedResponse(w, mapProductDetailsResponse(product, Pricing{Format: format}))
+}
+
+// HandleReplace handles PUT /catalog/{code} - replaces a product and its variants
+func (h *CatalogHandler) HandleReplace(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+
+	format, ok := requestPriceFormat(w, r)
+	if !ok {
+		return
+	}
+
+	var req ProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Warn("Invalid request body", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// The path identifies the product; the body code is only needed to rename it
+	if req.Code == "" {
+		req.Code = code
+	}
+
+	h.update(w, r, code, req, format)
+}
+
+// HandlePatch handles PATCH /catalog/{code} - updates the fields present in the body
+func (h *CatalogHandler) HandlePatch(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+
+	format, ok := requestPriceFormat(w, r)
+	if !ok {
+		return
+	}
+
+	var patch PatchProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		slog.Warn("Invalid request body", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	existing, err := h.repo.GetProductByCode(code)
+	if err != nil {
+		writeProductError(w, code, err)
+		return
+	}
+
+	req := productRequestFromModel(existing)
+	if patch.Code != nil {
+		req.Code = *patch.Code
+	}
+	if patch.Price != nil {
+		req.Price = *patch.Price
+	}
+	if patch.Prices != nil {
+		req.Prices = *patch.Prices
+	}
+	if patch.Category != nil {
+		req.Category = *patch.Category
+	}
+	if patch.TaxClass != nil {
+		req.TaxClass = *patch.TaxClass
+	}
+	if patch.Variants != nil {
+		req.Variants = *patch.Variants
+	}
+
+	h.update(w, r, code, req, format)
+}
+
+// update validates req and stores it as the new state of the product identified by code
+func (h *CatalogHandler) update(w http.ResponseWriter, r *http.Request, code string, req ProductRequest, format PriceFormat) {
+	if err := validateProductRequest(req); err != nil {
+		slog.Warn("Invalid product request", "code", code, "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slog.Info("Updating product", "code", code, "newCode", req.Code)
+
+	product := req.toModel()
+	if err := h.repo.WithActor(requestActor(r)).UpdateProduct(code, product); err != nil {
+		writeProductError(w, code, err)
+		return
+	}
+
+	slog.Info("Successfully updated product", "code", product.Code)
+
+	api.OKResponse(w, mapProductDetailsResponse(product, Pricing{Format: format}))
+}
+
+// HandleDelete handles DELETE /catalog/{code} - removes a product and its variants
+func (h *CatalogHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+
+	slog.Info("Deleting product", "code", code)
+
+	if err := h.repo.WithActor(requestActor(r)).DeleteProduct(code); err != nil {
+		writeProductError(w, code, err)
+		return
+	}
+
+	slog.Info("Successfully deleted product", "code", code)
+
+	api.NoContentResponse(w)
+}
+
+// writeProductError maps repository errors of product writes to HTTP responses
+func writeProductError(w http.ResponseWriter, code string, err error) {
+	switch {
+	case errors.Is(err, models.ErrProductNotFound):
+		slog.Warn("Product not found", "code", code)
+		api.ErrorResponse(w, http.StatusNotFound, "Product not found")
+	case errors.Is(err, models.ErrProductCodeExists):
+		slog.Warn("Duplicate product code", "code", code)
+		api.ErrorResponse(w, http.StatusConflict, "Product code already exists")
+	case errors.Is(err, models.ErrVariantSKUExists):
+		slog.Warn("Duplicate variant SKU", "code", code)
+		api.ErrorResponse(w, http.StatusConflict, "Variant SKU already exists")
+	case errors.Is(err, models.ErrVariantReserved):
+		slog.Warn("Variant held by pending reservations", "code", code)
+		api.ErrorResponse(w, http.StatusConflict, "Variant is held by pending reservations")
+	case errors.Is(err, models.ErrCategoryNotFound):
+		slog.Warn("Category not found", "code", code)
+		api.ErrorResponse(w, http.StatusBadRequest, "Category not found")
+	case errors.Is(err, models.ErrCurrencyNotFound):
+		slog.Warn("Price override currency not found", "code", code)
+		api.ErrorResponse(w, http.StatusBadRequest, "Currency not found")
+	case errors.Is(err, models.ErrTaxClassNotFound):
+		slog.Warn("Tax class not found", "code", code)
+		api.ErrorResponse(w, http.StatusBadRequest, "Tax class not found")
+	case errors.Is(err, models.ErrInvalidProduct), errors.Is(err, models.ErrInvalidVariantOption):
+		slog.Warn("Invalid product", "code", code, "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+	default:
+		slog.Error("Failed to write product", "code", code, "error", err)
+		api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+// validateProductRequest applies the same field rules used for categories:
+// required, non-whitespace and bounded by the column sizes.
+func validateProductRequest(req ProductRequest) error {
+	if req.Code == "" || req.Category == "" {
+		return errors.New("Code and category are required")
+	}
+	if strings.TrimSpace(req.Code) == "" || strings.TrimSpace(req.Category) == "" {
+		return errors.New("Code and category cannot be empty or whitespace only")
+	}
+	if len(req.Code) > 32 {
+		return errors.New("Code too long: maximum 32 characters")
+	}
+	if err := validatePrice("price", req.Price); err != nil {
+		return err
+	}
+	if err := validatePriceOverrides(req.Prices); err != nil {
+		return err
+	}
+
+	skus := make(map[string]bool, len(req.Variants))
+	for i, v := range req.Variants {
+		if err := validateVariantRequest(v); err != nil {
+			return fmt.Errorf("Variant %d: %w", i, err)
+		}
+		if skus[v.SKU] {
+			return fmt.Errorf("Variant %d: duplicate SKU %s", i, v.SKU)
+		}
+		skus[v.SKU] = true
+	}
+
+	return nil
+}
+
+// validateVariantRequest checks a single variant of a product write request
+func validateVariantRequest(v VariantRequest) error {
+	if strings.TrimSpace(v.Name) == "" || strings.TrimSpace(v.SKU) == "" {
+		return errors.New("name and SKU cannot be empty or whitespace only")
+	}
+	if len(v.SKU) > 32 {
+		return errors.New("SKU too long: maximum 32 characters")
+	}
+	if len(v.Name) > 256 {
+		return errors.New("name too long: maximum 256 characters")
+	}
+	if v.Price.Valid {
+		if err := validatePrice("price", v.Price.Decimal); err != nil {
+			return err
+		}
+	}
+	if err := validateVariantOptions(v.Options); err != nil {
+		return err
+	}
+	return validatePriceOverrides(v.Prices)
+}
+
+// validateVariantOptions checks the shape of variant options. Whether the
+// attributes exist and the values match their type is checked on write.
+func validateVariantOptions(options VariantOptions) error {
+	for _, code := range options.codes() {
+		if strings.TrimSpace(code) == "" || len(code) > 32 {
+			return fmt.Errorf("Invalid options: attribute code %q must be 1 to 32 characters", code)
+		}
+		if value := strings.TrimSpace(options[code]); value == "" || len(value) > 64 {
+			return fmt.Errorf("Invalid options: %s must be 1 to 64 characters", code)
+		}
+	}
+	return nil
+}
+
+// codes returns the attribute codes of the options in sorted order
+func (o VariantOptions) codes() []string {
+	codes := make([]string, 0, len(o))
+	for code := range o {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// validatePriceOverrides checks the currency codes and amounts of price overrides.
+// The base currency price is the price field itself and cannot be overridden.
+func validatePriceOverrides(prices PriceOverrides) error {
+	codes := make([]string, 0, len(prices))
+	for currency := range prices {
+		codes = append(codes, currency)
+	}
+	sort.Strings(codes)
+
+	for _, currency := range codes {
+		if len(currency) != 3 || strings.ToUpper(currency) != currency {
+			return fmt.Errorf("Invalid prices: %q is not an uppercase ISO 4217 currency code", currency)
+		}
+		if currency == models.BaseCurrency {
+			return fmt.Errorf("Invalid prices: %s is the base currency, set price instead", currency)
+		}
+		if err := validatePrice("prices."+currency, prices[currency]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatePrice ensures a price is positive and fits a decimal(10,2) column
+func validatePrice(field string, price decimal.Decimal) error {
+	if !price.IsPositive() {
+		return fmt.Errorf("Invalid %s: must be a positive number", field)
+	}
+	if price.GreaterThanOrEqual(maxPrice) {
+		return fmt.Errorf("Invalid %s: must be less than %s", field, maxPrice)
+	}
+	if !price.Equal(price.Round(2)) {
+		return fmt.Errorf("Invalid %s: at most 2 decimal places allowed", field)
+	}
+	return nil
+}
+
+// toModel converts a validated request into a product model
+func (req ProductRequest) toModel() *models.Product {
+	variants := make([]models.Variant, len(req.Variants))
+	for i, v := range req.Variants {
+		variants[i] = v.toModel()
+	}
+
+	prices := make([]models.ProductPrice, 0, len(req.Prices))
+	for currency, price := range req.Prices {
+		prices = append(prices, models.ProductPrice{CurrencyCode: currency, Price: price})
+	}
+
+	product := &models.Product{
+		Code:     req.Code,
+		Price:    req.Price,
+		Prices:   prices,
+		Category: models.Category{Code: req.Category},
+		Variants: variants,
+	}
+	if req.TaxClass != "" {
+		product.TaxClass = &models.TaxClass{Code: req.TaxClass}
+	}
+	return product
+}
+
+// productRequestFromModel builds the request representing the current product state
+func productRequestFromModel(p *models.Product) ProductRequest {
+	variants := make([]VariantRequest, len(p.Variants))
+	for i := range p.Variants {
+		variants[i] = variantRequestFromModel(&p.Variants[i])
+	}
+
+	prices := make(PriceOverrides, len(p.Prices))
+	for _, price := range p.Prices {
+		prices[price.CurrencyCode] = price.Price
+	}
+
+	req := ProductRequest{
+		Code:     p.Code,
+		Price:    p.Price,
+		Prices:   prices,
+		Category: p.Category.Code,
+		Variants: variants,
+	}
+	if p.TaxClass != nil {
+		req.TaxClass = p.TaxClass.Code
+	}
+	return req
+}
+
+// toModel converts a validated variant request into a variant model
+func (v VariantRequest) toModel() models.Variant {
+	prices := make([]models.VariantPrice, 0, len(v.Prices))
+	for currency, price := range v.Prices {
+		prices = append(prices, models.VariantPrice{CurrencyCode: currency, Price: price})
+	}
+
+	options := make([]models.VariantOption, 0, len(v.Options))
+	for _, code := range v.Options.codes() {
+		options = append(options, models.VariantOption{Attribute: &models.Attribute{Code: code}, Value: v.Options[code]})
+	}
+
+	return models.Variant{
+		Name:    v.Name,
+		SKU:     v.SKU,
+		Price:   v.Price,
+		Prices:  prices,
+		Options: options,
+	}
+}
+
+// variantRequestFromModel builds the request representing the current variant state
+func variantRequestFromModel(v *models.Variant) VariantRequest {
+	prices := make(PriceOverrides, len(v.Prices))
+	for _, price := range v.Prices {
+		prices[price.CurrencyCode] = price.Price
+	}
+
+	return VariantRequest{
+		Name:    v.Name,
+		SKU:     v.SKU,
+		Price:   v.Price,
+		Prices:  prices,
+		Options: mapVariantOptions(v),
+	}
+}
@@ -0,0 +1,302 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/testutil"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductCreateEndpoint(t *testing.T) {
+	mux, db := setupTestServer()
+
+	t.Run("POST /catalog creates product with variants", func(t *testing.T) {
+		testutil.CleanupProduct(t, db, "TEST_PROD_CREATE")
+
+		w := testutil.DoJSON(mux, http.MethodPost, "/catalog", map[string]any{
+			"code":     "TEST_PROD_CREATE",
+			"price":    "12.49",
+			"category": "SHOES",
+			"variants": []map[string]any{
+				{"name": "Small", "sku": "TEST_CREATE_S", "price": "13.00"},
+				{"name": "Large", "sku": "TEST_CREATE_L"},
+			},
+		})
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response ProductDetailsResponse
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.Equal(t, "TEST_PROD_CREATE", response.Code)
+		assert.Equal(t, 12.49, response.Price.Float())
+		assert.Equal(t, "SHOES", response.Category.Code)
+		assert.Len(t, response.Variants, 2)
+		assert.Equal(t, 13.0, response.Variants[0].Price.Float())
+		assert.Equal(t, 12.49, response.Variants[1].Price.Float(), "Variant without price should inherit")
+	})
+
+	t.Run("POST /catalog?priceFormat=string returns exact prices", func(t *testing.T) {
+		testutil.CleanupProduct(t, db, "TEST_PROD_EXACT")
+
+		w := testutil.DoJSON(mux, http.MethodPost, "/catalog?priceFormat=string", map[string]any{
+			"code":     "TEST_PROD_EXACT",
+			"price":    "12.49",
+			"category": "SHOES",
+			"variants": []map[string]any{
+				{"name": "Small", "sku": "TEST_EXACT_S"},
+			},
+		})
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var body map[string]any
+		err := json.NewDecoder(w.Body).Decode(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"amount": "12.49", "currency": "EUR"}, body["price"])
+		variant := body["variants"].([]any)[0].(map[string]any)
+		assert.Equal(t, map[string]any{"amount": "12.49", "currency": "EUR"}, variant["price"])
+	})
+
+	t.Run("POST /catalog stores currency price overrides", func(t *testing.T) {
+		testutil.CleanupProduct(t, db, "TEST_PROD_FX")
+
+		w := testutil.DoJSON(mux, http.MethodPost, "/catalog", map[string]any{
+			"code":     "TEST_PROD_FX",
+			"price":    "10.00",
+			"prices":   map[string]any{"GBP": "7.99"},
+			"category": "SHOES",
+			"variants": []map[string]any{
+				{"name": "Small", "sku": "TEST_FX_S", "prices": map[string]any{"GBP": "6.99"}},
+				{"name": "Large", "sku": "TEST_FX_L"},
+			},
+		})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		req := httptest.NewRequest(http.MethodGet, "/catalog/TEST_PROD_FX?currency=GBP", nil)
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response ProductDetailsResponse
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.Equal(t, "GBP", response.Currency)
+		assert.Equal(t, 7.99, response.Price.Float())
+		assert.Equal(t, 6.99, response.Variants[0].Price.Float())
+		assert.Equal(t, 7.99, response.Variants[1].Price.Float(), "Variant without override should inherit")
+	})
+
+	t.Run("POST /catalog returns 400 for unknown override currency", func(t *testing.T) {
+		testutil.CleanupProduct(t, db, "TEST_PROD_FX_BAD")
+
+		w := testutil.DoJSON(mux, http.MethodPost, "/catalog", map[string]any{
+			"code":     "TEST_PROD_FX_BAD",
+			"price":    "10.00",
+			"prices":   map[string]any{"JPY": "1500"},
+			"category": "SHOES",
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("POST /catalog returns 409 for duplicate code", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPost, "/catalog", map[string]any{
+			"code":     "PROD001",
+			"price":    "10.00",
+			"category": "SHOES",
+		})
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("POST /catalog returns 409 for duplicate SKU", func(t *testing.T) {
+		testutil.CleanupProduct(t, db, "TEST_PROD_DUP_SKU")
+
+		w := testutil.DoJSON(mux, http.MethodPost, "/catalog", map[string]any{
+			"code":     "TEST_PROD_DUP_SKU",
+			"price":    "10.00",
+			"category": "SHOES",
+			"variants": []map[string]any{{"name": "A", "sku": "SKU001A"}},
+		})
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("POST /catalog returns 400 for unknown category", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPost, "/catalog", map[string]any{
+			"code":     "TEST_PROD_BAD_CAT",
+			"price":    "10.00",
+			"category": "NONEXISTENT",
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("POST /catalog returns 400 for invalid JSON", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/catalog", bytes.NewBufferString("invalid json"))
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestValidateProductRequest(t *testing.T) {
+	valid := ProductRequest{
+		Code:     "PROD",
+		Price:    decimal.RequireFromString("10.00"),
+		Category: "SHOES",
+		Variants: []VariantRequest{{Name: "A", SKU: "SKU_A"}},
+	}
+
+	t.Run("accepts a valid request", func(t *testing.T) {
+		assert.NoError(t, validateProductRequest(valid))
+	})
+
+	cases := map[string]func(r *ProductRequest){
+		"missing code":        func(r *ProductRequest) { r.Code = "" },
+		"whitespace category": func(r *ProductRequest) { r.Category = "   " },
+		"code too long":       func(r *ProductRequest) { r.Code = "THIS_IS_A_VERY_LONG_PRODUCT_CODE_X" },
+		"zero price":          func(r *ProductRequest) { r.Price = decimal.RequireFromString("0") },
+		"negative price":      func(r *ProductRequest) { r.Price = decimal.RequireFromString("-1") },
+		"too many decimals":   func(r *ProductRequest) { r.Price = decimal.RequireFromString("1.999") },
+		"price too large":     func(r *ProductRequest) { r.Price = decimal.RequireFromString("100000000") },
+		"variant without sku": func(r *ProductRequest) { r.Variants = []VariantRequest{{Name: "A"}} },
+		"duplicate variant sku": func(r *ProductRequest) {
+			r.Variants = []VariantRequest{{Name: "A", SKU: "X"}, {Name: "B", SKU: "X"}}
+		},
+		"lowercase override currency": func(r *ProductRequest) {
+			r.Prices = PriceOverrides{"gbp": decimal.RequireFromString("9.99")}
+		},
+		"base currency override": func(r *ProductRequest) {
+			r.Prices = PriceOverrides{"EUR": decimal.RequireFromString("9.99")}
+		},
+		"negative variant override": func(r *ProductRequest) {
+			r.Variants = []VariantRequest{{Name: "A", SKU: "X", Prices: PriceOverrides{"USD": decimal.RequireFromString("-1")}}}
+		},
+	}
+
+	for name, mutate := range cases {
+		t.Run("rejects "+name, func(t *testing.T) {
+			req := valid
+			req.Variants = append([]VariantRequest(nil), valid.Variants...)
+			mutate(&req)
+			assert.Error(t, validateProductRequest(req))
+		})
+	}
+}
+
+func TestProductUpdateEndpoints(t *testing.T) {
+	mux, db := setupTestServer()
+
+	create := func(t *testing.T, code string) {
+		testutil.CleanupProduct(t, db, code)
+		w := testutil.DoJSON(mux, http.MethodPost, "/catalog", map[string]any{
+			"code":     code,
+			"price":    "20.00",
+			"category": "CLOTHING",
+			"variants": []map[string]any{
+				{"name": "A", "sku": code + "_A"},
+				{"name": "B", "sku": code + "_B", "price": "21.00"},
+			},
+		})
+		assert.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	t.Run("PUT /catalog/{code} replaces product and variants", func(t *testing.T) {
+		create(t, "TEST_PROD_PUT")
+
+		w := testutil.DoJSON(mux, http.MethodPut, "/catalog/TEST_PROD_PUT", map[string]any{
+			"price":    "25.00",
+			"category": "SHOES",
+			"variants": []map[string]any{
+				{"name": "A renamed", "sku": "TEST_PROD_PUT_A", "price": "26.00"},
+				{"name": "C", "sku": "TEST_PROD_PUT_C"},
+			},
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response ProductDetailsResponse
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.Equal(t, 25.0, response.Price.Float())
+		assert.Equal(t, "SHOES", response.Category.Code)
+
+		var variants []models.Variant
+		db.Joins("JOIN products ON products.id = product_variants.product_id").
+			Where("products.code = ?", "TEST_PROD_PUT").Order("sku").Find(&variants)
+		assert.Len(t, variants, 2)
+		assert.Equal(t, "A renamed", variants[0].Name)
+		assert.Equal(t, "TEST_PROD_PUT_C", variants[1].SKU)
+	})
+
+	t.Run("PATCH /catalog/{code} only changes provided fields", func(t *testing.T) {
+		create(t, "TEST_PROD_PATCH")
+
+		w := testutil.DoJSON(mux, http.MethodPatch, "/catalog/TEST_PROD_PATCH", map[string]any{
+			"price": "30.00",
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response ProductDetailsResponse
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.Equal(t, 30.0, response.Price.Float())
+		assert.Equal(t, "CLOTHING", response.Category.Code)
+		assert.Len(t, response.Variants, 2)
+	})
+
+	t.Run("PATCH /catalog/{code} returns 404 for unknown product", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPatch, "/catalog/NONEXISTENT", map[string]any{"price": "1.00"})
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("PUT /catalog/{code} returns 409 when renaming to an existing code", func(t *testing.T) {
+		create(t, "TEST_PROD_RENAME")
+
+		w := testutil.DoJSON(mux, http.MethodPut, "/catalog/TEST_PROD_RENAME", map[string]any{
+			"code":     "PROD001",
+			"price":    "25.00",
+			"category": "SHOES",
+		})
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestProductDeleteEndpoint(t *testing.T) {
+	mux, db := setupTestServer()
+
+	t.Run("DELETE /catalog/{code} removes product", func(t *testing.T) {
+		testutil.CleanupProduct(t, db, "TEST_PROD_DELETE")
+		w := testutil.DoJSON(mux, http.MethodPost, "/catalog", map[string]any{
+			"code":     "TEST_PROD_DELETE",
+			"price":    "5.00",
+			"category": "SHOES",
+		})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = testutil.DoJSON(mux, http.MethodDelete, "/catalog/TEST_PROD_DELETE", nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		w = testutil.DoJSON(mux, http.MethodGet, "/catalog/TEST_PROD_DELETE", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("DELETE /catalog/{code} returns 404 for unknown product", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodDelete, "/catalog/NONEXISTENT", nil)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
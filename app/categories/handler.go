@@ -3,34 +3,96 @@ package categories
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"regexp"
 	"strings"
 
 	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/catalog"
 	"github.com/mytheresa/go-hiring-challenge/models"
 )
 
 type CategoryResponse struct {
-	Code string `json:"code"`
-	Name string `json:"name"`
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Parent   string `json:"parent,omitempty"`
+	TaxClass string `json:"tax_class,omitempty"`
 }
 
+// CategoryTreeResponse represents a category with its nested subcategories
+type CategoryTreeResponse struct {
+	Code     string                 `json:"code"`
+	Name     string                 `json:"name"`
+	Children []CategoryTreeResponse `json:"children"`
+}
+
+// CreateCategoryRequest is the body accepted by POST /categories. Without a
+// tax class the category inherits the class of its parent.
 type CreateCategoryRequest struct {
-	Code string `json:"code"`
-	Name string `json:"name"`
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Parent   string `json:"parent,omitempty"`
+	TaxClass string `json:"tax_class,omitempty"`
+}
+
+// PatchCategoryRequest is the body accepted by PATCH /categories/{code}.
+// Only the fields present in the body are changed; an empty parent moves
+// the category to the root of the tree and an empty tax class makes it
+// inherit the class of its parent.
+type PatchCategoryRequest struct {
+	Code     *string `json:"code"`
+	Name     *string `json:"name"`
+	Parent   *string `json:"parent"`
+	TaxClass *string `json:"tax_class"`
+}
+
+// attributeCodePattern keeps attribute codes usable in ?attr.<code>= filters
+var attributeCodePattern = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// AttributeRequest is the body accepted by POST /categories/{code}/attributes.
+// Type is text or number; position orders the option axes of products.
+type AttributeRequest struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Position int    `json:"position"`
+}
+
+// AttributeResponse represents an attribute and the category defining it
+type AttributeResponse struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Position int    `json:"position"`
+	Category string `json:"category"`
 }
 
 type CategoriesHandler struct {
-	repo models.CategoryRepository
+	repo       models.CategoryRepository
+	products   models.ProductRepository
+	currencies *models.CurrenciesRepository
+	taxes      *models.TaxesRepository
 }
 
-func NewCategoriesHandler(repo models.CategoryRepository) *CategoriesHandler {
-	return &CategoriesHandler{repo: repo}
+// NewCategoriesHandler creates the categories handler. The currencies and
+// taxes repositories price the listed products like the catalog does.
+func NewCategoriesHandler(repo models.CategoryRepository, products models.ProductRepository, currencies *models.CurrenciesRepository, taxes *models.TaxesRepository) *CategoriesHandler {
+	return &CategoriesHandler{repo: repo, products: products, currencies: currencies, taxes: taxes}
 }
 
+// HandleList handles GET /categories. With ?format=tree the categories are
+// returned nested under their parents instead of as a flat list.
 func (h *CategoriesHandler) HandleList(w http.ResponseWriter, r *http.Request) {
-	slog.Info("Fetching all categories")
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "flat" && format != "tree" {
+		slog.Warn("Invalid format parameter", "value", format)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid format: must be flat or tree")
+		return
+	}
+
+	slog.Info("Fetching all categories", "format", format)
 
 	categories, err := h.repo.GetAllCategories()
 	if err != nil {
@@ -41,12 +103,28 @@ func (h *CategoriesHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 
 	slog.Info("Successfully fetched categories", "count", len(categories))
 
+	if format == "tree" {
+		api.OKResponse(w, buildCategoryTree(categories))
+		return
+	}
+
+	codes := make(map[uint]string, len(categories))
+	for _, cat := range categories {
+		codes[cat.ID] = cat.Code
+	}
+
 	response := make([]CategoryResponse, len(categories))
 	for i, cat := range categories {
 		response[i] = CategoryResponse{
 			Code: cat.Code,
 			Name: cat.Name,
 		}
+		if cat.ParentID != nil {
+			response[i].Parent = codes[*cat.ParentID]
+		}
+		if cat.TaxClass != nil {
+			response[i].TaxClass = cat.TaxClass.Code
+		}
 	}
 
 	api.OKResponse(w, response)
@@ -60,29 +138,9 @@ func (h *CategoriesHandler) HandleCreate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Validate required fields
-	if req.Code == "" || req.Name == "" {
-		slog.Warn("Missing required fields", "code", req.Code, "name", req.Name)
-		api.ErrorResponse(w, http.StatusBadRequest, "Code and name are required")
-		return
-	}
-
-	// Validate non-whitespace
-	if strings.TrimSpace(req.Code) == "" || strings.TrimSpace(req.Name) == "" {
-		slog.Warn("Whitespace-only fields", "code", req.Code, "name", req.Name)
-		api.ErrorResponse(w, http.StatusBadRequest, "Code and name cannot be empty or whitespace only")
-		return
-	}
-
-	// Validate max length (code: 50 chars, name: 255 chars)
-	if len(req.Code) > 50 {
-		slog.Warn("Code too long", "code", req.Code, "length", len(req.Code))
-		api.ErrorResponse(w, http.StatusBadRequest, "Code too long: maximum 50 characters")
-		return
-	}
-	if len(req.Name) > 255 {
-		slog.Warn("Name too long", "name", req.Name, "length", len(req.Name))
-		api.ErrorResponse(w, http.StatusBadRequest, "Name too long: maximum 255 characters")
+	if err := validateCategoryRequest(req.Code, req.Name); err != nil {
+		slog.Warn("Invalid category request", "code", req.Code, "name", req.Name, "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -92,6 +150,12 @@ func (h *CategoriesHandler) HandleCreate(w http.ResponseWriter, r *http.Request)
 		Code: req.Code,
 		Name: req.Name,
 	}
+	if req.Parent != "" {
+		category.Parent = &models.Category{Code: req.Parent}
+	}
+	if req.TaxClass != "" {
+		category.TaxClass = &models.TaxClass{Code: req.TaxClass}
+	}
 
 	if err := h.repo.CreateCategory(category); err != nil {
 		if errors.Is(err, models.ErrCategoryCodeExists) {
@@ -99,11 +163,16 @@ func (h *CategoriesHandler) HandleCreate(w http.ResponseWriter, r *http.Request)
 			api.ErrorResponse(w, http.StatusConflict, "Category code already exists")
 			return
 		}
-		if errors.Is(err, models.ErrInvalidCategory) {
+		if errors.Is(err, models.ErrInvalidCategory) || errors.Is(err, models.ErrInvalidCategoryParent) {
 			slog.Warn("Invalid category", "code", req.Code, "error", err)
 			api.ErrorResponse(w, http.StatusBadRequest, err.Error())
 			return
 		}
+		if errors.Is(err, models.ErrTaxClassNotFound) {
+			slog.Warn("Category tax class not found", "code", req.Code, "taxClass", req.TaxClass)
+			api.ErrorResponse(w, http.StatusBadRequest, "Tax class not found")
+			return
+		}
 		slog.Error("Failed to create category", "code", req.Code, "error", err)
 		api.ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -111,11 +180,356 @@ func (h *CategoriesHandler) HandleCreate(w http.ResponseWriter, r *http.Request)
 
 	slog.Info("Successfully created category", "code", category.Code)
 
+	// Return 201 Created with JSON response
+	api.CreatedResponse(w, mapCategoryResponse(category))
+}
+
+// HandleGet handles GET /categories/{code}
+func (h *CategoriesHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+
+	slog.Info("Fetching category", "code", code)
+
+	category, err := h.repo.GetCategoryByCode(code)
+	if err != nil {
+		writeCategoryError(w, code, err)
+		return
+	}
+
+	api.OKResponse(w, mapCategoryResponse(category))
+}
+
+// HandlePatch handles PATCH /categories/{code} - updates code and/or name
+func (h *CategoriesHandler) HandlePatch(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+
+	var patch PatchCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		slog.Warn("Invalid request body", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	existing, err := h.repo.GetCategoryByCode(code)
+	if err != nil {
+		writeCategoryError(w, code, err)
+		return
+	}
+
+	category := &models.Category{
+		Code:     existing.Code,
+		Name:     existing.Name,
+		Parent:   existing.Parent,
+		TaxClass: existing.TaxClass,
+	}
+	if patch.Parent != nil {
+		category.Parent = &models.Category{Code: *patch.Parent}
+	}
+	if patch.TaxClass != nil {
+		category.TaxClass = &models.TaxClass{Code: *patch.TaxClass}
+	}
+	if patch.Code != nil {
+		category.Code = *patch.Code
+	}
+	if patch.Name != nil {
+		category.Name = *patch.Name
+	}
+
+	if err := validateCategoryRequest(category.Code, category.Name); err != nil {
+		slog.Warn("Invalid category request", "code", code, "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slog.Info("Updating category", "code", code, "newCode", category.Code, "name", category.Name)
+
+	if err := h.repo.UpdateCategory(code, category); err != nil {
+		writeCategoryError(w, code, err)
+		return
+	}
+
+	slog.Info("Successfully updated category", "code", category.Code)
+
+	api.OKResponse(w, mapCategoryResponse(category))
+}
+
+// HandleDelete handles DELETE /categories/{code}.
+// Deletion is refused while products reference the category unless
+// ?reassignTo=OTHER is given, in which case those products are moved first.
+func (h *CategoriesHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	reassignTo := r.URL.Query().Get("reassignTo")
+
+	if reassignTo != "" {
+		if reassignTo == code {
+			slog.Warn("Cannot reassign products to the deleted category", "code", code)
+			api.ErrorResponse(w, http.StatusBadRequest, "reassignTo must be a different category")
+			return
+		}
+		if _, err := h.repo.GetCategoryByCode(reassignTo); err != nil {
+			if errors.Is(err, models.ErrCategoryNotFound) {
+				slog.Warn("Reassign target category not found", "code", code, "reassignTo", reassignTo)
+				api.ErrorResponse(w, http.StatusBadRequest, "reassignTo category not found")
+				return
+			}
+			writeCategoryError(w, code, err)
+			return
+		}
+	}
+
+	slog.Info("Deleting category", "code", code, "reassignTo", reassignTo)
+
+	if err := h.repo.DeleteCategory(code, reassignTo); err != nil {
+		writeCategoryError(w, code, err)
+		return
+	}
+
+	slog.Info("Successfully deleted category", "code", code)
+
+	api.NoContentResponse(w)
+}
+
+// HandleListProducts handles GET /categories/{code}/products with offset pagination
+func (h *CategoriesHandler) HandleListProducts(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	offset, limit := api.ParsePagination(r)
+
+	pricing, ok := catalog.RequestPricing(w, r, h.currencies, h.taxes)
+	if !ok {
+		return
+	}
+
+	if _, err := h.repo.GetCategoryByCode(code); err != nil {
+		writeCategoryError(w, code, err)
+		return
+	}
+
+	filters := models.ProductFilters{
+		Offset:       offset,
+		Limit:        limit,
+		CategoryCode: code,
+	}
+
+	slog.Info("Fetching category products", "code", code, "offset", offset, "limit", limit)
+
+	products, total, err := h.products.GetProductsWithFilters(filters)
+	if err != nil {
+		slog.Error("Failed to fetch category products", "code", code, "error", err)
+		api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	slog.Info("Successfully fetched category products", "code", code, "count", len(products), "total", total)
+
+	api.OKResponse(w, catalog.MapProductsResponse(products, total, pricing))
+}
+
+// HandleListAttributes handles GET /categories/{code}/attributes - lists the
+// attributes of the category, including the ones inherited from its ancestors
+func (h *CategoriesHandler) HandleListAttributes(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+
+	slog.Info("Fetching category attributes", "code", code)
+
+	attributes, err := h.repo.GetCategoryAttributes(code)
+	if err != nil {
+		writeCategoryError(w, code, err)
+		return
+	}
+
+	response := make([]AttributeResponse, len(attributes))
+	for i := range attributes {
+		response[i] = mapAttributeResponse(&attributes[i])
+	}
+
+	api.OKResponse(w, response)
+}
+
+// HandleCreateAttribute handles POST /categories/{code}/attributes
+func (h *CategoriesHandler) HandleCreateAttribute(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+
+	var req AttributeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Warn("Invalid request body", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := validateAttributeRequest(req); err != nil {
+		slog.Warn("Invalid attribute request", "code", code, "attribute", req.Code, "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slog.Info("Creating category attribute", "code", code, "attribute", req.Code, "type", req.Type)
+
+	attribute := &models.Attribute{
+		Code:     req.Code,
+		Name:     req.Name,
+		DataType: req.Type,
+		Position: req.Position,
+	}
+	if err := h.repo.CreateAttribute(code, attribute); err != nil {
+		writeCategoryError(w, code, err)
+		return
+	}
+
+	slog.Info("Successfully created category attribute", "code", code, "attribute", attribute.Code)
+
+	api.CreatedResponse(w, mapAttributeResponse(attribute))
+}
+
+// HandleDeleteAttribute handles DELETE /categories/{code}/attributes/{attribute}.
+// The options variants hold for the attribute are deleted with it.
+func (h *CategoriesHandler) HandleDeleteAttribute(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	attribute := r.PathValue("attribute")
+
+	slog.Info("Deleting category attribute", "code", code, "attribute", attribute)
+
+	if err := h.repo.DeleteAttribute(code, attribute); err != nil {
+		writeCategoryError(w, code, err)
+		return
+	}
+
+	slog.Info("Successfully deleted category attribute", "code", code, "attribute", attribute)
+
+	api.NoContentResponse(w)
+}
+
+// validateAttributeRequest checks the fields of an attribute write request.
+// The returned error message is safe to send to the client.
+func validateAttributeRequest(req AttributeRequest) error {
+	if !attributeCodePattern.MatchString(req.Code) {
+		return errors.New("Invalid code: must be 1 to 32 lowercase letters, digits or underscores")
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return errors.New("Name is required")
+	}
+	if len(req.Name) > 255 {
+		return errors.New("Name too long: maximum 255 characters")
+	}
+	if req.Type != models.AttributeText && req.Type != models.AttributeNumber {
+		return fmt.Errorf("Invalid type: must be %s or %s", models.AttributeText, models.AttributeNumber)
+	}
+	return nil
+}
+
+// mapAttributeResponse maps an attribute model to its API response
+func mapAttributeResponse(attribute *models.Attribute) AttributeResponse {
+	response := AttributeResponse{
+		Code:     attribute.Code,
+		Name:     attribute.Name,
+		Type:     attribute.DataType,
+		Position: attribute.Position,
+	}
+	if attribute.Category != nil {
+		response.Category = attribute.Category.Code
+	}
+	return response
+}
+
+// mapCategoryResponse maps a category model to its API response
+func mapCategoryResponse(category *models.Category) CategoryResponse {
 	response := CategoryResponse{
 		Code: category.Code,
 		Name: category.Name,
 	}
+	if category.Parent != nil {
+		response.Parent = category.Parent.Code
+	}
+	if category.TaxClass != nil {
+		response.TaxClass = category.TaxClass.Code
+	}
+	return response
+}
 
-	// Return 201 Created with JSON response
-	api.CreatedResponse(w, response)
+// buildCategoryTree nests categories under their parents. Categories whose
+// parent is missing from the list are treated as roots.
+func buildCategoryTree(categories []models.Category) []CategoryTreeResponse {
+	known := make(map[uint]bool, len(categories))
+	children := make(map[uint][]models.Category, len(categories))
+	for _, cat := range categories {
+		known[cat.ID] = true
+	}
+
+	var roots []models.Category
+	for _, cat := range categories {
+		if cat.ParentID == nil || !known[*cat.ParentID] {
+			roots = append(roots, cat)
+			continue
+		}
+		children[*cat.ParentID] = append(children[*cat.ParentID], cat)
+	}
+
+	var build func(nodes []models.Category) []CategoryTreeResponse
+	build = func(nodes []models.Category) []CategoryTreeResponse {
+		tree := make([]CategoryTreeResponse, len(nodes))
+		for i, node := range nodes {
+			tree[i] = CategoryTreeResponse{
+				Code:     node.Code,
+				Name:     node.Name,
+				Children: build(children[node.ID]),
+			}
+		}
+		return tree
+	}
+
+	return build(roots)
+}
+
+// writeCategoryError maps repository errors to HTTP responses
+func writeCategoryError(w http.ResponseWriter, code string, err error) {
+	switch {
+	case errors.Is(err, models.ErrCategoryNotFound):
+		slog.Warn("Category not found", "code", code)
+		api.ErrorResponse(w, http.StatusNotFound, "Category not found")
+	case errors.Is(err, models.ErrCategoryCodeExists):
+		slog.Warn("Duplicate category code", "code", code)
+		api.ErrorResponse(w, http.StatusConflict, "Category code already exists")
+	case errors.Is(err, models.ErrCategoryInUse):
+		slog.Warn("Category still referenced by products", "code", code)
+		api.ErrorResponse(w, http.StatusConflict, "Category is referenced by products: pass reassignTo to move them")
+	case errors.Is(err, models.ErrCategoryHasDependents):
+		slog.Warn("Category still referenced by attributes or promotions", "code", code)
+		api.ErrorResponse(w, http.StatusConflict, "Category is referenced by attributes or promotions: delete them first")
+	case errors.Is(err, models.ErrInvalidCategory), errors.Is(err, models.ErrInvalidCategoryParent):
+		slog.Warn("Invalid category", "code", code, "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, models.ErrTaxClassNotFound):
+		slog.Warn("Category tax class not found", "code", code)
+		api.ErrorResponse(w, http.StatusBadRequest, "Tax class not found")
+	case errors.Is(err, models.ErrAttributeNotFound):
+		slog.Warn("Category attribute not found", "code", code)
+		api.ErrorResponse(w, http.StatusNotFound, "Attribute not found")
+	case errors.Is(err, models.ErrAttributeCodeExists):
+		slog.Warn("Duplicate attribute code", "code", code)
+		api.ErrorResponse(w, http.StatusConflict, "Attribute code already exists in the category tree")
+	case errors.Is(err, models.ErrInvalidAttribute):
+		slog.Warn("Invalid attribute", "code", code, "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+	default:
+		slog.Error("Failed to process category", "code", code, "error", err)
+		api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+// validateCategoryRequest checks required, non-whitespace and maximum length
+// (code: 32 chars, name: 255 chars) rules for category fields. The limits
+// stay within the categories columns.
+func validateCategoryRequest(code, name string) error {
+	if code == "" || name == "" {
+		return errors.New("Code and name are required")
+	}
+	if strings.TrimSpace(code) == "" || strings.TrimSpace(name) == "" {
+		return errors.New("Code and name cannot be empty or whitespace only")
+	}
+	if len(code) > 32 {
+		return errors.New("Code too long: maximum 32 characters")
+	}
+	if len(name) > 255 {
+		return errors.New("Name too long: maximum 255 characters")
+	}
+	return nil
 }
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/mytheresa/go-hiring-challenge/app/catalog"
 	"github.com/mytheresa/go-hiring-challenge/internal/testutil"
 	"github.com/mytheresa/go-hiring-challenge/models"
 	"github.com/stretchr/testify/assert"
@@ -17,11 +19,18 @@ func setupTestServer(t *testing.T) (*http.ServeMux, *gorm.DB) {
 	db := testutil.SetupTestDB()
 
 	repo := models.NewCategoriesRepository(db)
-	handler := NewCategoriesHandler(repo)
+	handler := NewCategoriesHandler(repo, models.NewProductsRepository(db), models.NewCurrenciesRepository(db), models.NewTaxesRepository(db))
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /categories", handler.HandleList)
 	mux.HandleFunc("POST /categories", handler.HandleCreate)
+	mux.HandleFunc("GET /categories/{code}", handler.HandleGet)
+	mux.HandleFunc("PATCH /categories/{code}", handler.HandlePatch)
+	mux.HandleFunc("DELETE /categories/{code}", handler.HandleDelete)
+	mux.HandleFunc("GET /categories/{code}/products", handler.HandleListProducts)
+	mux.HandleFunc("GET /categories/{code}/attributes", handler.HandleListAttributes)
+	mux.HandleFunc("POST /categories/{code}/attributes", handler.HandleCreateAttribute)
+	mux.HandleFunc("DELETE /categories/{code}/attributes/{attribute}", handler.HandleDeleteAttribute)
 
 	return mux, db
 }
@@ -201,7 +210,8 @@ func TestCategoriesEndpoint_Create(t *testing.T) {
 
 	t.Run("POST /categories returns 400 for code too long", func(t *testing.T) {
 		requestBody := CreateCategoryRequest{
-			Code: "THIS_IS_A_VERY_LONG_CODE_THAT_EXCEEDS_THE_MAXIMUM_LENGTH_ALLOWED_FOR_CATEGORY_CODES",
+			// One character over the VARCHAR(32) column
+			Code: strings.Repeat("C", 33),
 			Name: "Test",
 		}
 
@@ -247,3 +257,358 @@ func TestCategoriesEndpoint_Create(t *testing.T) {
 		assert.Contains(t, errorResponse["error"], "too long")
 	})
 }
+
+// createTestCategory inserts a category and removes it after the test
+func createTestCategory(t *testing.T, db *gorm.DB, code, name string) {
+	db.Where("code = ?", code).Delete(&models.Category{})
+	assert.NoError(t, db.Create(&models.Category{Code: code, Name: name}).Error)
+	t.Cleanup(func() {
+		db.Where("code = ?", code).Delete(&models.Category{})
+	})
+}
+
+func TestCategoriesEndpoint_Get(t *testing.T) {
+	mux, _ := setupTestServer(t)
+
+	t.Run("GET /categories/{code} returns category", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/categories/SHOES", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response CategoryResponse
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.Equal(t, "SHOES", response.Code)
+		assert.Equal(t, "Shoes", response.Name)
+	})
+
+	t.Run("GET /categories/{code} returns 404 for unknown category", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/categories/NONEXISTENT", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestCategoriesEndpoint_Patch(t *testing.T) {
+	mux, db := setupTestServer(t)
+
+	t.Run("PATCH /categories/{code} updates name", func(t *testing.T) {
+		createTestCategory(t, db, "TEST_PATCH", "Before")
+
+		body, _ := json.Marshal(map[string]string{"name": "After"})
+		req := httptest.NewRequest(http.MethodPatch, "/categories/TEST_PATCH", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var updated models.Category
+		err := db.Where("code = ?", "TEST_PATCH").First(&updated).Error
+		assert.NoError(t, err)
+		assert.Equal(t, "After", updated.Name)
+	})
+
+	t.Run("PATCH /categories/{code} returns 409 when renaming to an existing code", func(t *testing.T) {
+		createTestCategory(t, db, "TEST_PATCH_DUP", "Dup")
+
+		body, _ := json.Marshal(map[string]string{"code": "SHOES"})
+		req := httptest.NewRequest(http.MethodPatch, "/categories/TEST_PATCH_DUP", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("PATCH /categories/{code} returns 400 for whitespace-only name", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]string{"name": "   "})
+		req := httptest.NewRequest(http.MethodPatch, "/categories/SHOES", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("PATCH /categories/{code} returns 404 for unknown category", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]string{"name": "Any"})
+		req := httptest.NewRequest(http.MethodPatch, "/categories/NONEXISTENT", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestCategoriesEndpoint_Delete(t *testing.T) {
+	mux, db := setupTestServer(t)
+
+	t.Run("DELETE /categories/{code} removes unused category", func(t *testing.T) {
+		createTestCategory(t, db, "TEST_DELETE", "Delete")
+
+		req := httptest.NewRequest(http.MethodDelete, "/categories/TEST_DELETE", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("DELETE /categories/{code} returns 409 when products reference it", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/categories/SHOES", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("DELETE /categories/{code} returns 400 for unknown reassign target", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/categories/SHOES?reassignTo=NONEXISTENT", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("DELETE /categories/{code} returns 404 for unknown category", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/categories/NONEXISTENT", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestCategoriesEndpoint_ListProducts(t *testing.T) {
+	mux, _ := setupTestServer(t)
+
+	t.Run("GET /categories/{code}/products returns products of category", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/categories/CLOTHING/products?limit=2", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response catalog.Response
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, len(response.Products), 2)
+		assert.Greater(t, response.Total, int64(0))
+		for _, p := range response.Products {
+			assert.Equal(t, "CLOTHING", p.Category.Code)
+		}
+	})
+
+	t.Run("GET /categories/{code}/products?currency=USD converts prices", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/categories/CLOTHING/products?limit=1&currency=usd", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response catalog.Response
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, "USD", response.Currency)
+	})
+
+	t.Run("GET /categories/{code}/products rejects unsupported currency and country", func(t *testing.T) {
+		for _, query := range []string{"currency=XXX", "country=XX"} {
+			req := httptest.NewRequest(http.MethodGet, "/categories/CLOTHING/products?"+query, nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+
+	t.Run("GET /categories/{code}/products returns 404 for unknown category", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/categories/NONEXISTENT/products", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	root, child := uint(1), uint(2)
+	categories := []models.Category{
+		{ID: 1, Code: "SHOES", Name: "Shoes"},
+		{ID: 2, Code: "SNEAKERS", Name: "Sneakers", ParentID: &root},
+		{ID: 3, Code: "RUNNING", Name: "Running", ParentID: &child},
+		{ID: 4, Code: "BAGS", Name: "Bags"},
+	}
+
+	tree := buildCategoryTree(categories)
+
+	assert.Len(t, tree, 2)
+	assert.Equal(t, "SHOES", tree[0].Code)
+	assert.Len(t, tree[0].Children, 1)
+	assert.Equal(t, "SNEAKERS", tree[0].Children[0].Code)
+	assert.Equal(t, "RUNNING", tree[0].Children[0].Children[0].Code)
+	assert.Empty(t, tree[1].Children)
+}
+
+func TestCategoriesEndpoint_Hierarchy(t *testing.T) {
+	mux, db := setupTestServer(t)
+
+	post := func(t *testing.T, req CreateCategoryRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		r := httptest.NewRequest(http.MethodPost, "/categories", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w
+	}
+
+	// Children must be removed before their parent
+	for _, code := range []string{"TEST_TREE_ROOT", "TEST_TREE_CHILD"} {
+		code := code
+		t.Cleanup(func() {
+			db.Where("code = ?", code).Delete(&models.Category{})
+		})
+	}
+	db.Where("code = ?", "TEST_TREE_CHILD").Delete(&models.Category{})
+	db.Where("code = ?", "TEST_TREE_ROOT").Delete(&models.Category{})
+
+	t.Run("POST /categories creates nested category", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, post(t, CreateCategoryRequest{Code: "TEST_TREE_ROOT", Name: "Root"}).Code)
+
+		w := post(t, CreateCategoryRequest{Code: "TEST_TREE_CHILD", Name: "Child", Parent: "TEST_TREE_ROOT"})
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response CategoryResponse
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.Equal(t, "TEST_TREE_ROOT", response.Parent)
+	})
+
+	t.Run("POST /categories returns 400 for unknown parent", func(t *testing.T) {
+		w := post(t, CreateCategoryRequest{Code: "TEST_TREE_ORPHAN", Name: "Orphan", Parent: "NONEXISTENT"})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("GET /categories?format=tree nests children", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/categories?format=tree", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []CategoryTreeResponse
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+
+		var root *CategoryTreeResponse
+		for i := range response {
+			if response[i].Code == "TEST_TREE_ROOT" {
+				root = &response[i]
+			}
+		}
+		if assert.NotNil(t, root, "Root category should be at the top level") {
+			assert.Len(t, root.Children, 1)
+			assert.Equal(t, "TEST_TREE_CHILD", root.Children[0].Code)
+		}
+	})
+
+	t.Run("GET /categories returns 400 for unknown format", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/categories?format=xml", nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("PATCH /categories/{code} rejects moving a category below its descendant", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]string{"parent": "TEST_TREE_CHILD"})
+		req := httptest.NewRequest(http.MethodPatch, "/categories/TEST_TREE_ROOT", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestValidateAttributeRequest(t *testing.T) {
+	assert.NoError(t, validateAttributeRequest(AttributeRequest{Code: "heel_height", Name: "Heel height", Type: "number"}))
+
+	assert.Error(t, validateAttributeRequest(AttributeRequest{Code: "Heel Height", Name: "Heel height", Type: "number"}))
+	assert.Error(t, validateAttributeRequest(AttributeRequest{Code: "heel_height", Name: " ", Type: "number"}))
+	assert.Error(t, validateAttributeRequest(AttributeRequest{Code: "heel_height", Name: "Heel height", Type: "date"}))
+}
+
+func TestCategoriesEndpoint_Attributes(t *testing.T) {
+	mux, db := setupTestServer(t)
+
+	do := func(method, path string, body any) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		r := httptest.NewRequest(method, path, bytes.NewBuffer(payload))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w
+	}
+
+	createTestCategory(t, db, "TEST_ATTR_ROOT", "Root")
+	db.Where("code = ?", "TEST_ATTR_CHILD").Delete(&models.Category{})
+	assert.Equal(t, http.StatusCreated, do(http.MethodPost, "/categories",
+		CreateCategoryRequest{Code: "TEST_ATTR_CHILD", Name: "Child", Parent: "TEST_ATTR_ROOT"}).Code)
+	t.Cleanup(func() {
+		db.Where("code = ?", "TEST_ATTR_CHILD").Delete(&models.Category{})
+	})
+
+	t.Run("POST /categories/{code}/attributes defines an attribute", func(t *testing.T) {
+		w := do(http.MethodPost, "/categories/TEST_ATTR_ROOT/attributes",
+			AttributeRequest{Code: "fit", Name: "Fit", Type: "text", Position: 1})
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response AttributeResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, AttributeResponse{Code: "fit", Name: "Fit", Type: "text", Position: 1, Category: "TEST_ATTR_ROOT"}, response)
+	})
+
+	t.Run("POST /categories/{code}/attributes rejects codes used along the branch", func(t *testing.T) {
+		w := do(http.MethodPost, "/categories/TEST_ATTR_CHILD/attributes",
+			AttributeRequest{Code: "fit", Name: "Fit", Type: "text"})
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("GET /categories/{code}/attributes includes inherited attributes", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, do(http.MethodPost, "/categories/TEST_ATTR_CHILD/attributes",
+			AttributeRequest{Code: "length", Name: "Length", Type: "number", Position: 2}).Code)
+
+		w := do(http.MethodGet, "/categories/TEST_ATTR_CHILD/attributes", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []AttributeResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		if assert.Len(t, response, 2) {
+			assert.Equal(t, "fit", response[0].Code)
+			assert.Equal(t, "TEST_ATTR_ROOT", response[0].Category)
+			assert.Equal(t, "length", response[1].Code)
+		}
+	})
+
+	t.Run("DELETE /categories/{code}/attributes/{attribute} only deletes own attributes", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, "/categories/TEST_ATTR_CHILD/attributes/fit", nil).Code)
+		assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/categories/TEST_ATTR_ROOT/attributes/fit", nil).Code)
+	})
+}
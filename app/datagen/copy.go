@@ -0,0 +1,150 @@
+package datagen
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+// Write loads the generated catalog with COPY within tx. IDs are reserved
+// from the table sequences, so the catalog can be loaded next to existing
+// rows as long as the codes are free. The user triggers of products and
+// variants are disabled while loading: search documents are refreshed in
+// one pass at the end and no price history is recorded.
+func Write(ctx context.Context, tx pgx.Tx, g *Generator) error {
+	for _, table := range []string{"products", "product_variants"} {
+		if _, err := tx.Exec(ctx, fmt.Sprintf("ALTER TABLE %s DISABLE TRIGGER USER", table)); err != nil {
+			return err
+		}
+	}
+
+	categories := g.Categories()
+	categoryBase, err := reserveIDs(ctx, tx, "categories", len(categories))
+	if err != nil {
+		return err
+	}
+	productBase, err := reserveIDs(ctx, tx, "products", g.config.Products)
+	if err != nil {
+		return err
+	}
+
+	var warehouseID int64
+	if err := tx.QueryRow(ctx, "SELECT id FROM warehouses WHERE is_default").Scan(&warehouseID); err != nil {
+		return fmt.Errorf("finding the default warehouse: %w", err)
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"categories"}, []string{"id", "code", "name", "parent_id"},
+		pgx.CopyFromSlice(len(categories), func(i int) ([]any, error) {
+			c := categories[i]
+			var parentID any
+			if c.ParentID != 0 {
+				parentID = categoryBase + c.ParentID
+			}
+			return []any{categoryBase + c.ID, c.Code, c.Name, parentID}, nil
+		}))
+	if err != nil {
+		return fmt.Errorf("copying categories: %w", err)
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"products"}, []string{"id", "code", "price", "category_id"},
+		g.products(func(p Product) [][]any {
+			return [][]any{{productBase + p.ID, p.Code, numeric(p.Price), categoryBase + p.CategoryID}}
+		}))
+	if err != nil {
+		return fmt.Errorf("copying products: %w", err)
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"product_variants"}, []string{"product_id", "name", "sku", "price"},
+		g.products(func(p Product) [][]any {
+			rows := make([][]any, len(p.Variants))
+			for i, v := range p.Variants {
+				var price any
+				if v.Price.Valid {
+					price = numeric(v.Price.Decimal)
+				}
+				rows[i] = []any{productBase + p.ID, v.Name, v.SKU, price}
+			}
+			return rows
+		}))
+	if err != nil {
+		return fmt.Errorf("copying variants: %w", err)
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"stock_levels"}, []string{"sku", "warehouse_id", "on_hand"},
+		g.products(func(p Product) [][]any {
+			rows := make([][]any, len(p.Variants))
+			for i, v := range p.Variants {
+				rows[i] = []any{v.SKU, warehouseID, v.Stock}
+			}
+			return rows
+		}))
+	if err != nil {
+		return fmt.Errorf("copying stock levels: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, "UPDATE products SET search_vector = product_search_document(id) WHERE id BETWEEN $1 AND $2",
+		productBase+1, productBase+int64(g.config.Products)); err != nil {
+		return fmt.Errorf("refreshing search documents: %w", err)
+	}
+
+	for _, table := range []string{"products", "product_variants"} {
+		if _, err := tx.Exec(ctx, fmt.Sprintf("ALTER TABLE %s ENABLE TRIGGER USER", table)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// products streams the rows that rows maps every generated product to
+func (g *Generator) products(rows func(Product) [][]any) pgx.CopyFromSource {
+	return &productRows{generator: g, rows: rows}
+}
+
+// productRows is a COPY source generating products on the fly, so the
+// catalog is never held in memory
+type productRows struct {
+	generator *Generator
+	rows      func(Product) [][]any
+	next      int
+	pending   [][]any
+	current   []any
+}
+
+func (r *productRows) Next() bool {
+	for len(r.pending) == 0 {
+		if r.next >= r.generator.config.Products {
+			return false
+		}
+		r.pending = r.rows(r.generator.Product(r.next))
+		r.next++
+	}
+	r.current, r.pending = r.pending[0], r.pending[1:]
+	return true
+}
+
+func (r *productRows) Values() ([]any, error) {
+	return r.current, nil
+}
+
+func (r *productRows) Err() error {
+	return nil
+}
+
+// reserveIDs reserves n consecutive IDs of the serial id column of table
+// and returns the ID preceding them
+func reserveIDs(ctx context.Context, tx pgx.Tx, table string, n int) (int64, error) {
+	var base int64
+	err := tx.QueryRow(ctx, `SELECT nextval(pg_get_serial_sequence($1, 'id')) - 1`, table).Scan(&base)
+	if err != nil || n <= 1 {
+		return base, err
+	}
+	_, err = tx.Exec(ctx, `SELECT setval(pg_get_serial_sequence($1, 'id'), $2)`, table, base+int64(n))
+	return base, err
+}
+
+func numeric(d decimal.Decimal) pgtype.Numeric {
+	return pgtype.Numeric{Int: d.Coefficient(), Exp: d.Exponent(), Valid: true}
+}
@@ -0,0 +1,194 @@
+// Package datagen generates a synthetic catalog for load and UI testing.
+// The output only depends on the configuration, so a seed reproduces the
+// same catalog.
+package datagen
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+
+	"github.com/shopspring/decimal"
+)
+
+// Price distribution of the generated products in the base currency:
+// log-normal around a median of 350 like a luxury catalog, bounded to
+// [minPrice, maxPrice]
+const (
+	medianPrice = 350
+	priceSigma  = 0.9
+	minPrice    = 5
+	maxPrice    = 25000
+)
+
+// maxCategoryDepth bounds the nesting of generated categories, roots being
+// at depth 0
+const maxCategoryDepth = 2
+
+var (
+	departments = []string{"Clothing", "Shoes", "Bags", "Accessories", "Jewelry", "Beauty", "Home", "Sport"}
+	adjectives  = []string{"Classic", "Modern", "Vintage", "Essential", "Luxury", "Casual", "Evening", "Summer", "Winter", "Urban"}
+	nouns       = []string{"Dresses", "Coats", "Jackets", "Knitwear", "Shirts", "Trousers", "Sneakers", "Boots", "Sandals", "Totes", "Clutches", "Belts", "Scarves", "Watches", "Rings"}
+	sizes       = []string{"XXS", "XS", "S", "M", "L", "XL", "XXL", "36", "37", "38", "39", "40", "41", "42", "43", "44"}
+	colors      = []string{"Black", "White", "Navy", "Beige", "Brown", "Red", "Green", "Grey", "Pink", "Gold"}
+)
+
+// Config sizes the generated catalog
+type Config struct {
+	Categories int
+	Products   int
+
+	// Variants is the average number of variants per product
+	Variants int
+
+	// NullPriceRatio is the share of variants without a price of their
+	// own, which inherit the product price
+	NullPriceRatio float64
+
+	Seed uint64
+}
+
+// Validate checks the configuration describes a catalog
+func (c Config) Validate() error {
+	switch {
+	case c.Categories < 1:
+		return errors.New("at least one category is required")
+	case c.Products < 0:
+		return errors.New("the number of products cannot be negative")
+	case c.Variants < 1:
+		return errors.New("at least one variant per product is required")
+	case c.NullPriceRatio < 0 || c.NullPriceRatio > 1:
+		return errors.New("the NULL price ratio must be between 0 and 1")
+	}
+	return nil
+}
+
+// Category is a generated category. IDs are 1-based positions; the writer
+// offsets them by the IDs it reserves. ParentID is 0 for roots.
+type Category struct {
+	ID       int64
+	ParentID int64
+	Code     string
+	Name     string
+}
+
+// Product is a generated product and its variants
+type Product struct {
+	ID         int64
+	Code       string
+	Price      decimal.Decimal
+	CategoryID int64
+	Variants   []Variant
+}
+
+// Variant is a generated variant. Stock is the on hand quantity.
+type Variant struct {
+	Name  string
+	SKU   string
+	Price decimal.NullDecimal
+	Stock int
+}
+
+// Generator produces the categories and products of a configuration
+type Generator struct {
+	config Config
+}
+
+// New creates a generator for a validated configuration
+func New(config Config) *Generator {
+	return &Generator{config: config}
+}
+
+// Categories generates the category tree. Parents come before their
+// children; the first categories are the roots.
+func (g *Generator) Categories() []Category {
+	rng := g.rng(0)
+	roots := max(1, g.config.Categories/10)
+
+	categories := make([]Category, g.config.Categories)
+	depth := make([]int, g.config.Categories)
+	for i := range categories {
+		c := &categories[i]
+		c.ID = int64(i + 1)
+		c.Code = fmt.Sprintf("GEN-C%05d", i+1)
+
+		if i < roots {
+			c.Name = departments[i%len(departments)]
+			if i >= len(departments) {
+				c.Name = fmt.Sprintf("%s %d", c.Name, i/len(departments)+1)
+			}
+			continue
+		}
+
+		// Parents are drawn among the earlier categories above the depth limit
+		parent := rng.IntN(i)
+		for depth[parent] >= maxCategoryDepth {
+			parent = rng.IntN(i)
+		}
+		c.ParentID = int64(parent + 1)
+		depth[i] = depth[parent] + 1
+		c.Name = fmt.Sprintf("%s %s", adjectives[rng.IntN(len(adjectives))], nouns[rng.IntN(len(nouns))])
+	}
+	return categories
+}
+
+// Product generates the product at 0-based index i. Every product draws
+// from its own random source, so products can be generated in any order.
+func (g *Generator) Product(i int) Product {
+	rng := g.rng(uint64(i) + 1)
+
+	product := Product{
+		ID:         int64(i + 1),
+		Code:       fmt.Sprintf("GEN-P%07d", i+1),
+		Price:      price(rng),
+		CategoryID: int64(rng.IntN(g.config.Categories) + 1),
+	}
+
+	// Between 1 and 2 * Variants - 1 variants, Variants on average
+	count := 1 + rng.IntN(2*g.config.Variants-1)
+	color := colors[rng.IntN(len(colors))]
+	offset := rng.IntN(len(sizes))
+	product.Variants = make([]Variant, count)
+	for j := range product.Variants {
+		v := &product.Variants[j]
+		v.Name = fmt.Sprintf("%s %s", color, sizes[(offset+j)%len(sizes)])
+		v.SKU = fmt.Sprintf("%s-%02d", product.Code, j+1)
+		v.Stock = rng.IntN(25)
+		if rng.Float64() >= g.config.NullPriceRatio {
+			// Variants with a price of their own cost up to 20% more
+			uplift := decimal.NewFromInt(int64(rng.IntN(5) * 5)).Shift(-2)
+			v.Price = decimal.NewNullDecimal(roundPrice(product.Price.Mul(decimal.NewFromInt(1).Add(uplift))))
+		}
+	}
+	return product
+}
+
+// rng returns the random source of a stream of the configured seed
+func (g *Generator) rng(stream uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(g.config.Seed, stream))
+}
+
+// price draws a product price from the catalog distribution
+func price(rng *rand.Rand) decimal.Decimal {
+	p := math.Exp(math.Log(medianPrice) + priceSigma*rng.NormFloat64())
+	p = math.Min(math.Max(p, minPrice), maxPrice)
+	return roundPrice(decimal.NewFromFloat(p))
+}
+
+// roundPrice rounds to the steps shop prices use: 5 below 100, 10 below
+// 1000 and 50 above
+func roundPrice(p decimal.Decimal) decimal.Decimal {
+	step := decimal.NewFromInt(50)
+	switch {
+	case p.LessThan(decimal.NewFromInt(100)):
+		step = decimal.NewFromInt(5)
+	case p.LessThan(decimal.NewFromInt(1000)):
+		step = decimal.NewFromInt(10)
+	}
+	rounded := p.Div(step).Round(0).Mul(step)
+	if rounded.IsZero() {
+		return step
+	}
+	return rounded
+}
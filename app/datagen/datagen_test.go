@@ -0,0 +1,99 @@
+package datagen
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testConfig() Config {
+	return Config{Categories: 40, Products: 2000, Variants: 3, NullPriceRatio: 0.6, Seed: 42}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, testConfig().Validate())
+
+	for name, mutate := range map[string]func(*Config){
+		"no category":    func(c *Config) { c.Categories = 0 },
+		"negative count": func(c *Config) { c.Products = -1 },
+		"no variant":     func(c *Config) { c.Variants = 0 },
+		"ratio above 1":  func(c *Config) { c.NullPriceRatio = 1.5 },
+		"negative ratio": func(c *Config) { c.NullPriceRatio = -0.1 },
+	} {
+		config := testConfig()
+		mutate(&config)
+		assert.Error(t, config.Validate(), name)
+	}
+}
+
+func TestGenerator_Deterministic(t *testing.T) {
+	a, b := New(testConfig()), New(testConfig())
+	assert.Equal(t, a.Categories(), b.Categories())
+	assert.Equal(t, a.Product(1234), b.Product(1234))
+
+	other := testConfig()
+	other.Seed = 43
+	assert.NotEqual(t, a.Product(1234), New(other).Product(1234))
+}
+
+func TestGenerator_Categories(t *testing.T) {
+	categories := New(testConfig()).Categories()
+	assert.Len(t, categories, 40)
+
+	depth := map[int64]int{}
+	for _, c := range categories {
+		if c.ParentID == 0 {
+			depth[c.ID] = 0
+			continue
+		}
+		parentDepth, ok := depth[c.ParentID]
+		assert.True(t, ok, "parent of %s comes first", c.Code)
+		depth[c.ID] = parentDepth + 1
+		assert.LessOrEqual(t, depth[c.ID], maxCategoryDepth)
+	}
+	assert.Equal(t, int64(0), categories[0].ParentID)
+}
+
+func TestGenerator_Products(t *testing.T) {
+	config := testConfig()
+	g := New(config)
+
+	variants, nullPrices := 0, 0
+	skus := map[string]bool{}
+	for i := 0; i < config.Products; i++ {
+		p := g.Product(i)
+		assert.True(t, p.Price.GreaterThanOrEqual(decimal.NewFromInt(minPrice)), p.Code)
+		assert.True(t, p.Price.LessThanOrEqual(decimal.NewFromInt(maxPrice)), p.Code)
+		assert.True(t, p.CategoryID >= 1 && p.CategoryID <= int64(config.Categories))
+		assert.NotEmpty(t, p.Variants)
+
+		for _, v := range p.Variants {
+			assert.False(t, skus[v.SKU], "SKU %s is unique", v.SKU)
+			skus[v.SKU] = true
+			if !v.Price.Valid {
+				nullPrices++
+			} else {
+				assert.True(t, v.Price.Decimal.GreaterThanOrEqual(p.Price))
+			}
+		}
+		variants += len(p.Variants)
+	}
+
+	average := float64(variants) / float64(config.Products)
+	assert.InDelta(t, config.Variants, average, 0.2)
+	assert.InDelta(t, config.NullPriceRatio, float64(nullPrices)/float64(variants), 0.05)
+}
+
+func TestRoundPrice(t *testing.T) {
+	cases := map[string]string{
+		"12.30":   "10",
+		"97.60":   "100",
+		"344.99":  "340",
+		"1234.00": "1250",
+		"1.00":    "5",
+	}
+	for input, expected := range cases {
+		assert.Equal(t, expected, roundPrice(decimal.RequireFromString(input)).String(), input)
+	}
+}
@@ -0,0 +1,438 @@
+// Package migrate applies versioned SQL migrations to the database and
+// records the applied ones in the schema_migrations table.
+package migrate
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidMigration  = errors.New("invalid migration")
+	ErrChecksumMismatch  = errors.New("applied migration was modified")
+	ErrUnknownMigration  = errors.New("applied migration is unknown")
+	ErrIrreversible      = errors.New("migration has no down script")
+	ErrNothingToRollBack = errors.New("no applied migration")
+)
+
+// filePattern matches migration files such as 001-products.up.sql
+var filePattern = regexp.MustCompile(`^(\d+)-([a-z0-9-]+)\.(up|down)\.sql$`)
+
+// tableName is the table recording the applied migrations. It also keys the
+// advisory lock that serializes migrating processes, e.g. replicas starting
+// together.
+const tableName = "schema_migrations"
+
+const createTableSQL = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name VARCHAR(256) NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`
+
+// Migration is a schema change read from a NNN-name.up.sql file and its
+// optional NNN-name.down.sql counterpart. Down is empty when the migration
+// cannot be reverted. Checksum is the SHA-256 of the up script and detects
+// migrations edited after they were applied.
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// String identifies the migration by its file name prefix
+func (m Migration) String() string {
+	return fmt.Sprintf("%03d-%s", m.Version, m.Name)
+}
+
+// Load reads the migrations in the root directory of fsys, ordered by
+// version. Files other than .sql files are ignored.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		match := filePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: %s is not named NNN-name.up.sql or NNN-name.down.sql", ErrInvalidMigration, entry.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("%w: %s has an invalid version", ErrInvalidMigration, entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("%w: version %d is used by %s and %s", ErrInvalidMigration, version, m.Name, match[2])
+		}
+
+		script := string(content)
+		if strings.TrimSpace(script) == "" {
+			return nil, fmt.Errorf("%w: %s is empty", ErrInvalidMigration, entry.Name())
+		}
+		if match[3] == "up" {
+			m.Up = script
+			sum := sha256.Sum256(content)
+			m.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			m.Down = script
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("%w: %s has a down script but no up script", ErrInvalidMigration, m)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// record is a row of schema_migrations
+type record struct {
+	Version   int64 `gorm:"primaryKey"`
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+func (r *record) TableName() string {
+	return tableName
+}
+
+// State of a migration against the database
+const (
+	StatePending  = "pending"
+	StateApplied  = "applied"
+	StateModified = "modified"
+	StateUnknown  = "unknown"
+)
+
+// Status reports a migration and whether it is applied. Modified
+// migrations were edited after they were applied; unknown ones are applied
+// but have no file, e.g. when the database was migrated by a newer release.
+type Status struct {
+	Version   int64
+	Name      string
+	State     string
+	AppliedAt *time.Time
+}
+
+// Migrator applies a set of migrations to a database
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New creates a migrator for migrations, ordered by version as returned
+// by Load
+func New(db *gorm.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Status reports every known and every applied migration ordered by version
+func (m *Migrator) Status() ([]Status, error) {
+	records, err := m.records(m.db)
+	if err != nil {
+		return nil, err
+	}
+	return status(m.migrations, records), nil
+}
+
+// Pending returns the migrations not applied yet. It fails with
+// ErrUnknownMigration when the database schema is ahead of the migrations,
+// i.e. it was migrated by a newer release, and with ErrChecksumMismatch when
+// an applied migration was modified.
+func (m *Migrator) Pending() ([]Migration, error) {
+	records, err := m.records(m.db)
+	if err != nil {
+		return nil, err
+	}
+	return m.pending(records)
+}
+
+// Up applies the pending migrations in version order, at most limit of
+// them when limit is positive. Each migration runs in its own transaction
+// together with its schema_migrations row. It refuses to run while an
+// applied migration is modified or unknown.
+func (m *Migrator) Up(limit int) ([]Migration, error) {
+	var applied []Migration
+	err := m.locked(func(conn *gorm.DB) error {
+		records, err := m.records(conn)
+		if err != nil {
+			return err
+		}
+		pending, err := m.pending(records)
+		if err != nil {
+			return err
+		}
+		if limit > 0 && len(pending) > limit {
+			pending = pending[:limit]
+		}
+
+		for _, migration := range pending {
+			if err := apply(conn, migration); err != nil {
+				return err
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the last applied migrations in reverse version order, one
+// unless limit is greater
+func (m *Migrator) Down(limit int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.locked(func(conn *gorm.DB) error {
+		var err error
+		reverted, err = m.down(conn, limit)
+		return err
+	})
+	return reverted, err
+}
+
+// Redo reverts the last applied migration and applies it again
+func (m *Migrator) Redo() (*Migration, error) {
+	var migration *Migration
+	err := m.locked(func(conn *gorm.DB) error {
+		reverted, err := m.down(conn, 1)
+		if err != nil {
+			return err
+		}
+		if err := apply(conn, reverted[0]); err != nil {
+			return err
+		}
+		migration = &reverted[0]
+		return nil
+	})
+	return migration, err
+}
+
+func (m *Migrator) down(conn *gorm.DB, limit int) ([]Migration, error) {
+	records, err := m.records(conn)
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(status(m.migrations, records)); err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrNothingToRollBack
+	}
+
+	if limit < 1 {
+		limit = 1
+	}
+	reverted := make([]Migration, 0, limit)
+	for i := len(records) - 1; i >= 0 && len(reverted) < limit; i-- {
+		migration := m.migrations[m.index(records[i].Version)]
+		if err := revert(conn, migration); err != nil {
+			return reverted, err
+		}
+		reverted = append(reverted, migration)
+	}
+	return reverted, nil
+}
+
+// locked runs fn on a single connection holding the migration advisory
+// lock. Other processes wait for the lock, then see the migrations applied
+// meanwhile.
+func (m *Migrator) locked(fn func(conn *gorm.DB) error) error {
+	return m.db.Connection(func(conn *gorm.DB) (err error) {
+		if err := conn.Exec("SELECT pg_advisory_lock(hashtext(?))", tableName).Error; err != nil {
+			return err
+		}
+		defer func() {
+			if unlockErr := unlock(conn); unlockErr != nil {
+				err = errors.Join(err, unlockErr)
+			}
+		}()
+
+		if err := conn.Exec(createTableSQL).Error; err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+// unlock releases the migration advisory lock of conn. The lock belongs to
+// the session, so when it cannot be released the connection is discarded
+// instead of going back to the pool, where it would keep blocking every
+// other migrating process.
+func unlock(conn *gorm.DB) error {
+	var unlocked bool
+	err := conn.Raw("SELECT pg_advisory_unlock(hashtext(?))", tableName).Scan(&unlocked).Error
+	if err == nil && unlocked {
+		return nil
+	}
+	if err == nil {
+		err = errors.New("the lock was not held")
+	}
+
+	if sqlConn, ok := conn.Statement.ConnPool.(*sql.Conn); ok {
+		// Returning ErrBadConn makes database/sql close the connection
+		sqlConn.Raw(func(any) error { return driver.ErrBadConn })
+	}
+	return fmt.Errorf("releasing the migration lock: %w", err)
+}
+
+// records returns the rows of schema_migrations ordered by version, none
+// when the table is not created yet
+func (m *Migrator) records(conn *gorm.DB) ([]record, error) {
+	var exists bool
+	if err := conn.Raw("SELECT to_regclass(?) IS NOT NULL", tableName).Scan(&exists).Error; err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	var records []record
+	if err := conn.Order("version").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// pending returns the migrations without record once the records are
+// verified
+func (m *Migrator) pending(records []record) ([]Migration, error) {
+	statuses := status(m.migrations, records)
+	if err := verify(statuses); err != nil {
+		return nil, err
+	}
+
+	pending := make([]Migration, 0)
+	for _, s := range statuses {
+		if s.State == StatePending {
+			pending = append(pending, m.migrations[m.index(s.Version)])
+		}
+	}
+	return pending, nil
+}
+
+func apply(conn *gorm.DB, migration Migration) error {
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(migration.Up).Error; err != nil {
+			return err
+		}
+		return tx.Create(&record{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Checksum:  migration.Checksum,
+			AppliedAt: time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("applying %s: %w", migration, err)
+	}
+	return nil
+}
+
+func revert(conn *gorm.DB, migration Migration) error {
+	if migration.Down == "" {
+		return fmt.Errorf("%w: %s", ErrIrreversible, migration)
+	}
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(migration.Down).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&record{Version: migration.Version}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("reverting %s: %w", migration, err)
+	}
+	return nil
+}
+
+// index returns the position of the migration with version, which must be
+// known
+func (m *Migrator) index(version int64) int {
+	return sort.Search(len(m.migrations), func(i int) bool {
+		return m.migrations[i].Version >= version
+	})
+}
+
+// status merges the migrations with the applied records, both ordered by
+// version
+func status(migrations []Migration, records []record) []Status {
+	applied := make(map[int64]record, len(records))
+	for _, r := range records {
+		applied[r.Version] = r
+	}
+
+	statuses := make([]Status, 0, len(migrations)+len(records))
+	known := make(map[int64]bool, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = true
+		s := Status{Version: migration.Version, Name: migration.Name, State: StatePending}
+		if r, ok := applied[migration.Version]; ok {
+			s.State = StateApplied
+			if r.Checksum != migration.Checksum {
+				s.State = StateModified
+			}
+			appliedAt := r.AppliedAt
+			s.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, s)
+	}
+	for _, r := range records {
+		if !known[r.Version] {
+			appliedAt := r.AppliedAt
+			statuses = append(statuses, Status{Version: r.Version, Name: r.Name, State: StateUnknown, AppliedAt: &appliedAt})
+		}
+	}
+
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses
+}
+
+// verify fails on the first applied migration that is modified or unknown
+func verify(statuses []Status) error {
+	for _, s := range statuses {
+		switch s.State {
+		case StateModified:
+			return fmt.Errorf("%w: %03d-%s", ErrChecksumMismatch, s.Version, s.Name)
+		case StateUnknown:
+			return fmt.Errorf("%w: %03d-%s", ErrUnknownMigration, s.Version, s.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	sqlmigrations "github.com/mytheresa/go-hiring-challenge/sql/migrations"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("pairs up and down scripts ordered by version", func(t *testing.T) {
+		migrations, err := Load(fstest.MapFS{
+			"010-stock.up.sql":      {Data: []byte("CREATE TABLE stock ();")},
+			"002-variants.up.sql":   {Data: []byte("CREATE TABLE variants ();")},
+			"001-products.up.sql":   {Data: []byte("CREATE TABLE products ();")},
+			"001-products.down.sql": {Data: []byte("DROP TABLE products;")},
+			"README.md":             {Data: []byte("not a migration")},
+		})
+
+		assert.NoError(t, err)
+		if assert.Len(t, migrations, 3) {
+			assert.Equal(t, int64(1), migrations[0].Version)
+			assert.Equal(t, "products", migrations[0].Name)
+			assert.Equal(t, "DROP TABLE products;", migrations[0].Down)
+			assert.Len(t, migrations[0].Checksum, 64)
+			assert.Equal(t, "002-variants", migrations[1].String())
+			assert.Empty(t, migrations[1].Down)
+			assert.Equal(t, int64(10), migrations[2].Version)
+		}
+	})
+
+	t.Run("checksum depends on the up script only", func(t *testing.T) {
+		a, _ := Load(fstest.MapFS{"001-a.up.sql": {Data: []byte("SELECT 1;")}})
+		b, _ := Load(fstest.MapFS{
+			"001-a.up.sql":   {Data: []byte("SELECT 1;")},
+			"001-a.down.sql": {Data: []byte("SELECT 2;")},
+		})
+		c, _ := Load(fstest.MapFS{"001-a.up.sql": {Data: []byte("SELECT 1; ")}})
+
+		assert.Equal(t, a[0].Checksum, b[0].Checksum)
+		assert.NotEqual(t, a[0].Checksum, c[0].Checksum)
+	})
+
+	invalid := map[string]fstest.MapFS{
+		"badly named file":  {"products.sql": {Data: []byte("SELECT 1;")}},
+		"version zero":      {"000-zero.up.sql": {Data: []byte("SELECT 1;")}},
+		"duplicate version": {"001-a.up.sql": {Data: []byte("SELECT 1;")}, "001-b.up.sql": {Data: []byte("SELECT 1;")}},
+		"down without up":   {"001-a.down.sql": {Data: []byte("SELECT 1;")}},
+		"empty script":      {"001-a.up.sql": {Data: []byte(" \n")}},
+	}
+	for name, fsys := range invalid {
+		t.Run("rejects "+name, func(t *testing.T) {
+			_, err := Load(fsys)
+			assert.ErrorIs(t, err, ErrInvalidMigration)
+		})
+	}
+
+	t.Run("loads the embedded migrations", func(t *testing.T) {
+		migrations, err := Load(sqlmigrations.FS)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, migrations)
+		for i, m := range migrations {
+			assert.Equal(t, int64(i+1), m.Version, "migrations are numbered without gaps")
+			assert.NotEmpty(t, m.Down, "%s has a down script", m)
+		}
+	})
+}
+
+func TestStatus(t *testing.T) {
+	migrations, _ := Load(fstest.MapFS{
+		"001-a.up.sql": {Data: []byte("SELECT 1;")},
+		"002-b.up.sql": {Data: []byte("SELECT 2;")},
+		"003-c.up.sql": {Data: []byte("SELECT 3;")},
+	})
+	appliedAt := time.Now()
+
+	t.Run("reports applied and pending migrations", func(t *testing.T) {
+		statuses := status(migrations, []record{
+			{Version: 1, Name: "a", Checksum: migrations[0].Checksum, AppliedAt: appliedAt},
+		})
+
+		if assert.Len(t, statuses, 3) {
+			assert.Equal(t, StateApplied, statuses[0].State)
+			assert.Equal(t, appliedAt, *statuses[0].AppliedAt)
+			assert.Equal(t, StatePending, statuses[1].State)
+			assert.Nil(t, statuses[1].AppliedAt)
+			assert.Equal(t, StatePending, statuses[2].State)
+		}
+		assert.NoError(t, verify(statuses))
+	})
+
+	t.Run("detects modified migrations", func(t *testing.T) {
+		statuses := status(migrations, []record{
+			{Version: 1, Name: "a", Checksum: "edited", AppliedAt: appliedAt},
+		})
+
+		assert.Equal(t, StateModified, statuses[0].State)
+		assert.ErrorIs(t, verify(statuses), ErrChecksumMismatch)
+	})
+
+	t.Run("detects applied migrations without file", func(t *testing.T) {
+		statuses := status(migrations, []record{
+			{Version: 1, Name: "a", Checksum: migrations[0].Checksum, AppliedAt: appliedAt},
+			{Version: 4, Name: "d", Checksum: "newer", AppliedAt: appliedAt},
+		})
+
+		if assert.Len(t, statuses, 4) {
+			assert.Equal(t, StateUnknown, statuses[3].State)
+			assert.Equal(t, "d", statuses[3].Name)
+		}
+		assert.ErrorIs(t, verify(statuses), ErrUnknownMigration)
+	})
+}
@@ -0,0 +1,280 @@
+package promotions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+// Column sizes of the promotions table
+const (
+	maxCodeLength = 32
+	maxNameLength = 256
+)
+
+// PromotionRequest is the body accepted by POST /promotions and
+// PUT /promotions/{code}. Exactly one of product and category must be set;
+// a category promotion also applies to its subcategories. Value is a
+// percentage for "percentage" promotions and a base currency amount for
+// "fixed" ones. Missing validity bounds leave the window open.
+type PromotionRequest struct {
+	Code      string          `json:"code"`
+	Name      string          `json:"name"`
+	Type      string          `json:"type"`
+	Value     decimal.Decimal `json:"value"`
+	Product   string          `json:"product,omitempty"`
+	Category  string          `json:"category,omitempty"`
+	Priority  int             `json:"priority"`
+	Stackable bool            `json:"stackable"`
+	ValidFrom *time.Time      `json:"valid_from,omitempty"`
+	ValidTo   *time.Time      `json:"valid_to,omitempty"`
+}
+
+// PromotionResponse represents a promotion and whether it is active now
+type PromotionResponse struct {
+	Code      string          `json:"code"`
+	Name      string          `json:"name"`
+	Type      string          `json:"type"`
+	Value     decimal.Decimal `json:"value"`
+	Product   string          `json:"product,omitempty"`
+	Category  string          `json:"category,omitempty"`
+	Priority  int             `json:"priority"`
+	Stackable bool            `json:"stackable"`
+	ValidFrom *time.Time      `json:"valid_from,omitempty"`
+	ValidTo   *time.Time      `json:"valid_to,omitempty"`
+	Active    bool            `json:"active"`
+}
+
+type PromotionsHandler struct {
+	repo models.PromotionRepository
+}
+
+func NewPromotionsHandler(repo models.PromotionRepository) *PromotionsHandler {
+	return &PromotionsHandler{repo: repo}
+}
+
+// HandleList handles GET /promotions - lists all promotions by descending priority
+func (h *PromotionsHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	slog.Info("Fetching all promotions")
+
+	promotions, err := h.repo.GetAllPromotions()
+	if err != nil {
+		slog.Error("Failed to fetch promotions", "error", err)
+		api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	now := time.Now()
+	response := make([]PromotionResponse, len(promotions))
+	for i := range promotions {
+		response[i] = mapPromotionResponse(&promotions[i], now)
+	}
+
+	api.OKResponse(w, response)
+}
+
+// HandleCreate handles POST /promotions
+func (h *PromotionsHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	var req PromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Warn("Invalid request body", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := validatePromotionRequest(req); err != nil {
+		slog.Warn("Invalid promotion request", "code", req.Code, "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slog.Info("Creating promotion", "code", req.Code, "type", req.Type, "product", req.Product, "category", req.Category)
+
+	promotion := req.toModel()
+	if err := h.repo.CreatePromotion(promotion); err != nil {
+		writePromotionError(w, req.Code, err)
+		return
+	}
+
+	slog.Info("Successfully created promotion", "code", promotion.Code)
+
+	api.CreatedResponse(w, mapPromotionResponse(promotion, time.Now()))
+}
+
+// HandleGet handles GET /promotions/{code}
+func (h *PromotionsHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+
+	slog.Info("Fetching promotion", "code", code)
+
+	promotion, err := h.repo.GetPromotionByCode(code)
+	if err != nil {
+		writePromotionError(w, code, err)
+		return
+	}
+
+	api.OKResponse(w, mapPromotionResponse(promotion, time.Now()))
+}
+
+// HandleReplace handles PUT /promotions/{code} - replaces the promotion
+func (h *PromotionsHandler) HandleReplace(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+
+	var req PromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Warn("Invalid request body", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// The path identifies the promotion; the body code is only needed to rename it
+	if req.Code == "" {
+		req.Code = code
+	}
+
+	if err := validatePromotionRequest(req); err != nil {
+		slog.Warn("Invalid promotion request", "code", code, "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slog.Info("Updating promotion", "code", code, "newCode", req.Code)
+
+	promotion := req.toModel()
+	if err := h.repo.UpdatePromotion(code, promotion); err != nil {
+		writePromotionError(w, code, err)
+		return
+	}
+
+	slog.Info("Successfully updated promotion", "code", promotion.Code)
+
+	api.OKResponse(w, mapPromotionResponse(promotion, time.Now()))
+}
+
+// HandleDelete handles DELETE /promotions/{code}
+func (h *PromotionsHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+
+	slog.Info("Deleting promotion", "code", code)
+
+	if err := h.repo.DeletePromotion(code); err != nil {
+		writePromotionError(w, code, err)
+		return
+	}
+
+	slog.Info("Successfully deleted promotion", "code", code)
+
+	api.NoContentResponse(w)
+}
+
+// validatePromotionRequest checks the fields of a promotion write request.
+// The returned error message is safe to send to the client.
+func validatePromotionRequest(req PromotionRequest) error {
+	if strings.TrimSpace(req.Code) == "" || strings.TrimSpace(req.Name) == "" {
+		return errors.New("Code and name are required")
+	}
+	if len(req.Code) > maxCodeLength {
+		return fmt.Errorf("Code must be at most %d characters", maxCodeLength)
+	}
+	if len(req.Name) > maxNameLength {
+		return fmt.Errorf("Name must be at most %d characters", maxNameLength)
+	}
+
+	switch req.Type {
+	case models.DiscountPercentage:
+		if !req.Value.IsPositive() || req.Value.GreaterThan(decimal.NewFromInt(100)) {
+			return errors.New("Invalid value: percentage must be greater than 0 and at most 100")
+		}
+	case models.DiscountFixed:
+		if !req.Value.IsPositive() {
+			return errors.New("Invalid value: fixed discount must be greater than 0")
+		}
+	default:
+		return fmt.Errorf("Invalid type: must be %s or %s", models.DiscountPercentage, models.DiscountFixed)
+	}
+	if req.Value.Exponent() < -2 {
+		return errors.New("Invalid value: at most 2 decimal places")
+	}
+
+	if (req.Product == "") == (req.Category == "") {
+		return errors.New("Exactly one of product and category is required")
+	}
+	if req.ValidFrom != nil && req.ValidTo != nil && !req.ValidTo.After(*req.ValidFrom) {
+		return errors.New("Invalid validity window: valid_to must be after valid_from")
+	}
+	return nil
+}
+
+// toModel converts the request to a promotion referencing its target by code
+func (req PromotionRequest) toModel() *models.Promotion {
+	promotion := &models.Promotion{
+		Code:         req.Code,
+		Name:         req.Name,
+		DiscountType: req.Type,
+		Value:        req.Value,
+		Priority:     req.Priority,
+		Stackable:    req.Stackable,
+		ValidFrom:    req.ValidFrom,
+		ValidTo:      req.ValidTo,
+	}
+	if req.Product != "" {
+		promotion.Product = &models.Product{Code: req.Product}
+	} else {
+		promotion.Category = &models.Category{Code: req.Category}
+	}
+	return promotion
+}
+
+// writePromotionError maps repository errors to HTTP responses
+func writePromotionError(w http.ResponseWriter, code string, err error) {
+	switch {
+	case errors.Is(err, models.ErrPromotionNotFound):
+		slog.Warn("Promotion not found", "code", code)
+		api.ErrorResponse(w, http.StatusNotFound, "Promotion not found")
+	case errors.Is(err, models.ErrPromotionCodeExists):
+		slog.Warn("Duplicate promotion code", "code", code)
+		api.ErrorResponse(w, http.StatusConflict, "Promotion code already exists")
+	case errors.Is(err, models.ErrProductNotFound):
+		slog.Warn("Promotion product not found", "code", code)
+		api.ErrorResponse(w, http.StatusBadRequest, "Product not found")
+	case errors.Is(err, models.ErrCategoryNotFound):
+		slog.Warn("Promotion category not found", "code", code)
+		api.ErrorResponse(w, http.StatusBadRequest, "Category not found")
+	case errors.Is(err, models.ErrInvalidPromotion):
+		slog.Warn("Invalid promotion", "code", code, "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+	default:
+		slog.Error("Failed to process promotion", "code", code, "error", err)
+		api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+// mapPromotionResponse maps a promotion model to its API response
+func mapPromotionResponse(promotion *models.Promotion, now time.Time) PromotionResponse {
+	response := PromotionResponse{
+		Code:      promotion.Code,
+		Name:      promotion.Name,
+		Type:      promotion.DiscountType,
+		Value:     promotion.Value,
+		Priority:  promotion.Priority,
+		Stackable: promotion.Stackable,
+		ValidFrom: promotion.ValidFrom,
+		ValidTo:   promotion.ValidTo,
+		Active:    promotion.ActiveAt(now),
+	}
+	if promotion.Product != nil {
+		response.Product = promotion.Product.Code
+	}
+	if promotion.Category != nil {
+		response.Category = promotion.Category.Code
+	}
+	return response
+}
@@ -0,0 +1,127 @@
+package promotions
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/testutil"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupTestServer() (*http.ServeMux, *gorm.DB) {
+	db := testutil.SetupTestDB()
+
+	handler := NewPromotionsHandler(models.NewPromotionsRepository(db))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /promotions", handler.HandleList)
+	mux.HandleFunc("POST /promotions", handler.HandleCreate)
+	mux.HandleFunc("GET /promotions/{code}", handler.HandleGet)
+	mux.HandleFunc("PUT /promotions/{code}", handler.HandleReplace)
+	mux.HandleFunc("DELETE /promotions/{code}", handler.HandleDelete)
+
+	return mux, db
+}
+
+func TestValidatePromotionRequest(t *testing.T) {
+	valid := PromotionRequest{Code: "ACC20", Name: "20% off", Type: "percentage", Value: decimal.NewFromInt(20), Category: "ACCESSORIES"}
+	assert.NoError(t, validatePromotionRequest(valid))
+
+	cases := map[string]func(*PromotionRequest){
+		"missing name":        func(r *PromotionRequest) { r.Name = " " },
+		"unknown type":        func(r *PromotionRequest) { r.Type = "bogo" },
+		"percentage over 100": func(r *PromotionRequest) { r.Value = decimal.NewFromInt(101) },
+		"zero value":          func(r *PromotionRequest) { r.Value = decimal.Zero },
+		"too many decimals":   func(r *PromotionRequest) { r.Value = decimal.RequireFromString("1.005") },
+		"two targets":         func(r *PromotionRequest) { r.Product = "PROD001" },
+		"no target":           func(r *PromotionRequest) { r.Category = "" },
+	}
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := valid
+			mutate(&req)
+			assert.Error(t, validatePromotionRequest(req))
+		})
+	}
+}
+
+func TestPromotionEndpoints(t *testing.T) {
+	mux, db := setupTestServer()
+	testutil.CleanupByCode(t, db, &models.Promotion{}, "TEST_PROMO")
+	testutil.CleanupByCode(t, db, &models.Promotion{}, "TEST_PROMO_RENAMED")
+
+	t.Run("POST /promotions creates a promotion", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPost, "/promotions", map[string]any{
+			"code":     "TEST_PROMO",
+			"name":     "20% off shoes",
+			"type":     "percentage",
+			"value":    "20",
+			"category": "SHOES",
+			"priority": 10,
+		})
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response PromotionResponse
+		err := json.NewDecoder(w.Body).Decode(&response)
+		assert.NoError(t, err)
+		assert.Equal(t, "SHOES", response.Category)
+		assert.True(t, response.Active)
+	})
+
+	t.Run("promotions are attached to the products of the category", func(t *testing.T) {
+		product, err := models.NewProductsRepository(db).GetProductByCode("PROD002")
+		assert.NoError(t, err)
+		assert.Contains(t, codes(product.Promotions), "TEST_PROMO")
+	})
+
+	t.Run("POST /promotions returns 409 for duplicate codes and 400 for unknown targets", func(t *testing.T) {
+		body := map[string]any{"code": "TEST_PROMO", "name": "Dup", "type": "fixed", "value": "5", "product": "PROD001"}
+		w := testutil.DoJSON(mux, http.MethodPost, "/promotions", body)
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		body["code"], body["product"] = "TEST_PROMO_RENAMED", "NONEXISTENT"
+		w = testutil.DoJSON(mux, http.MethodPost, "/promotions", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("PUT /promotions/{code} replaces the promotion", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPut, "/promotions/TEST_PROMO", map[string]any{
+			"code":      "TEST_PROMO_RENAMED",
+			"name":      "5 EUR off PROD001",
+			"type":      "fixed",
+			"value":     "5",
+			"product":   "PROD001",
+			"stackable": true,
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = testutil.DoJSON(mux, http.MethodGet, "/promotions/TEST_PROMO_RENAMED", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response PromotionResponse
+		json.NewDecoder(w.Body).Decode(&response)
+		assert.Equal(t, "PROD001", response.Product)
+		assert.Empty(t, response.Category)
+		assert.True(t, response.Stackable)
+	})
+
+	t.Run("DELETE /promotions/{code} removes the promotion", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodDelete, "/promotions/TEST_PROMO_RENAMED", nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		w = testutil.DoJSON(mux, http.MethodGet, "/promotions/TEST_PROMO_RENAMED", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func codes(promotions []models.Promotion) []string {
+	result := make([]string, len(promotions))
+	for i, p := range promotions {
+		result[i] = p.Code
+	}
+	return result
+}
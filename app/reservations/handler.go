@@ -0,0 +1,233 @@
+package reservations
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// DefaultTTL is how long a reservation holds stock when not configured
+const DefaultTTL = 15 * time.Minute
+
+// maxReservationItems bounds the items of a single reservation request
+const maxReservationItems = 100
+
+// CreateReservationRequest is the body accepted by POST /reservations
+type CreateReservationRequest struct {
+	Items []ReservationItemRequest `json:"items"`
+}
+
+// ReservationItemRequest asks for a quantity of a SKU
+type ReservationItemRequest struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+// ReservationResponse represents a reservation with the warehouses its units are held in
+type ReservationResponse struct {
+	ID        uint                      `json:"id"`
+	Status    string                    `json:"status"`
+	ExpiresAt time.Time                 `json:"expires_at"`
+	Items     []ReservationItemResponse `json:"items"`
+}
+
+// ReservationItemResponse is a quantity of a SKU held in one warehouse
+type ReservationItemResponse struct {
+	SKU       string `json:"sku"`
+	Warehouse string `json:"warehouse"`
+	Quantity  int    `json:"quantity"`
+}
+
+type ReservationsHandler struct {
+	repo models.ReservationRepository
+	ttl  time.Duration
+}
+
+func NewReservationsHandler(repo models.ReservationRepository, ttl time.Duration) *ReservationsHandler {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &ReservationsHandler{repo: repo, ttl: ttl}
+}
+
+// HandleCreate handles POST /reservations - holds stock for a checkout
+func (h *ReservationsHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	var req CreateReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Warn("Invalid request body", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := validateCreateRequest(req); err != nil {
+		slog.Warn("Invalid reservation request", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	requests := make([]models.ReservationRequest, len(req.Items))
+	for i, item := range req.Items {
+		requests[i] = models.ReservationRequest{SKU: item.SKU, Quantity: item.Quantity}
+	}
+
+	slog.Info("Creating reservation", "items", len(requests), "ttl", h.ttl)
+
+	reservation, err := h.repo.CreateReservation(requests, time.Now().UTC().Add(h.ttl))
+	if err != nil {
+		writeReservationError(w, 0, err)
+		return
+	}
+
+	slog.Info("Successfully created reservation", "id", reservation.ID, "expiresAt", reservation.ExpiresAt)
+
+	api.CreatedResponse(w, mapReservationResponse(reservation))
+}
+
+// HandleGet handles GET /reservations/{id}
+func (h *ReservationsHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseReservationID(w, r)
+	if !ok {
+		return
+	}
+
+	reservation, err := h.repo.GetReservation(id)
+	if err != nil {
+		writeReservationError(w, id, err)
+		return
+	}
+
+	api.OKResponse(w, mapReservationResponse(reservation))
+}
+
+// HandleConfirm handles POST /reservations/{id}/confirm - the held units are sold
+func (h *ReservationsHandler) HandleConfirm(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseReservationID(w, r)
+	if !ok {
+		return
+	}
+
+	slog.Info("Confirming reservation", "id", id)
+
+	reservation, err := h.repo.ConfirmReservation(id, time.Now().UTC())
+	if err != nil {
+		writeReservationError(w, id, err)
+		return
+	}
+
+	slog.Info("Successfully confirmed reservation", "id", id)
+
+	api.OKResponse(w, mapReservationResponse(reservation))
+}
+
+// HandleRelease handles POST /reservations/{id}/release - the held units become available again
+func (h *ReservationsHandler) HandleRelease(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseReservationID(w, r)
+	if !ok {
+		return
+	}
+
+	slog.Info("Releasing reservation", "id", id)
+
+	reservation, err := h.repo.ReleaseReservation(id)
+	if err != nil {
+		writeReservationError(w, id, err)
+		return
+	}
+
+	slog.Info("Successfully released reservation", "id", id)
+
+	api.OKResponse(w, mapReservationResponse(reservation))
+}
+
+// validateCreateRequest checks the items of a reservation request
+func validateCreateRequest(req CreateReservationRequest) error {
+	if len(req.Items) == 0 {
+		return errors.New("At least one item is required")
+	}
+	if len(req.Items) > maxReservationItems {
+		return errors.New("Too many items: maximum 100 per reservation")
+	}
+	for _, item := range req.Items {
+		if strings.TrimSpace(item.SKU) == "" {
+			return errors.New("Item SKU cannot be empty or whitespace only")
+		}
+		if item.Quantity <= 0 {
+			return errors.New("Item quantity must be a positive integer")
+		}
+	}
+	return nil
+}
+
+// parseReservationID reads the {id} path value, writing a 404 response when it is not an id
+func parseReservationID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil || id == 0 {
+		slog.Warn("Invalid reservation id", "id", r.PathValue("id"))
+		api.ErrorResponse(w, http.StatusNotFound, "Reservation not found")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// writeReservationError maps repository errors of reservations to HTTP responses
+func writeReservationError(w http.ResponseWriter, id uint, err error) {
+	switch {
+	case errors.Is(err, models.ErrReservationNotFound):
+		slog.Warn("Reservation not found", "id", id)
+		api.ErrorResponse(w, http.StatusNotFound, "Reservation not found")
+	case errors.Is(err, models.ErrReservationNotPending):
+		slog.Warn("Reservation is not pending", "id", id)
+		api.ErrorResponse(w, http.StatusConflict, "Reservation is no longer pending")
+	case errors.Is(err, models.ErrReservationExpired):
+		slog.Warn("Reservation has expired", "id", id)
+		api.ErrorResponse(w, http.StatusConflict, "Reservation has expired")
+	case errors.Is(err, models.ErrReservedStockMissing):
+		slog.Error("Reserved stock no longer exists", "id", id)
+		api.ErrorResponse(w, http.StatusConflict, "Reserved stock no longer exists")
+	case errors.Is(err, models.ErrInsufficientStock):
+		var shortage *models.StockShortageError
+		if errors.As(err, &shortage) {
+			slog.Warn("Insufficient stock for reservation", "sku", shortage.SKU)
+			api.ErrorResponse(w, http.StatusConflict, "Insufficient stock for SKU "+shortage.SKU)
+			return
+		}
+		slog.Warn("Insufficient stock for reservation")
+		api.ErrorResponse(w, http.StatusConflict, "Insufficient stock")
+	case errors.Is(err, models.ErrVariantNotFound):
+		slog.Warn("Reservation references unknown SKU")
+		api.ErrorResponse(w, http.StatusBadRequest, "Variant not found")
+	case errors.Is(err, models.ErrInvalidReservation):
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid reservation")
+	default:
+		slog.Error("Failed to process reservation", "id", id, "error", err)
+		api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+// mapReservationResponse maps a reservation model to its API response
+func mapReservationResponse(reservation *models.Reservation) ReservationResponse {
+	items := make([]ReservationItemResponse, len(reservation.Items))
+	for i, item := range reservation.Items {
+		items[i] = ReservationItemResponse{
+			SKU:      item.SKU,
+			Quantity: item.Quantity,
+		}
+		if item.Warehouse != nil {
+			items[i].Warehouse = item.Warehouse.Code
+		}
+	}
+
+	return ReservationResponse{
+		ID:        reservation.ID,
+		Status:    reservation.Status,
+		ExpiresAt: reservation.ExpiresAt,
+		Items:     items,
+	}
+}
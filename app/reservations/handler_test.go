@@ -0,0 +1,96 @@
+package reservations
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/testutil"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupTestServer(t *testing.T) (*http.ServeMux, *gorm.DB) {
+	db := testutil.SetupTestDB()
+
+	handler := NewReservationsHandler(models.NewReservationsRepository(db), time.Minute)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /reservations", handler.HandleCreate)
+	mux.HandleFunc("GET /reservations/{id}", handler.HandleGet)
+	mux.HandleFunc("POST /reservations/{id}/confirm", handler.HandleConfirm)
+	mux.HandleFunc("POST /reservations/{id}/release", handler.HandleRelease)
+
+	return mux, db
+}
+
+// createStockedVariant creates a product whose single variant has onHand units
+func createStockedVariant(t *testing.T, db *gorm.DB, code, sku string, onHand int) {
+	testutil.CreateProduct(t, db, code, sku)
+	if _, err := models.NewStockLevelsRepository(db).SetStock(sku, "", onHand); err != nil {
+		t.Fatalf("setting stock: %v", err)
+	}
+}
+
+func TestReservationsEndpoints(t *testing.T) {
+	mux, db := setupTestServer(t)
+	createStockedVariant(t, db, "TEST_RESERVE", "TEST_RESERVE_SKU", 2)
+
+	t.Run("POST /reservations holds stock and confirm sells it", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPost, "/reservations", map[string]any{
+			"items": []map[string]any{{"sku": "TEST_RESERVE_SKU", "quantity": 1}},
+		})
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var created ReservationResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+		assert.Equal(t, models.ReservationPending, created.Status)
+		assert.Equal(t, []ReservationItemResponse{{SKU: "TEST_RESERVE_SKU", Warehouse: "MUC", Quantity: 1}}, created.Items)
+		assert.True(t, created.ExpiresAt.After(time.Now()))
+
+		w = testutil.DoJSON(mux, http.MethodPost, fmt.Sprintf("/reservations/%d/confirm", created.ID), nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var confirmed ReservationResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&confirmed))
+		assert.Equal(t, models.ReservationConfirmed, confirmed.Status)
+
+		w = testutil.DoJSON(mux, http.MethodPost, fmt.Sprintf("/reservations/%d/release", created.ID), nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("POST /reservations returns 409 when stock is short", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPost, "/reservations", map[string]any{
+			"items": []map[string]any{{"sku": "TEST_RESERVE_SKU", "quantity": 5}},
+		})
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("POST /reservations/{id}/release returns 404 for unknown reservation", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, testutil.DoJSON(mux, http.MethodPost, "/reservations/999999999/release", nil).Code)
+		assert.Equal(t, http.StatusNotFound, testutil.DoJSON(mux, http.MethodGet, "/reservations/abc", nil).Code)
+	})
+}
+
+func TestValidateCreateRequest(t *testing.T) {
+	cases := map[string]CreateReservationRequest{
+		"no items":          {},
+		"empty SKU":         {Items: []ReservationItemRequest{{SKU: " ", Quantity: 1}}},
+		"zero quantity":     {Items: []ReservationItemRequest{{SKU: "A", Quantity: 0}}},
+		"negative quantity": {Items: []ReservationItemRequest{{SKU: "A", Quantity: -1}}},
+	}
+
+	for name, req := range cases {
+		t.Run("rejects "+name, func(t *testing.T) {
+			assert.Error(t, validateCreateRequest(req))
+		})
+	}
+
+	t.Run("accepts items", func(t *testing.T) {
+		assert.NoError(t, validateCreateRequest(CreateReservationRequest{Items: []ReservationItemRequest{{SKU: "A", Quantity: 2}}}))
+	})
+}
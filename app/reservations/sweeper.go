@@ -0,0 +1,50 @@
+package reservations
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// DefaultSweepInterval is how often expired reservations are released when not configured
+const DefaultSweepInterval = time.Minute
+
+// RunSweeper releases expired reservations every interval until ctx is done.
+// Each tick drains all reservations that expired so far, batch by batch.
+func RunSweeper(ctx context.Context, repo models.ReservationRepository, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultSweepInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	slog.Info("Reservation sweeper started", "interval", interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Reservation sweeper stopped")
+			return
+		case <-ticker.C:
+			sweep(ctx, repo)
+		}
+	}
+}
+
+// sweep expires reservations until a batch comes back empty or fails
+func sweep(ctx context.Context, repo models.ReservationRepository) {
+	for ctx.Err() == nil {
+		expired, err := repo.ExpireReservations(time.Now().UTC())
+		if err != nil {
+			slog.Error("Failed to expire reservations", "error", err)
+			return
+		}
+		if expired == 0 {
+			return
+		}
+		slog.Info("Expired reservations", "count", expired)
+	}
+}
@@ -0,0 +1,87 @@
+package schemacheck
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// columnsSQL lists the columns of the tables and views of the current
+// schema
+const columnsSQL = `SELECT c.table_name, c.column_name, c.data_type, c.is_nullable,
+       COALESCE(c.numeric_precision, 0) AS numeric_precision, COALESCE(c.numeric_scale, 0) AS numeric_scale,
+       t.table_type
+FROM information_schema.columns c
+JOIN information_schema.tables t ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+WHERE c.table_schema = current_schema()
+ORDER BY c.table_name, c.ordinal_position`
+
+// indexesSQL lists the indexes of the tables of the current schema with
+// their key columns in order; expression columns are left out
+const indexesSQL = `SELECT t.relname AS table_name, ix.indisprimary AS is_primary, ix.indisunique AS is_unique,
+       ARRAY_TO_STRING(ARRAY(
+           SELECT a.attname FROM UNNEST(ix.indkey) WITH ORDINALITY AS k (attnum, n)
+           JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+           ORDER BY k.n), ',') AS columns
+FROM pg_index ix
+JOIN pg_class t ON t.oid = ix.indrelid
+JOIN pg_namespace ns ON ns.oid = t.relnamespace
+WHERE ns.nspname = current_schema() AND ix.indpred IS NULL
+ORDER BY t.relname`
+
+// Inspect reads the tables and views of the current schema, keyed by name.
+// Partial indexes are left out as they do not cover every row.
+func Inspect(db *gorm.DB) (map[string]Table, error) {
+	var columns []struct {
+		TableName        string
+		ColumnName       string
+		DataType         string
+		IsNullable       string
+		NumericPrecision int
+		NumericScale     int
+		TableType        string
+	}
+	if err := db.Raw(columnsSQL).Scan(&columns).Error; err != nil {
+		return nil, err
+	}
+
+	tables := make(map[string]Table)
+	for _, c := range columns {
+		table := tables[c.TableName]
+		table.Name = c.TableName
+		table.View = c.TableType == "VIEW"
+		column := Column{
+			Name:     c.ColumnName,
+			Type:     normalizeType(c.DataType),
+			Nullable: c.IsNullable == "YES",
+		}
+		if column.Type == TypeNumeric {
+			column.Precision, column.Scale = c.NumericPrecision, c.NumericScale
+		}
+		table.Columns = append(table.Columns, column)
+		tables[c.TableName] = table
+	}
+
+	var indexes []struct {
+		TableName string
+		IsPrimary bool
+		IsUnique  bool
+		Columns   string
+	}
+	if err := db.Raw(indexesSQL).Scan(&indexes).Error; err != nil {
+		return nil, err
+	}
+	for _, i := range indexes {
+		table, ok := tables[i.TableName]
+		if !ok || i.Columns == "" {
+			continue
+		}
+		columns := strings.Split(i.Columns, ",")
+		if i.IsPrimary {
+			table.PrimaryKey = columns
+		}
+		table.Indexes = append(table.Indexes, Index{Columns: columns, Unique: i.IsUnique})
+		tables[i.TableName] = table
+	}
+	return tables, nil
+}
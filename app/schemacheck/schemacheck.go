@@ -0,0 +1,297 @@
+// Package schemacheck compares the tables declared by GORM models with the
+// database schema and reports where they disagree.
+package schemacheck
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+// Column type families compared between models and the database. Columns
+// of other types are compared by their database type name only.
+const (
+	TypeInteger   = "integer"
+	TypeText      = "text"
+	TypeBoolean   = "boolean"
+	TypeTimestamp = "timestamp"
+	TypeNumeric   = "numeric"
+)
+
+// decimalPattern parses the type:decimal(p,s) tag of decimal fields
+var decimalPattern = regexp.MustCompile(`(?i)^(?:decimal|numeric)\((\d+),\s*(\d+)\)$`)
+
+// Column describes a column. Precision and Scale are set for numeric
+// columns with a declared precision.
+type Column struct {
+	Name      string
+	Type      string
+	Precision int
+	Scale     int
+	Nullable  bool
+}
+
+// TypeName formats the type of the column, e.g. numeric(10,2)
+func (c Column) TypeName() string {
+	if c.Type == TypeNumeric && c.Precision > 0 {
+		return fmt.Sprintf("numeric(%d,%d)", c.Precision, c.Scale)
+	}
+	return c.Type
+}
+
+// Index is an index over Columns, in order
+type Index struct {
+	Columns []string
+	Unique  bool
+}
+
+func (i Index) String() string {
+	kind := "index"
+	if i.Unique {
+		kind = "unique index"
+	}
+	return fmt.Sprintf("%s on (%s)", kind, strings.Join(i.Columns, ", "))
+}
+
+// Table describes a table or a view and its columns, primary key and
+// indexes
+type Table struct {
+	Name       string
+	View       bool
+	Columns    []Column
+	PrimaryKey []string
+	Indexes    []Index
+}
+
+func (t Table) column(name string) (Column, bool) {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+// Issue is a disagreement between a model and the database. Column is
+// empty for issues about the whole table.
+type Issue struct {
+	Table   string
+	Column  string
+	Message string
+}
+
+func (i Issue) String() string {
+	if i.Column == "" {
+		return fmt.Sprintf("%s: %s", i.Table, i.Message)
+	}
+	return fmt.Sprintf("%s.%s: %s", i.Table, i.Column, i.Message)
+}
+
+// Expected returns the tables the models declare. Only columns mapped by a
+// field are included; relations and fields tagged "-" are not.
+func Expected(namer schema.Namer, models ...any) ([]Table, error) {
+	cache := &sync.Map{}
+	tables := make([]Table, 0, len(models))
+	for _, model := range models {
+		s, err := schema.Parse(model, cache, namer)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %T: %w", model, err)
+		}
+
+		table := Table{Name: s.Table}
+		for _, name := range s.DBNames {
+			field := s.FieldsByDBName[name]
+			column := Column{Name: name, Nullable: !field.NotNull && !field.PrimaryKey && canHoldNull(field.FieldType)}
+			column.Type, column.Precision, column.Scale = fieldType(field)
+			table.Columns = append(table.Columns, column)
+
+			if field.Unique {
+				table.Indexes = append(table.Indexes, Index{Columns: []string{name}, Unique: true})
+			}
+		}
+		for _, field := range s.PrimaryFields {
+			table.PrimaryKey = append(table.PrimaryKey, field.DBName)
+		}
+		for _, index := range s.ParseIndexes() {
+			expected := Index{Unique: index.Class == "UNIQUE"}
+			for _, option := range index.Fields {
+				if option.Field != nil {
+					expected.Columns = append(expected.Columns, option.Field.DBName)
+				}
+			}
+			table.Indexes = append(table.Indexes, expected)
+		}
+		table.Indexes = uniqueIndexes(table.Indexes)
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// Compare reports how the actual tables, keyed by name, disagree with the
+// expected ones. Columns and indexes the models do not declare are not
+// reported, nor are primary keys, indexes and nullability of views: Postgres
+// reports every column of a view as nullable.
+func Compare(expected []Table, actual map[string]Table) []Issue {
+	var issues []Issue
+	for _, want := range expected {
+		have, ok := actual[want.Name]
+		if !ok {
+			issues = append(issues, Issue{Table: want.Name, Message: "table is missing from the database"})
+			continue
+		}
+
+		for _, column := range want.Columns {
+			if have.View {
+				column.Nullable = true
+			}
+			issues = append(issues, compareColumn(want.Name, column, have)...)
+		}
+		if have.View {
+			continue
+		}
+
+		if !equalColumns(want.PrimaryKey, have.PrimaryKey) {
+			issues = append(issues, Issue{Table: want.Name, Message: fmt.Sprintf(
+				"primary key is (%s) in the database, (%s) in the model",
+				strings.Join(have.PrimaryKey, ", "), strings.Join(want.PrimaryKey, ", "))})
+		}
+		for _, index := range want.Indexes {
+			if !hasIndex(have, index) {
+				issues = append(issues, Issue{Table: want.Name, Message: fmt.Sprintf("%s is missing from the database", index)})
+			}
+		}
+	}
+	return issues
+}
+
+func compareColumn(table string, want Column, have Table) []Issue {
+	got, ok := have.column(want.Name)
+	if !ok {
+		return []Issue{{Table: table, Column: want.Name, Message: "column is missing from the database"}}
+	}
+
+	var issues []Issue
+	if want.Type != "" && (want.Type != got.Type ||
+		(want.Type == TypeNumeric && want.Precision > 0 && (want.Precision != got.Precision || want.Scale != got.Scale))) {
+		issues = append(issues, Issue{Table: table, Column: want.Name, Message: fmt.Sprintf(
+			"type is %s in the database, %s in the model", got.TypeName(), want.TypeName())})
+	}
+	if got.Nullable && !want.Nullable {
+		issues = append(issues, Issue{Table: table, Column: want.Name, Message: "column is nullable in the database, NOT NULL in the model"})
+	}
+	return issues
+}
+
+// hasIndex reports whether table has an index serving want. A unique index
+// must cover exactly its columns; any index, primary keys included, whose
+// leading columns are the columns of a non-unique one serves it.
+func hasIndex(table Table, want Index) bool {
+	for _, index := range table.Indexes {
+		if want.Unique && index.Unique && equalColumns(index.Columns, want.Columns) {
+			return true
+		}
+		if !want.Unique && len(index.Columns) >= len(want.Columns) && equalColumns(index.Columns[:len(want.Columns)], want.Columns) {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueIndexes sorts indexes and drops duplicates, e.g. of a field tagged
+// both unique and uniqueIndex
+func uniqueIndexes(indexes []Index) []Index {
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return indexes[i].String() < indexes[j].String()
+	})
+	deduped := indexes[:0]
+	for i, index := range indexes {
+		if i == 0 || index.String() != indexes[i-1].String() {
+			deduped = append(deduped, index)
+		}
+	}
+	return deduped
+}
+
+func equalColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldType returns the type family of a field, from its type tag when
+// given, else from its Go type. It is empty when the family is unknown.
+func fieldType(field *schema.Field) (family string, precision, scale int) {
+	if tag := field.TagSettings["TYPE"]; tag != "" {
+		if match := decimalPattern.FindStringSubmatch(tag); match != nil {
+			precision, _ = strconv.Atoi(match[1])
+			scale, _ = strconv.Atoi(match[2])
+			return TypeNumeric, precision, scale
+		}
+		return normalizeType(strings.ToLower(tag)), 0, 0
+	}
+
+	t := field.FieldType
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t == reflect.TypeOf(time.Time{}):
+		return TypeTimestamp, 0, 0
+	case strings.HasSuffix(t.Name(), "Decimal"):
+		return TypeNumeric, 0, 0
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return TypeInteger, 0, 0
+	case reflect.String:
+		return TypeText, 0, 0
+	case reflect.Bool:
+		return TypeBoolean, 0, 0
+	}
+	return "", 0, 0
+}
+
+// canHoldNull reports whether a Go type scans NULL: pointers and Null*
+// style structs with a Valid flag
+func canHoldNull(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		return true
+	}
+	if t.Kind() == reflect.Struct {
+		if valid, ok := t.FieldByName("Valid"); ok && valid.Type.Kind() == reflect.Bool {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeType maps a Postgres type name to its family
+func normalizeType(name string) string {
+	switch name {
+	case "smallint", "integer", "bigint", "int", "int2", "int4", "int8", "serial", "bigserial":
+		return TypeInteger
+	case "character varying", "varchar", "character", "char", "text":
+		return TypeText
+	case "boolean", "bool":
+		return TypeBoolean
+	case "timestamp without time zone", "timestamp with time zone", "timestamp", "timestamptz", "date":
+		return TypeTimestamp
+	case "numeric", "decimal":
+		return TypeNumeric
+	}
+	return name
+}
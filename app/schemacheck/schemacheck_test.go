@@ -0,0 +1,146 @@
+package schemacheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm/schema"
+)
+
+type testProduct struct {
+	ID         uint                `gorm:"primaryKey"`
+	Code       string              `gorm:"uniqueIndex;not null"`
+	Price      decimal.Decimal     `gorm:"type:decimal(10,2);not null"`
+	SalePrice  decimal.NullDecimal `gorm:"type:decimal(10,2)"`
+	CategoryID uint                `gorm:"index"`
+	Note       *string
+	CreatedAt  time.Time
+	Internal   string `gorm:"-"`
+}
+
+func (p *testProduct) TableName() string {
+	return "products"
+}
+
+// matchingProducts is the products table testProduct declares
+func matchingProducts() Table {
+	return Table{
+		Name: "products",
+		Columns: []Column{
+			{Name: "id", Type: TypeInteger},
+			{Name: "code", Type: TypeText},
+			{Name: "price", Type: TypeNumeric, Precision: 10, Scale: 2},
+			{Name: "sale_price", Type: TypeNumeric, Precision: 10, Scale: 2, Nullable: true},
+			{Name: "category_id", Type: TypeInteger},
+			{Name: "note", Type: TypeText, Nullable: true},
+			{Name: "created_at", Type: TypeTimestamp},
+			{Name: "search_vector", Type: "tsvector", Nullable: true},
+		},
+		PrimaryKey: []string{"id"},
+		Indexes: []Index{
+			{Columns: []string{"id"}, Unique: true},
+			{Columns: []string{"code"}, Unique: true},
+			{Columns: []string{"category_id", "code"}},
+		},
+	}
+}
+
+func TestExpected(t *testing.T) {
+	tables, err := Expected(schema.NamingStrategy{}, &testProduct{})
+
+	assert.NoError(t, err)
+	if assert.Len(t, tables, 1) {
+		table := tables[0]
+		assert.Equal(t, "products", table.Name)
+		assert.Equal(t, []string{"id"}, table.PrimaryKey)
+		assert.Equal(t, []Column{
+			{Name: "id", Type: TypeInteger},
+			{Name: "code", Type: TypeText},
+			{Name: "price", Type: TypeNumeric, Precision: 10, Scale: 2},
+			{Name: "sale_price", Type: TypeNumeric, Precision: 10, Scale: 2, Nullable: true},
+			{Name: "category_id", Type: TypeInteger},
+			{Name: "note", Type: TypeText, Nullable: true},
+			{Name: "created_at", Type: TypeTimestamp},
+		}, table.Columns)
+		assert.ElementsMatch(t, []Index{
+			{Columns: []string{"code"}, Unique: true},
+			{Columns: []string{"category_id"}},
+		}, table.Indexes)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	expected, _ := Expected(schema.NamingStrategy{}, &testProduct{})
+
+	t.Run("no issues for a matching table", func(t *testing.T) {
+		issues := Compare(expected, map[string]Table{"products": matchingProducts()})
+		assert.Empty(t, issues)
+	})
+
+	t.Run("reports a missing table", func(t *testing.T) {
+		issues := Compare(expected, map[string]Table{})
+		assert.Equal(t, []Issue{{Table: "products", Message: "table is missing from the database"}}, issues)
+	})
+
+	t.Run("reports column mismatches", func(t *testing.T) {
+		products := matchingProducts()
+		products.Columns[1].Nullable = true                                      // code
+		products.Columns[2].Precision = 12                                       // price
+		products.Columns[4] = Column{Name: "category_id", Type: TypeText}        // category_id
+		products.Columns = append(products.Columns[:5], products.Columns[6:]...) // note
+
+		issues := Compare(expected, map[string]Table{"products": products})
+		assert.Equal(t, []Issue{
+			{Table: "products", Column: "code", Message: "column is nullable in the database, NOT NULL in the model"},
+			{Table: "products", Column: "price", Message: "type is numeric(12,2) in the database, numeric(10,2) in the model"},
+			{Table: "products", Column: "category_id", Message: "type is text in the database, integer in the model"},
+			{Table: "products", Column: "note", Message: "column is missing from the database"},
+		}, issues)
+	})
+
+	t.Run("reports nullable columns the model cannot scan", func(t *testing.T) {
+		products := matchingProducts()
+		products.Columns[4].Nullable = true // category_id
+
+		issues := Compare(expected, map[string]Table{"products": products})
+		assert.Equal(t, []Issue{
+			{Table: "products", Column: "category_id", Message: "column is nullable in the database, NOT NULL in the model"},
+		}, issues)
+	})
+
+	t.Run("reports primary key and index mismatches", func(t *testing.T) {
+		products := matchingProducts()
+		products.PrimaryKey = []string{"code"}
+		products.Indexes = []Index{{Columns: []string{"code"}, Unique: true}}
+
+		issues := Compare(expected, map[string]Table{"products": products})
+		assert.Equal(t, []Issue{
+			{Table: "products", Message: "primary key is (code) in the database, (id) in the model"},
+			{Table: "products", Message: "index on (category_id) is missing from the database"},
+		}, issues)
+	})
+
+	t.Run("a unique index must be unique", func(t *testing.T) {
+		products := matchingProducts()
+		products.Indexes = []Index{{Columns: []string{"code"}}, {Columns: []string{"category_id"}}}
+
+		issues := Compare(expected, map[string]Table{"products": products})
+		assert.Equal(t, []Issue{
+			{Table: "products", Message: "unique index on (code) is missing from the database"},
+		}, issues)
+	})
+
+	t.Run("ignores keys, indexes and nullability of views", func(t *testing.T) {
+		products := matchingProducts()
+		products.View = true
+		products.PrimaryKey = nil
+		products.Indexes = nil
+		for i := range products.Columns {
+			products.Columns[i].Nullable = true
+		}
+
+		assert.Empty(t, Compare(expected, map[string]Table{"products": products}))
+	})
+}
@@ -0,0 +1,199 @@
+package stock
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// StockResponse represents the stock of a variant SKU. Without Warehouse it
+// is the total over the active warehouses, broken down in Warehouses.
+type StockResponse struct {
+	SKU        string          `json:"sku"`
+	Warehouse  string          `json:"warehouse,omitempty"`
+	OnHand     int             `json:"on_hand"`
+	Reserved   int             `json:"reserved"`
+	Available  int             `json:"available"`
+	Warehouses []StockResponse `json:"warehouses,omitempty"`
+}
+
+// WarehouseResponse represents a warehouse
+type WarehouseResponse struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Active  bool   `json:"active"`
+	Default bool   `json:"default"`
+}
+
+// SetStockRequest is the body accepted by PUT /stock/{sku}.
+// Without a warehouse the default warehouse is updated.
+type SetStockRequest struct {
+	Warehouse string `json:"warehouse"`
+	OnHand    *int   `json:"on_hand"`
+}
+
+// AdjustStockRequest is the body accepted by POST /stock/{sku}/adjustments.
+// Delta is added to the units on hand; use a negative value to remove units.
+// Without a warehouse the default warehouse is adjusted.
+type AdjustStockRequest struct {
+	Warehouse string `json:"warehouse"`
+	Delta     int    `json:"delta"`
+	Reason    string `json:"reason"`
+}
+
+type StockHandler struct {
+	repo models.StockRepository
+}
+
+func NewStockHandler(repo models.StockRepository) *StockHandler {
+	return &StockHandler{repo: repo}
+}
+
+// HandleListWarehouses handles GET /warehouses
+func (h *StockHandler) HandleListWarehouses(w http.ResponseWriter, r *http.Request) {
+	slog.Info("Fetching all warehouses")
+
+	warehouses, err := h.repo.GetWarehouses()
+	if err != nil {
+		slog.Error("Failed to fetch warehouses", "error", err)
+		api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	response := make([]WarehouseResponse, len(warehouses))
+	for i, wh := range warehouses {
+		response[i] = WarehouseResponse{
+			Code:    wh.Code,
+			Name:    wh.Name,
+			Active:  wh.Active,
+			Default: wh.IsDefault,
+		}
+	}
+
+	api.OKResponse(w, response)
+}
+
+// HandleGet handles GET /stock/{sku} - returns the total stock with its
+// per-warehouse breakdown
+func (h *StockHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	sku := r.PathValue("sku")
+
+	slog.Info("Fetching stock", "sku", sku)
+
+	levels, err := h.repo.GetStock(sku)
+	if err != nil {
+		writeStockError(w, sku, err)
+		return
+	}
+
+	total := models.AggregateStock(sku, levels)
+	response := StockResponse{
+		SKU:       sku,
+		OnHand:    total.OnHand,
+		Reserved:  total.Reserved,
+		Available: total.Available(),
+	}
+	for i := range levels {
+		response.Warehouses = append(response.Warehouses, mapStockResponse(&levels[i]))
+	}
+
+	api.OKResponse(w, response)
+}
+
+// HandleSet handles PUT /stock/{sku} - overwrites the units on hand
+func (h *StockHandler) HandleSet(w http.ResponseWriter, r *http.Request) {
+	sku := r.PathValue("sku")
+
+	var req SetStockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Warn("Invalid request body", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.OnHand == nil || *req.OnHand < 0 {
+		slog.Warn("Invalid stock request", "sku", sku)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid on_hand: must be zero or a positive integer")
+		return
+	}
+
+	slog.Info("Setting stock", "sku", sku, "warehouse", req.Warehouse, "onHand", *req.OnHand)
+
+	stock, err := h.repo.SetStock(sku, req.Warehouse, *req.OnHand)
+	if err != nil {
+		writeStockError(w, sku, err)
+		return
+	}
+
+	slog.Info("Successfully set stock", "sku", sku, "available", stock.Available())
+
+	api.OKResponse(w, mapStockResponse(stock))
+}
+
+// HandleAdjust handles POST /stock/{sku}/adjustments - adds or removes units on hand
+func (h *StockHandler) HandleAdjust(w http.ResponseWriter, r *http.Request) {
+	sku := r.PathValue("sku")
+
+	var req AdjustStockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Warn("Invalid request body", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Delta == 0 {
+		slog.Warn("Invalid stock adjustment", "sku", sku)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid delta: must be a non-zero integer")
+		return
+	}
+
+	slog.Info("Adjusting stock", "sku", sku, "warehouse", req.Warehouse, "delta", req.Delta, "reason", req.Reason)
+
+	stock, err := h.repo.AdjustStock(sku, req.Warehouse, req.Delta)
+	if err != nil {
+		writeStockError(w, sku, err)
+		return
+	}
+
+	slog.Info("Successfully adjusted stock", "sku", sku, "available", stock.Available())
+
+	api.OKResponse(w, mapStockResponse(stock))
+}
+
+// writeStockError maps repository errors of stock operations to HTTP responses
+func writeStockError(w http.ResponseWriter, sku string, err error) {
+	switch {
+	case errors.Is(err, models.ErrVariantNotFound):
+		slog.Warn("Variant not found", "sku", sku)
+		api.ErrorResponse(w, http.StatusNotFound, "Variant not found")
+	case errors.Is(err, models.ErrWarehouseNotFound):
+		slog.Warn("Warehouse not found", "sku", sku)
+		api.ErrorResponse(w, http.StatusBadRequest, "Warehouse not found")
+	case errors.Is(err, models.ErrInsufficientStock):
+		slog.Warn("Insufficient stock", "sku", sku)
+		api.ErrorResponse(w, http.StatusConflict, "Insufficient stock: on hand cannot drop below zero or reserved units")
+	default:
+		slog.Error("Failed to process stock", "sku", sku, "error", err)
+		api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+// mapStockResponse maps the stock level of a warehouse to its API response
+func mapStockResponse(stock *models.StockLevel) StockResponse {
+	var warehouse string
+	if stock.Warehouse != nil {
+		warehouse = stock.Warehouse.Code
+	}
+
+	return StockResponse{
+		SKU:       stock.SKU,
+		Warehouse: warehouse,
+		OnHand:    stock.OnHand,
+		Reserved:  stock.Reserved,
+		Available: stock.Available(),
+	}
+}
@@ -0,0 +1,107 @@
+package stock
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/testutil"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupTestServer(t *testing.T) (*http.ServeMux, *gorm.DB) {
+	db := testutil.SetupTestDB()
+
+	handler := NewStockHandler(models.NewStockLevelsRepository(db))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /warehouses", handler.HandleListWarehouses)
+	mux.HandleFunc("GET /stock/{sku}", handler.HandleGet)
+	mux.HandleFunc("PUT /stock/{sku}", handler.HandleSet)
+	mux.HandleFunc("POST /stock/{sku}/adjustments", handler.HandleAdjust)
+
+	return mux, db
+}
+
+func TestStockEndpoints(t *testing.T) {
+	mux, db := setupTestServer(t)
+	testutil.CreateProduct(t, db, "TEST_STOCK", "TEST_STOCK_SKU")
+
+	t.Run("GET /stock/{sku} reports zero for a variant without stock", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodGet, "/stock/TEST_STOCK_SKU", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response StockResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, StockResponse{SKU: "TEST_STOCK_SKU"}, response)
+	})
+
+	t.Run("PUT /stock/{sku} sets units on hand", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPut, "/stock/TEST_STOCK_SKU", map[string]any{"on_hand": 5})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response StockResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, "MUC", response.Warehouse, "Stock without warehouse goes to the default one")
+		assert.Equal(t, 5, response.OnHand)
+		assert.Equal(t, 5, response.Available)
+	})
+
+	t.Run("PUT /stock/{sku} sets units in a named warehouse", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPut, "/stock/TEST_STOCK_SKU", map[string]any{"warehouse": "LEJ", "on_hand": 2})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = testutil.DoJSON(mux, http.MethodGet, "/stock/TEST_STOCK_SKU", nil)
+
+		var response StockResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, 7, response.Available)
+		assert.Len(t, response.Warehouses, 2)
+	})
+
+	t.Run("PUT /stock/{sku} returns 400 for unknown warehouse", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPut, "/stock/TEST_STOCK_SKU", map[string]any{"warehouse": "NOPE", "on_hand": 2})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("POST /stock/{sku}/adjustments adds and removes units", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPost, "/stock/TEST_STOCK_SKU/adjustments", map[string]any{"delta": -2, "reason": "damaged"})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response StockResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, 3, response.OnHand)
+	})
+
+	t.Run("POST /stock/{sku}/adjustments returns 409 below zero", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPost, "/stock/TEST_STOCK_SKU/adjustments", map[string]any{"delta": -4})
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("returns 404 for unknown SKU", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, testutil.DoJSON(mux, http.MethodGet, "/stock/UNKNOWN_SKU", nil).Code)
+		assert.Equal(t, http.StatusNotFound, testutil.DoJSON(mux, http.MethodPut, "/stock/UNKNOWN_SKU", map[string]any{"on_hand": 1}).Code)
+	})
+
+	t.Run("rejects invalid bodies", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, testutil.DoJSON(mux, http.MethodPut, "/stock/TEST_STOCK_SKU", map[string]any{"on_hand": -1}).Code)
+		assert.Equal(t, http.StatusBadRequest, testutil.DoJSON(mux, http.MethodPost, "/stock/TEST_STOCK_SKU/adjustments", map[string]any{"delta": 0}).Code)
+	})
+}
+
+func TestWarehousesEndpoint(t *testing.T) {
+	mux, _ := setupTestServer(t)
+
+	t.Run("GET /warehouses lists the distribution centres", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodGet, "/warehouses", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []WarehouseResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Len(t, response, 3)
+	})
+}
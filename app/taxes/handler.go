@@ -0,0 +1,251 @@
+package taxes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+// Column sizes of the tax_classes table
+const (
+	maxCodeLength = 32
+	maxNameLength = 256
+)
+
+// countryPattern matches ISO 3166-1 alpha-2 country codes
+var countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// TaxClassRequest is the body accepted by POST /tax-classes
+type TaxClassRequest struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// TaxClassResponse represents a tax class
+type TaxClassResponse struct {
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	IsDefault bool   `json:"is_default"`
+}
+
+// VATRateRequest is the body accepted by PUT /vat-rates/{country}/{class}.
+// Rate is a fraction, e.g. 0.19 for 19%.
+type VATRateRequest struct {
+	Rate decimal.Decimal `json:"rate"`
+}
+
+// VATRateResponse represents the VAT rate of a tax class in a country.
+// Rate is a fraction rendered as a JSON number, see api.Rate.
+type VATRateResponse struct {
+	Country  string   `json:"country"`
+	TaxClass string   `json:"tax_class"`
+	Rate     api.Rate `json:"rate"`
+}
+
+type TaxesHandler struct {
+	repo models.TaxRepository
+}
+
+func NewTaxesHandler(repo models.TaxRepository) *TaxesHandler {
+	return &TaxesHandler{repo: repo}
+}
+
+// HandleListClasses handles GET /tax-classes
+func (h *TaxesHandler) HandleListClasses(w http.ResponseWriter, r *http.Request) {
+	slog.Info("Fetching tax classes")
+
+	classes, err := h.repo.GetTaxClasses()
+	if err != nil {
+		slog.Error("Failed to fetch tax classes", "error", err)
+		api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	response := make([]TaxClassResponse, len(classes))
+	for i := range classes {
+		response[i] = mapTaxClassResponse(&classes[i])
+	}
+
+	api.OKResponse(w, response)
+}
+
+// HandleCreateClass handles POST /tax-classes
+func (h *TaxesHandler) HandleCreateClass(w http.ResponseWriter, r *http.Request) {
+	var req TaxClassRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Warn("Invalid request body", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := validateTaxClassRequest(req); err != nil {
+		slog.Warn("Invalid tax class request", "code", req.Code, "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slog.Info("Creating tax class", "code", req.Code)
+
+	class := &models.TaxClass{Code: req.Code, Name: req.Name}
+	if err := h.repo.CreateTaxClass(class); err != nil {
+		writeTaxError(w, req.Code, err)
+		return
+	}
+
+	slog.Info("Successfully created tax class", "code", class.Code)
+
+	api.CreatedResponse(w, mapTaxClassResponse(class))
+}
+
+// HandleListRates handles GET /vat-rates, optionally filtered by ?country=
+func (h *TaxesHandler) HandleListRates(w http.ResponseWriter, r *http.Request) {
+	country := r.URL.Query().Get("country")
+	if country != "" && !countryPattern.MatchString(country) {
+		slog.Warn("Invalid country parameter", "value", country)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid country: must be an ISO 3166-1 alpha-2 code such as DE")
+		return
+	}
+
+	slog.Info("Fetching VAT rates", "country", country)
+
+	rates, err := h.repo.GetVATRates(country)
+	if err != nil {
+		slog.Error("Failed to fetch VAT rates", "country", country, "error", err)
+		api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	response := make([]VATRateResponse, len(rates))
+	for i := range rates {
+		response[i] = mapVATRateResponse(&rates[i])
+	}
+
+	api.OKResponse(w, response)
+}
+
+// HandleSetRate handles PUT /vat-rates/{country}/{class} - creates or
+// replaces the rate of a tax class in a country
+func (h *TaxesHandler) HandleSetRate(w http.ResponseWriter, r *http.Request) {
+	country, class := r.PathValue("country"), r.PathValue("class")
+
+	var req VATRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Warn("Invalid request body", "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := validateVATRateRequest(country, req); err != nil {
+		slog.Warn("Invalid VAT rate request", "country", country, "class", class, "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slog.Info("Setting VAT rate", "country", country, "class", class, "rate", req.Rate)
+
+	rate, err := h.repo.SetVATRate(country, class, req.Rate)
+	if err != nil {
+		writeTaxError(w, class, err)
+		return
+	}
+
+	slog.Info("Successfully set VAT rate", "country", country, "class", class)
+
+	api.OKResponse(w, mapVATRateResponse(rate))
+}
+
+// HandleDeleteRate handles DELETE /vat-rates/{country}/{class}
+func (h *TaxesHandler) HandleDeleteRate(w http.ResponseWriter, r *http.Request) {
+	country, class := r.PathValue("country"), r.PathValue("class")
+
+	slog.Info("Deleting VAT rate", "country", country, "class", class)
+
+	if err := h.repo.DeleteVATRate(country, class); err != nil {
+		writeTaxError(w, class, err)
+		return
+	}
+
+	slog.Info("Successfully deleted VAT rate", "country", country, "class", class)
+
+	api.NoContentResponse(w)
+}
+
+// validateTaxClassRequest checks the fields of a tax class write request.
+// The returned error message is safe to send to the client.
+func validateTaxClassRequest(req TaxClassRequest) error {
+	if strings.TrimSpace(req.Code) == "" || strings.TrimSpace(req.Name) == "" {
+		return errors.New("Code and name are required")
+	}
+	if len(req.Code) > maxCodeLength {
+		return fmt.Errorf("Code must be at most %d characters", maxCodeLength)
+	}
+	if len(req.Name) > maxNameLength {
+		return fmt.Errorf("Name must be at most %d characters", maxNameLength)
+	}
+	return nil
+}
+
+// validateVATRateRequest checks the country and rate of a VAT rate write request.
+// The returned error message is safe to send to the client.
+func validateVATRateRequest(country string, req VATRateRequest) error {
+	if !countryPattern.MatchString(country) {
+		return errors.New("Invalid country: must be an ISO 3166-1 alpha-2 code such as DE")
+	}
+	if req.Rate.IsNegative() || !req.Rate.LessThan(decimal.NewFromInt(1)) {
+		return errors.New("Invalid rate: must be at least 0 and less than 1")
+	}
+	if req.Rate.Exponent() < -4 {
+		return errors.New("Invalid rate: at most 4 decimal places")
+	}
+	return nil
+}
+
+// writeTaxError maps repository errors to HTTP responses
+func writeTaxError(w http.ResponseWriter, code string, err error) {
+	switch {
+	case errors.Is(err, models.ErrTaxClassNotFound):
+		slog.Warn("Tax class not found", "code", code)
+		api.ErrorResponse(w, http.StatusNotFound, "Tax class not found")
+	case errors.Is(err, models.ErrVATRateNotFound):
+		slog.Warn("VAT rate not found", "code", code)
+		api.ErrorResponse(w, http.StatusNotFound, "VAT rate not found")
+	case errors.Is(err, models.ErrTaxClassCodeExists):
+		slog.Warn("Duplicate tax class code", "code", code)
+		api.ErrorResponse(w, http.StatusConflict, "Tax class code already exists")
+	case errors.Is(err, models.ErrInvalidTaxClass), errors.Is(err, models.ErrInvalidVATRate):
+		slog.Warn("Invalid tax data", "code", code, "error", err)
+		api.ErrorResponse(w, http.StatusBadRequest, err.Error())
+	default:
+		slog.Error("Failed to process tax data", "code", code, "error", err)
+		api.ErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+// mapTaxClassResponse maps a tax class model to its API response
+func mapTaxClassResponse(class *models.TaxClass) TaxClassResponse {
+	return TaxClassResponse{
+		Code:      class.Code,
+		Name:      class.Name,
+		IsDefault: class.IsDefault,
+	}
+}
+
+// mapVATRateResponse maps a VAT rate model to its API response
+func mapVATRateResponse(rate *models.VATRate) VATRateResponse {
+	response := VATRateResponse{
+		Country: rate.CountryCode,
+		Rate:    api.NewRate(rate.Rate),
+	}
+	if rate.TaxClass != nil {
+		response.TaxClass = rate.TaxClass.Code
+	}
+	return response
+}
@@ -0,0 +1,100 @@
+package taxes
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/internal/testutil"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupTestServer() (*http.ServeMux, *gorm.DB) {
+	db := testutil.SetupTestDB()
+
+	handler := NewTaxesHandler(models.NewTaxesRepository(db))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tax-classes", handler.HandleListClasses)
+	mux.HandleFunc("POST /tax-classes", handler.HandleCreateClass)
+	mux.HandleFunc("GET /vat-rates", handler.HandleListRates)
+	mux.HandleFunc("PUT /vat-rates/{country}/{class}", handler.HandleSetRate)
+	mux.HandleFunc("DELETE /vat-rates/{country}/{class}", handler.HandleDeleteRate)
+
+	return mux, db
+}
+
+func TestValidateVATRateRequest(t *testing.T) {
+	assert.NoError(t, validateVATRateRequest("DE", VATRateRequest{Rate: decimal.RequireFromString("0.19")}))
+	assert.NoError(t, validateVATRateRequest("DE", VATRateRequest{Rate: decimal.Zero}))
+
+	assert.Error(t, validateVATRateRequest("de", VATRateRequest{Rate: decimal.RequireFromString("0.19")}))
+	assert.Error(t, validateVATRateRequest("DEU", VATRateRequest{Rate: decimal.RequireFromString("0.19")}))
+	assert.Error(t, validateVATRateRequest("DE", VATRateRequest{Rate: decimal.RequireFromString("-0.01")}))
+	assert.Error(t, validateVATRateRequest("DE", VATRateRequest{Rate: decimal.NewFromInt(1)}))
+	assert.Error(t, validateVATRateRequest("DE", VATRateRequest{Rate: decimal.RequireFromString("0.12345")}))
+}
+
+func TestTaxEndpoints(t *testing.T) {
+	mux, db := setupTestServer()
+	testutil.CleanupByCode(t, db, &models.TaxClass{}, "TEST_LUXURY")
+
+	t.Run("GET /tax-classes lists the seeded classes", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodGet, "/tax-classes", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var classes []TaxClassResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &classes))
+		assert.Contains(t, classes, TaxClassResponse{Code: "STANDARD", Name: "Standard rate", IsDefault: true})
+	})
+
+	t.Run("POST /tax-classes creates a class and rejects duplicates", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPost, "/tax-classes", TaxClassRequest{Code: "TEST_LUXURY", Name: "Luxury goods"})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = testutil.DoJSON(mux, http.MethodPost, "/tax-classes", TaxClassRequest{Code: "TEST_LUXURY", Name: "Luxury goods"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("PUT /vat-rates sets and replaces a rate", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPut, "/vat-rates/DE/TEST_LUXURY", map[string]any{"rate": "0.25"})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = testutil.DoJSON(mux, http.MethodPut, "/vat-rates/DE/TEST_LUXURY", map[string]any{"rate": "0.22"})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var rate VATRateResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &rate))
+		assert.Equal(t, "DE", rate.Country)
+		assert.Equal(t, "TEST_LUXURY", rate.TaxClass)
+		assert.True(t, rate.Rate.Equal(decimal.RequireFromString("0.22")))
+	})
+
+	t.Run("GET /vat-rates filters by country", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodGet, "/vat-rates?country=DE", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var rates []VATRateResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &rates))
+		assert.NotEmpty(t, rates)
+		for _, rate := range rates {
+			assert.Equal(t, "DE", rate.Country)
+		}
+	})
+
+	t.Run("PUT /vat-rates with an unknown class returns 404", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodPut, "/vat-rates/DE/NOPE", map[string]any{"rate": "0.1"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("DELETE /vat-rates removes a rate", func(t *testing.T) {
+		w := testutil.DoJSON(mux, http.MethodDelete, "/vat-rates/DE/TEST_LUXURY", nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		w = testutil.DoJSON(mux, http.MethodDelete, "/vat-rates/DE/TEST_LUXURY", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+
+	"github.com/mytheresa/go-hiring-challenge/app/catalog"
+	"github.com/mytheresa/go-hiring-challenge/app/database"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// importActor is recorded as the author of the imported prices unless
+// -actor is given
+const importActor = "import"
+
+// main imports the products and variants of a CSV file, see
+// catalog.ImportColumns for its columns, and prints the rejected rows. It
+// exits with status 1 if a row was rejected.
+//
+// Usage: import [-chunk-size n] [-actor name] file.csv
+func main() {
+	chunkSize := flag.Int("chunk-size", 0, "commit every n products and skip rejected ones; 0 imports the file in a single transaction")
+	actor := flag.String("actor", importActor, "author of the price changes in the price history")
+	flag.Parse()
+	if flag.NArg() != 1 || *chunkSize < 0 {
+		log.Fatalf("usage: import [-chunk-size n] [-actor name] file.csv")
+	}
+
+	file, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("opening import file: %v", err)
+	}
+	defer file.Close()
+
+	// Load environment variables from .env file
+	if err := godotenv.Load(".env"); err != nil {
+		log.Fatalf("Error loading .env file: %s", err)
+	}
+
+	// Initialize database connection
+	db, close := database.New(
+		os.Getenv("POSTGRES_USER"),
+		os.Getenv("POSTGRES_PASSWORD"),
+		os.Getenv("POSTGRES_DB"),
+		os.Getenv("POSTGRES_PORT"),
+	)
+	defer close()
+
+	repo := models.NewProductsRepository(db).WithActor(*actor)
+	report, err := catalog.Import(repo, file, *chunkSize)
+	if err != nil {
+		close()
+		log.Fatalf("import failed: %v", err)
+	}
+
+	for _, e := range report.Errors {
+		fmt.Printf("row %d\t%s\t%s\t%s\n", e.Row, e.Code, e.SKU, e.Message)
+	}
+	log.Printf("Read %d rows of %d products: %d created, %d updated", report.Rows, report.Products, report.Created, report.Updated)
+	if len(report.Errors) > 0 {
+		close()
+		if *chunkSize == 0 {
+			log.Fatalf("%d row(s) rejected, nothing was imported", len(report.Errors))
+		}
+		log.Fatalf("%d row(s) rejected and skipped", len(report.Errors))
+	}
+}
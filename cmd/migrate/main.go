@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/mytheresa/go-hiring-challenge/app/database"
+	"github.com/mytheresa/go-hiring-challenge/app/migrate"
+	sqlmigrations "github.com/mytheresa/go-hiring-challenge/sql/migrations"
+)
+
+const usage = `usage: migrate <command>
+
+commands:
+  up [n]     apply the pending migrations, or the next n
+  down [n]   revert the last applied migration, or the last n
+  status     list the migrations and whether they are applied
+  redo       revert the last applied migration and apply it again`
+
+func main() {
+	// Load environment variables from .env file
+	if err := godotenv.Load(".env"); err != nil {
+		log.Fatalf("Error loading .env file: %s", err)
+	}
+
+	if len(os.Args) < 2 || len(os.Args) > 3 {
+		log.Fatal(usage)
+	}
+	command := os.Args[1]
+	limit := 0
+	if len(os.Args) == 3 {
+		n, err := strconv.Atoi(os.Args[2])
+		if err != nil || n < 1 || (command != "up" && command != "down") {
+			log.Fatal(usage)
+		}
+		limit = n
+	}
+
+	migrations, err := migrate.Load(sqlmigrations.FS)
+	if err != nil {
+		log.Fatalf("loading migrations failed: %v", err)
+	}
+
+	// Initialize database connection
+	db, close := database.New(
+		os.Getenv("POSTGRES_USER"),
+		os.Getenv("POSTGRES_PASSWORD"),
+		os.Getenv("POSTGRES_DB"),
+		os.Getenv("POSTGRES_PORT"),
+	)
+	defer close()
+
+	migrator := migrate.New(db, migrations)
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(limit)
+		for _, m := range applied {
+			log.Printf("Applied %s", m)
+		}
+		if err != nil {
+			log.Fatalf("migrating up failed: %v", err)
+		}
+		if len(applied) == 0 {
+			log.Printf("No pending migrations")
+		}
+	case "down":
+		reverted, err := migrator.Down(limit)
+		for _, m := range reverted {
+			log.Printf("Reverted %s", m)
+		}
+		if err != nil {
+			log.Fatalf("migrating down failed: %v", err)
+		}
+	case "redo":
+		m, err := migrator.Redo()
+		if err != nil {
+			log.Fatalf("redoing migration failed: %v", err)
+		}
+		log.Printf("Redid %s", m)
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			log.Fatalf("reading migration status failed: %v", err)
+		}
+		printStatus(statuses)
+	default:
+		log.Fatal(usage)
+	}
+}
+
+func printStatus(statuses []migrate.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, s := range statuses {
+		appliedAt := "-"
+		if s.AppliedAt != nil {
+			appliedAt = s.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%03d\t%s\t%s\t%s\n", s.Version, s.Name, s.State, appliedAt)
+	}
+	w.Flush()
+}
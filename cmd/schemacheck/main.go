@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+
+	"github.com/mytheresa/go-hiring-challenge/app/database"
+	"github.com/mytheresa/go-hiring-challenge/app/schemacheck"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// tables lists the models mapped to database tables and views
+var tables = []any{
+	&models.Product{},
+	&models.Variant{},
+	&models.Category{},
+	&models.Attribute{},
+	&models.VariantOption{},
+	&models.Currency{},
+	&models.ExchangeRate{},
+	&models.ProductPrice{},
+	&models.VariantPrice{},
+	&models.ScheduledPrice{},
+	&models.PriceChange{},
+	&models.Promotion{},
+	&models.TaxClass{},
+	&models.VATRate{},
+	&models.Warehouse{},
+	&models.StockLevel{},
+	&models.VariantStock{},
+	&models.Reservation{},
+	&models.ReservationItem{},
+}
+
+// main reports where the GORM models disagree with the database schema and
+// exits with status 1 if they do
+func main() {
+	// Load environment variables from .env file
+	if err := godotenv.Load(".env"); err != nil {
+		log.Fatalf("Error loading .env file: %s", err)
+	}
+
+	// Initialize database connection
+	db, close := database.New(
+		os.Getenv("POSTGRES_USER"),
+		os.Getenv("POSTGRES_PASSWORD"),
+		os.Getenv("POSTGRES_DB"),
+		os.Getenv("POSTGRES_PORT"),
+	)
+	defer close()
+
+	expected, err := schemacheck.Expected(db.NamingStrategy, tables...)
+	if err != nil {
+		log.Fatalf("parsing models failed: %v", err)
+	}
+	actual, err := schemacheck.Inspect(db)
+	if err != nil {
+		log.Fatalf("reading database schema failed: %v", err)
+	}
+
+	issues := schemacheck.Compare(expected, actual)
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	if len(issues) > 0 {
+		close()
+		log.Fatalf("%d schema mismatch(es) between the models and the database", len(issues))
+	}
+	log.Printf("Models match the database schema")
+}
@@ -1,23 +1,61 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
 	"log"
 	"os"
-	"path/filepath"
 	"sort"
-	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/joho/godotenv"
+	"gorm.io/gorm"
 
 	"github.com/mytheresa/go-hiring-challenge/app/database"
+	"github.com/mytheresa/go-hiring-challenge/app/datagen"
 )
 
+// seedActor is recorded as the author of the seeded prices
+const seedActor = "seed"
+
+// resetFile is the seed file removing the data of a previous run
+const resetFile = "000-reset.sql"
+
+// main loads the demo data into a migrated database, replacing the data
+// of a previous run. Every .sql file of POSTGRES_SEED_DIR is executed in
+// name order within a single transaction.
+//
+// "seed generate" replaces the data with a synthetic catalog instead, see
+// the flags of generate.
 func main() {
 	// Load environment variables from .env file
 	if err := godotenv.Load(".env"); err != nil {
 		log.Fatalf("Error loading .env file: %s", err)
 	}
 
+	var config datagen.Config
+	generateMode := len(os.Args) > 1 && os.Args[1] == "generate"
+	if generateMode {
+		flags := flag.NewFlagSet("generate", flag.ExitOnError)
+		flags.IntVar(&config.Categories, "categories", 50, "number of categories")
+		flags.IntVar(&config.Products, "products", 10000, "number of products")
+		flags.IntVar(&config.Variants, "variants", 3, "average number of variants per product")
+		flags.Float64Var(&config.NullPriceRatio, "null-price-ratio", 0.6, "share of variants inheriting the product price")
+		flags.Uint64Var(&config.Seed, "seed", 1, "random seed; the same seed generates the same catalog")
+		flags.Parse(os.Args[2:])
+		if err := config.Validate(); err != nil {
+			log.Fatalf("invalid generate flags: %v", err)
+		}
+	} else if len(os.Args) > 1 {
+		log.Fatalf("usage: seed [generate [flags]]")
+	}
+
+	dir := os.DirFS(os.Getenv("POSTGRES_SEED_DIR"))
+
 	// Initialize database connection
 	db, close := database.New(
 		os.Getenv("POSTGRES_USER"),
@@ -27,37 +65,73 @@ func main() {
 	)
 	defer close()
 
-	dir := os.Getenv("POSTGRES_SQL_DIR")
-	files, err := os.ReadDir(dir)
-	if err != nil {
-		log.Fatalf("reading directory failed: %v", err)
+	if generateMode {
+		start := time.Now()
+		if err := generate(db, dir, config); err != nil {
+			close()
+			log.Fatalf("generating data failed: %v", err)
+		}
+		log.Printf("Generated %d categories and %d products in %s", config.Categories, config.Products, time.Since(start).Round(time.Millisecond))
+		return
 	}
 
-	// Filter and sort .sql files
-	var sqlFiles []os.DirEntry
-	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".sql") {
-			sqlFiles = append(sqlFiles, file)
-		}
+	if err := loadDemo(db, dir); err != nil {
+		close()
+		log.Fatalf("seeding failed: %v", err)
 	}
-	sort.Slice(sqlFiles, func(i, j int) bool {
-		return sqlFiles[i].Name() < sqlFiles[j].Name()
-	})
+}
 
-	for _, file := range sqlFiles {
-		path := filepath.Join(dir, file.Name())
+// loadDemo executes the seed files in name order within a transaction
+func loadDemo(db *gorm.DB, dir fs.FS) error {
+	files, err := fs.Glob(dir, "*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
 
-		content, err := os.ReadFile(path)
-		if err != nil {
-			log.Printf("reading file %s failed: %v", file.Name(), err)
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT set_config('app.actor', ?, true)", seedActor).Error; err != nil {
+			return err
 		}
-
-		sql := string(content)
-		if err := db.Exec(sql).Error; err != nil {
-			log.Printf("executing %s failed: %v", file.Name(), err)
-			return
+		for _, file := range files {
+			content, err := fs.ReadFile(dir, file)
+			if err != nil {
+				return err
+			}
+			if err := tx.Exec(string(content)).Error; err != nil {
+				return fmt.Errorf("executing %s: %w", file, err)
+			}
+			log.Printf("Executed %s successfully\n", file)
 		}
+		return nil
+	})
+}
+
+// generate resets the data and loads a synthetic catalog within a
+// transaction. COPY needs the pgx connection under the database/sql pool.
+func generate(db *gorm.DB, dir fs.FS, config datagen.Config) error {
+	reset, err := fs.ReadFile(dir, resetFile)
+	if err != nil {
+		return err
+	}
 
-		log.Printf("Executed %s successfully\n", file.Name())
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
 	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		return pgx.BeginFunc(ctx, driverConn.(*stdlib.Conn).Conn(), func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, string(reset)); err != nil {
+				return fmt.Errorf("executing %s: %w", resetFile, err)
+			}
+			return datagen.Write(ctx, tx, datagen.New(config))
+		})
+	})
 }
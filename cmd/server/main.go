@@ -2,21 +2,36 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/mytheresa/go-hiring-challenge/app/api"
 	"github.com/mytheresa/go-hiring-challenge/app/catalog"
 	"github.com/mytheresa/go-hiring-challenge/app/categories"
 	"github.com/mytheresa/go-hiring-challenge/app/database"
+	"github.com/mytheresa/go-hiring-challenge/app/migrate"
+	"github.com/mytheresa/go-hiring-challenge/app/promotions"
+	"github.com/mytheresa/go-hiring-challenge/app/reservations"
+	"github.com/mytheresa/go-hiring-challenge/app/stock"
+	"github.com/mytheresa/go-hiring-challenge/app/taxes"
 	"github.com/mytheresa/go-hiring-challenge/models"
+	sqlmigrations "github.com/mytheresa/go-hiring-challenge/sql/migrations"
+	"gorm.io/gorm"
 )
 
 func main() {
+	// Replicas started with --migrate apply pending migrations one at a time
+	migrateOnStart := flag.Bool("migrate", false, "apply pending database migrations before serving")
+	flag.Parse()
+
 	// Load environment variables from .env file
 	if err := godotenv.Load(".env"); err != nil {
 		log.Fatalf("Error loading .env file: %s", err)
@@ -35,20 +50,104 @@ func main() {
 	)
 	defer close()
 
+	migrateSchema(db, *migrateOnStart)
+
 	// Initialize repositories
 	prodRepo := models.NewProductsRepository(db)
 	catRepo := models.NewCategoriesRepository(db)
+	currencyRepo := models.NewCurrenciesRepository(db)
+	stockRepo := models.NewStockLevelsRepository(db)
+	reservationRepo := models.NewReservationsRepository(db)
+	promotionRepo := models.NewPromotionsRepository(db)
+	taxRepo := models.NewTaxesRepository(db)
+
+	// Cursor tokens must be signed with the same secret by every replica
+	cursorSecret := os.Getenv("CURSOR_SECRET")
+	if cursorSecret == "" {
+		log.Fatalf("CURSOR_SECRET is not set")
+	}
+
+	// Reservations hold stock for RESERVATION_TTL; the sweeper releases them once expired
+	reservationTTL := durationEnv("RESERVATION_TTL", reservations.DefaultTTL)
+	sweepInterval := durationEnv("RESERVATION_SWEEP_INTERVAL", reservations.DefaultSweepInterval)
+	go reservations.RunSweeper(ctx, reservationRepo, sweepInterval)
 
 	// Initialize handlers
-	catalogHandler := catalog.NewCatalogHandler(prodRepo)
-	categoriesHandler := categories.NewCategoriesHandler(catRepo)
+	// Quotes report the VAT of DEFAULT_COUNTRY unless ?country= is given
+	catalogHandler := catalog.NewCatalogHandler(prodRepo, currencyRepo, taxRepo, api.NewCursorCodec([]byte(cursorSecret)), os.Getenv("DEFAULT_COUNTRY"))
+	categoriesHandler := categories.NewCategoriesHandler(catRepo, prodRepo, currencyRepo, taxRepo)
+	stockHandler := stock.NewStockHandler(stockRepo)
+	reservationsHandler := reservations.NewReservationsHandler(reservationRepo, reservationTTL)
+	promotionsHandler := promotions.NewPromotionsHandler(promotionRepo)
+	taxesHandler := taxes.NewTaxesHandler(taxRepo)
 
 	// Set up routing
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /catalog", catalogHandler.HandleGet)
+	mux.HandleFunc("POST /catalog", catalogHandler.HandleCreate)
+	mux.HandleFunc("GET /catalog/search", catalogHandler.HandleSearch)
 	mux.HandleFunc("GET /catalog/{code}", catalogHandler.HandleGetDetails)
+	mux.HandleFunc("PUT /catalog/{code}", catalogHandler.HandleReplace)
+	mux.HandleFunc("PATCH /catalog/{code}", catalogHandler.HandlePatch)
+	mux.HandleFunc("DELETE /catalog/{code}", catalogHandler.HandleDelete)
+	mux.HandleFunc("GET /catalog/{code}/availability", catalogHandler.HandleGetAvailability)
+	mux.HandleFunc("GET /catalog/{code}/price-history", catalogHandler.HandleGetPriceHistory)
+	mux.HandleFunc("GET /catalog/{code}/scheduled-prices", catalogHandler.HandleListScheduledPrices)
+	mux.HandleFunc("POST /catalog/{code}/scheduled-prices", catalogHandler.HandleCreateScheduledPrice)
+	mux.HandleFunc("DELETE /catalog/{code}/scheduled-prices/{id}", catalogHandler.HandleDeleteScheduledPrice)
+	mux.HandleFunc("GET /catalog/{code}/variants", catalogHandler.HandleListVariants)
+	mux.HandleFunc("POST /catalog/{code}/variants", catalogHandler.HandleCreateVariant)
+	mux.HandleFunc("GET /catalog/{code}/variants/{sku}", catalogHandler.HandleGetVariant)
+	mux.HandleFunc("PATCH /catalog/{code}/variants/{sku}", catalogHandler.HandlePatchVariant)
+	mux.HandleFunc("DELETE /catalog/{code}/variants/{sku}", catalogHandler.HandleDeleteVariant)
+	mux.HandleFunc("POST /quotes", catalogHandler.HandleQuote)
+	mux.HandleFunc("POST /imports", catalogHandler.HandleImport)
 	mux.HandleFunc("GET /categories", categoriesHandler.HandleList)
 	mux.HandleFunc("POST /categories", categoriesHandler.HandleCreate)
+	mux.HandleFunc("GET /categories/{code}", categoriesHandler.HandleGet)
+	mux.HandleFunc("PATCH /categories/{code}", categoriesHandler.HandlePatch)
+	mux.HandleFunc("DELETE /categories/{code}", categoriesHandler.HandleDelete)
+	mux.HandleFunc("GET /categories/{code}/products", categoriesHandler.HandleListProducts)
+	mux.HandleFunc("GET /categories/{code}/attributes", categoriesHandler.HandleListAttributes)
+	mux.HandleFunc("POST /categories/{code}/attributes", categoriesHandler.HandleCreateAttribute)
+	mux.HandleFunc("DELETE /categories/{code}/attributes/{attribute}", categoriesHandler.HandleDeleteAttribute)
+	mux.HandleFunc("GET /warehouses", stockHandler.HandleListWarehouses)
+	mux.HandleFunc("GET /stock/{sku}", stockHandler.HandleGet)
+	mux.HandleFunc("PUT /stock/{sku}", stockHandler.HandleSet)
+	mux.HandleFunc("POST /stock/{sku}/adjustments", stockHandler.HandleAdjust)
+	mux.HandleFunc("POST /reservations", reservationsHandler.HandleCreate)
+	mux.HandleFunc("GET /reservations/{id}", reservationsHandler.HandleGet)
+	mux.HandleFunc("POST /reservations/{id}/confirm", reservationsHandler.HandleConfirm)
+	mux.HandleFunc("POST /reservations/{id}/release", reservationsHandler.HandleRelease)
+	mux.HandleFunc("GET /promotions", promotionsHandler.HandleList)
+	mux.HandleFunc("POST /promotions", promotionsHandler.HandleCreate)
+	mux.HandleFunc("GET /promotions/{code}", promotionsHandler.HandleGet)
+	mux.HandleFunc("PUT /promotions/{code}", promotionsHandler.HandleReplace)
+	mux.HandleFunc("DELETE /promotions/{code}", promotionsHandler.HandleDelete)
+	mux.HandleFunc("GET /tax-classes", taxesHandler.HandleListClasses)
+	mux.HandleFunc("POST /tax-classes", taxesHandler.HandleCreateClass)
+	mux.HandleFunc("GET /vat-rates", taxesHandler.HandleListRates)
+	mux.HandleFunc("PUT /vat-rates/{country}/{class}", taxesHandler.HandleSetRate)
+	mux.HandleFunc("DELETE /vat-rates/{country}/{class}", taxesHandler.HandleDeleteRate)
+
+	// API v2 serves the same catalog with exact decimal string prices
+	mux.HandleFunc("GET /v2/catalog", catalog.V2(catalogHandler.HandleGet))
+	mux.HandleFunc("POST /v2/catalog", catalog.V2(catalogHandler.HandleCreate))
+	mux.HandleFunc("GET /v2/catalog/search", catalog.V2(catalogHandler.HandleSearch))
+	mux.HandleFunc("GET /v2/catalog/{code}", catalog.V2(catalogHandler.HandleGetDetails))
+	mux.HandleFunc("PUT /v2/catalog/{code}", catalog.V2(catalogHandler.HandleReplace))
+	mux.HandleFunc("PATCH /v2/catalog/{code}", catalog.V2(catalogHandler.HandlePatch))
+	mux.HandleFunc("DELETE /v2/catalog/{code}", catalog.V2(catalogHandler.HandleDelete))
+	mux.HandleFunc("GET /v2/catalog/{code}/price-history", catalog.V2(catalogHandler.HandleGetPriceHistory))
+	mux.HandleFunc("GET /v2/catalog/{code}/scheduled-prices", catalog.V2(catalogHandler.HandleListScheduledPrices))
+	mux.HandleFunc("POST /v2/catalog/{code}/scheduled-prices", catalog.V2(catalogHandler.HandleCreateScheduledPrice))
+	mux.HandleFunc("DELETE /v2/catalog/{code}/scheduled-prices/{id}", catalog.V2(catalogHandler.HandleDeleteScheduledPrice))
+	mux.HandleFunc("GET /v2/catalog/{code}/variants", catalog.V2(catalogHandler.HandleListVariants))
+	mux.HandleFunc("POST /v2/catalog/{code}/variants", catalog.V2(catalogHandler.HandleCreateVariant))
+	mux.HandleFunc("GET /v2/catalog/{code}/variants/{sku}", catalog.V2(catalogHandler.HandleGetVariant))
+	mux.HandleFunc("PATCH /v2/catalog/{code}/variants/{sku}", catalog.V2(catalogHandler.HandlePatchVariant))
+	mux.HandleFunc("DELETE /v2/catalog/{code}/variants/{sku}", catalog.V2(catalogHandler.HandleDeleteVariant))
+	mux.HandleFunc("GET /v2/categories/{code}/products", catalog.V2(categoriesHandler.HandleListProducts))
 
 	// Set up the HTTP server
 	srv := &http.Server{
@@ -71,3 +170,50 @@ func main() {
 	srv.Shutdown(ctx)
 	stop()
 }
+
+// migrateSchema applies the pending embedded migrations when apply is set.
+// The server refuses to start on a schema ahead of the binary, e.g. after a
+// rollback to an older release, or with modified migrations.
+func migrateSchema(db *gorm.DB, apply bool) {
+	migrations, err := migrate.Load(sqlmigrations.FS)
+	if err != nil {
+		log.Fatalf("Loading migrations failed: %s", err)
+	}
+	migrator := migrate.New(db, migrations)
+
+	if apply {
+		applied, err := migrator.Up(0)
+		for _, m := range applied {
+			log.Printf("Applied migration %s", m)
+		}
+		if err != nil {
+			log.Fatalf("Migrating database failed: %s", err)
+		}
+		return
+	}
+
+	pending, err := migrator.Pending()
+	if errors.Is(err, migrate.ErrUnknownMigration) {
+		log.Fatalf("Database schema is ahead of this binary: %s", err)
+	}
+	if err != nil {
+		log.Fatalf("Checking migrations failed: %s", err)
+	}
+	if len(pending) > 0 {
+		log.Printf("Database schema is %d migration(s) behind, run with --migrate to apply them", len(pending))
+	}
+}
+
+// durationEnv parses an optional duration environment variable such as "15m"
+func durationEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Fatalf("%s must be a positive duration: %q", key, value)
+	}
+	return d
+}
@@ -1,4 +1,5 @@
-// Package testutil provides shared testing utilities for integration tests.
+// Package testutil provides the database and HTTP fixtures shared by the
+// integration tests.
 // This package is internal and cannot be imported by external packages.
 package testutil
 
@@ -0,0 +1,44 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// CleanupByCode removes the rows of model identified by code before and
+// after the test, so a failed run does not break the next one
+func CleanupByCode(t *testing.T, db *gorm.DB, model any, code string) {
+	t.Helper()
+	db.Where("code = ?", code).Delete(model)
+	t.Cleanup(func() {
+		db.Where("code = ?", code).Delete(model)
+	})
+}
+
+// CleanupProduct removes a product and its variants by code before and
+// after the test
+func CleanupProduct(t *testing.T, db *gorm.DB, code string) {
+	t.Helper()
+	CleanupByCode(t, db, &models.Product{}, code)
+}
+
+// CreateProduct creates a product of the seeded SHOES category priced 10.00
+// with a single variant without stock. It is removed after the test.
+func CreateProduct(t *testing.T, db *gorm.DB, code, sku string) *models.Product {
+	t.Helper()
+	CleanupProduct(t, db, code)
+
+	product := &models.Product{
+		Code:     code,
+		Price:    decimal.RequireFromString("10.00"),
+		Category: models.Category{Code: "SHOES"},
+		Variants: []models.Variant{{Name: "One size", SKU: sku}},
+	}
+	if err := models.NewProductsRepository(db).CreateProduct(product); err != nil {
+		t.Fatalf("creating product %s: %v", code, err)
+	}
+	return product
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Attribute data types
+const (
+	AttributeText   = "text"
+	AttributeNumber = "number"
+)
+
+// maxOptionValueLength is the size of the variant_options.value column
+const maxOptionValueLength = 64
+
+// Attribute is a typed variant attribute such as size, color or material.
+// It is defined on a category and applies to the products of the category
+// and of its subcategories. Position orders the option axes of a product.
+type Attribute struct {
+	ID         uint      `gorm:"primaryKey"`
+	CategoryID uint      `gorm:"not null"`
+	Category   *Category `gorm:"foreignKey:CategoryID"`
+	Code       string    `gorm:"not null"`
+	Name       string    `gorm:"not null"`
+	DataType   string    `gorm:"not null"`
+	Position   int       `gorm:"not null"`
+}
+
+func (a *Attribute) TableName() string {
+	return "attributes"
+}
+
+// NormalizeValue returns the canonical form of an option value: trimmed
+// text, or the decimal representation of numbers so "42.0" matches "42"
+func (a *Attribute) NormalizeValue(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("%s cannot be empty", a.Code)
+	}
+	if len(value) > maxOptionValueLength {
+		return "", fmt.Errorf("%s too long: maximum %d characters", a.Code, maxOptionValueLength)
+	}
+	if a.DataType == AttributeNumber {
+		number, ok := canonicalNumber(value)
+		if !ok {
+			return "", fmt.Errorf("%s must be a number", a.Code)
+		}
+		return number, nil
+	}
+	return value, nil
+}
+
+// canonicalNumber returns the decimal representation of a number value, the
+// form number options are stored in, and reports false for other values
+func canonicalNumber(value string) (string, bool) {
+	number, err := decimal.NewFromString(strings.TrimSpace(value))
+	if err != nil {
+		return "", false
+	}
+	return number.String(), true
+}
+
+// VariantOption is the value of an attribute on a variant, e.g. color black
+type VariantOption struct {
+	VariantID   uint       `gorm:"primaryKey"`
+	AttributeID uint       `gorm:"primaryKey"`
+	Attribute   *Attribute `gorm:"foreignKey:AttributeID"`
+	Value       string     `gorm:"not null"`
+}
+
+func (o *VariantOption) TableName() string {
+	return "variant_options"
+}
+
+// VariantOptionError reports an option of a variant that does not match the
+// attributes of its product. It matches ErrInvalidVariantOption with errors.Is.
+type VariantOptionError struct {
+	SKU    string
+	Reason string
+}
+
+func (e *VariantOptionError) Error() string {
+	return fmt.Sprintf("invalid option of variant %s: %s", e.SKU, e.Reason)
+}
+
+func (e *VariantOptionError) Unwrap() error {
+	return ErrInvalidVariantOption
+}
+
+// OptionValue returns the value of the attribute code on the variant
+func (v *Variant) OptionValue(code string) (string, bool) {
+	for _, option := range v.Options {
+		if option.Attribute != nil && option.Attribute.Code == code {
+			return option.Value, true
+		}
+	}
+	return "", false
+}
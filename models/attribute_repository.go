@@ -0,0 +1,258 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// GetCategoryAttributes retrieves the attributes applying to the category
+// identified by code: its own and the ones inherited from its ancestors
+func (r *CategoriesRepository) GetCategoryAttributes(code string) ([]Attribute, error) {
+	category, err := findCategoryByCode(r.db, code)
+	if err != nil {
+		return nil, err
+	}
+	return categoryAttributes(r.db, category.ID)
+}
+
+// CreateAttribute defines an attribute on the category identified by
+// categoryCode. Its code must not be used by an attribute of an ancestor or
+// a descendant so every product sees each code at most once.
+func (r *CategoriesRepository) CreateAttribute(categoryCode string, attribute *Attribute) error {
+	if attribute == nil || strings.TrimSpace(attribute.Code) == "" || strings.TrimSpace(attribute.Name) == "" {
+		return ErrInvalidAttribute
+	}
+	if attribute.DataType != AttributeText && attribute.DataType != AttributeNumber {
+		return ErrInvalidAttribute
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		category, err := findCategoryByCode(tx, categoryCode)
+		if err != nil {
+			return err
+		}
+
+		inherited, err := categoryAttributes(tx, category.ID)
+		if err != nil {
+			return err
+		}
+		for _, a := range inherited {
+			if a.Code == attribute.Code {
+				return ErrAttributeCodeExists
+			}
+		}
+
+		var conflicts int64
+		if err := tx.Model(&Attribute{}).Where("code = ? AND category_id IN (?)", attribute.Code,
+			tx.Session(&gorm.Session{NewDB: true}).Raw(categorySubtreeSQL, []string{category.Code})).
+			Count(&conflicts).Error; err != nil {
+			return err
+		}
+		if conflicts > 0 {
+			return ErrAttributeCodeExists
+		}
+
+		attribute.ID = 0
+		attribute.CategoryID = category.ID
+		attribute.Category = category
+		if err := tx.Omit("Category").Create(attribute).Error; err != nil {
+			if _, ok := uniqueViolation(err); ok {
+				return ErrAttributeCodeExists
+			}
+			if _, ok := checkViolation(err); ok {
+				return ErrInvalidAttribute
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// DeleteAttribute removes the attribute code defined on the category
+// identified by categoryCode, together with the variant options using it
+func (r *CategoriesRepository) DeleteAttribute(categoryCode, code string) error {
+	category, err := findCategoryByCode(r.db, categoryCode)
+	if err != nil {
+		return err
+	}
+
+	result := r.db.Where("category_id = ? AND code = ?", category.ID, code).Delete(&Attribute{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAttributeNotFound
+	}
+	return nil
+}
+
+// categoryAttributes returns the attributes defined on a category and its
+// ancestors, ordered by position and code. Should a code be defined twice
+// along the branch, e.g. after a category was moved, the nearest one wins.
+func categoryAttributes(tx *gorm.DB, categoryID uint) ([]Attribute, error) {
+	ancestry, err := categoryAncestry(tx, []uint{categoryID})
+	if err != nil {
+		return nil, err
+	}
+	depth := make(map[uint]int, len(ancestry))
+	ids := make([]uint, len(ancestry))
+	for i, a := range ancestry {
+		depth[a.AncestorID] = a.Depth
+		ids[i] = a.AncestorID
+	}
+
+	var defined []Attribute
+	if err := tx.Preload("Category").Where("category_id IN ?", ids).Find(&defined).Error; err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(defined, func(i, j int) bool {
+		return depth[defined[i].CategoryID] < depth[defined[j].CategoryID]
+	})
+	seen := make(map[string]bool, len(defined))
+	attributes := make([]Attribute, 0, len(defined))
+	for _, a := range defined {
+		if !seen[a.Code] {
+			seen[a.Code] = true
+			attributes = append(attributes, a)
+		}
+	}
+
+	sort.SliceStable(attributes, func(i, j int) bool {
+		if attributes[i].Position != attributes[j].Position {
+			return attributes[i].Position < attributes[j].Position
+		}
+		return attributes[i].Code < attributes[j].Code
+	})
+	return attributes, nil
+}
+
+// attachAttributes loads the attributes of the category tree of product and
+// the options its variants hold for them
+func attachAttributes(tx *gorm.DB, product *Product) error {
+	attributes, err := categoryAttributes(tx, product.CategoryID)
+	if err != nil {
+		return err
+	}
+	product.Attributes = attributes
+
+	if err := attachVariantOptions(tx, product.Variants); err != nil {
+		return err
+	}
+
+	// Options of attributes the product no longer inherits are not shown
+	applies := make(map[uint]bool, len(attributes))
+	for _, a := range attributes {
+		applies[a.ID] = true
+	}
+	for i := range product.Variants {
+		v := &product.Variants[i]
+		options := v.Options[:0]
+		for _, option := range v.Options {
+			if applies[option.AttributeID] {
+				options = append(options, option)
+			}
+		}
+		v.Options = options
+	}
+	return nil
+}
+
+// attachVariantOptions loads the options of variants ordered by the
+// position of their attribute
+func attachVariantOptions(tx *gorm.DB, variants []Variant) error {
+	if len(variants) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(variants))
+	for i, v := range variants {
+		ids[i] = v.ID
+	}
+
+	var options []VariantOption
+	if err := tx.Joins("Attribute").Where("variant_options.variant_id IN ?", ids).
+		Order(`"Attribute".position, "Attribute".code`).Find(&options).Error; err != nil {
+		return err
+	}
+
+	byVariant := make(map[uint][]VariantOption, len(variants))
+	for _, option := range options {
+		byVariant[option.VariantID] = append(byVariant[option.VariantID], option)
+	}
+	for i := range variants {
+		variants[i].Options = byVariant[variants[i].ID]
+	}
+	return nil
+}
+
+// writeVariantOptions replaces the stored options of a written variant.
+// Options reference their attribute by code, which must be one of
+// attributes; values are stored normalized.
+func writeVariantOptions(tx *gorm.DB, attributes []Attribute, variant *Variant) error {
+	order := make(map[string]int, len(attributes))
+	for i, a := range attributes {
+		order[a.Code] = i
+	}
+
+	options := make([]VariantOption, 0, len(variant.Options))
+	seen := make(map[string]bool, len(variant.Options))
+	for _, option := range variant.Options {
+		if option.Attribute == nil {
+			return &VariantOptionError{SKU: variant.SKU, Reason: "attribute is required"}
+		}
+		code := option.Attribute.Code
+		i, ok := order[code]
+		if !ok {
+			return &VariantOptionError{SKU: variant.SKU, Reason: fmt.Sprintf("attribute %s is not defined for the product category", code)}
+		}
+		if seen[code] {
+			return &VariantOptionError{SKU: variant.SKU, Reason: fmt.Sprintf("duplicate attribute %s", code)}
+		}
+		seen[code] = true
+
+		attribute := &attributes[i]
+		value, err := attribute.NormalizeValue(option.Value)
+		if err != nil {
+			return &VariantOptionError{SKU: variant.SKU, Reason: err.Error()}
+		}
+		options = append(options, VariantOption{
+			VariantID:   variant.ID,
+			AttributeID: attribute.ID,
+			Attribute:   attribute,
+			Value:       value,
+		})
+	}
+	sort.SliceStable(options, func(i, j int) bool {
+		return order[options[i].Attribute.Code] < order[options[j].Attribute.Code]
+	})
+
+	if err := tx.Where("variant_id = ?", variant.ID).Delete(&VariantOption{}).Error; err != nil {
+		return err
+	}
+	variant.Options = options
+	if len(options) == 0 {
+		return nil
+	}
+	return tx.Omit("Attribute").Create(&options).Error
+}
+
+// writeProductOptions replaces the options of every variant of a written
+// product and attaches the attributes of its category tree
+func writeProductOptions(tx *gorm.DB, product *Product) error {
+	attributes, err := categoryAttributes(tx, product.CategoryID)
+	if err != nil {
+		return err
+	}
+	product.Attributes = attributes
+
+	for i := range product.Variants {
+		if err := writeVariantOptions(tx, attributes, &product.Variants[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttribute_NormalizeValue(t *testing.T) {
+	size := &Attribute{Code: "size", DataType: AttributeNumber}
+	color := &Attribute{Code: "color", DataType: AttributeText}
+
+	value, err := size.NormalizeValue(" 42.0 ")
+	assert.NoError(t, err)
+	assert.Equal(t, "42", value)
+
+	value, err = color.NormalizeValue(" Black ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Black", value)
+
+	_, err = size.NormalizeValue("XL")
+	assert.Error(t, err)
+	_, err = color.NormalizeValue("  ")
+	assert.Error(t, err)
+}
+
+func TestVariant_OptionValue(t *testing.T) {
+	v := &Variant{Options: []VariantOption{
+		{Attribute: &Attribute{Code: "size"}, Value: "42"},
+		{Attribute: &Attribute{Code: "color"}, Value: "black"},
+	}}
+
+	value, ok := v.OptionValue("color")
+	assert.True(t, ok)
+	assert.Equal(t, "black", value)
+
+	_, ok = v.OptionValue("material")
+	assert.False(t, ok)
+}
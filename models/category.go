@@ -2,10 +2,18 @@ package models
 
 // Category represents a product category in the catalog.
 // It includes a unique code and a human-readable name.
+// Categories can be nested through an optional parent category.
 type Category struct {
-	ID   uint   `gorm:"primaryKey"`
-	Code string `gorm:"uniqueIndex;not null"`
-	Name string `gorm:"not null"`
+	ID       uint      `gorm:"primaryKey"`
+	Code     string    `gorm:"uniqueIndex;not null"`
+	Name     string    `gorm:"not null"`
+	ParentID *uint     `gorm:"index"`
+	Parent   *Category `gorm:"foreignKey:ParentID"`
+
+	// TaxClass is inherited by the products of the category and its
+	// subcategories that have no class of their own
+	TaxClassID *uint     `gorm:"index"`
+	TaxClass   *TaxClass `gorm:"foreignKey:TaxClassID"`
 }
 
 func (c *Category) TableName() string {
@@ -12,6 +12,53 @@ import (
 type CategoryRepository interface {
 	GetAllCategories() ([]Category, error)
 	CreateCategory(category *Category) error
+	GetCategoryByCode(code string) (*Category, error)
+	UpdateCategory(code string, category *Category) error
+	DeleteCategory(code, reassignTo string) error
+	GetCategoryPath(id uint) ([]Category, error)
+	GetCategoryAttributes(code string) ([]Attribute, error)
+	CreateAttribute(categoryCode string, attribute *Attribute) error
+	DeleteAttribute(categoryCode, code string) error
+}
+
+// categorySubtreeSQL selects the ids of the categories with the given codes
+// together with the ids of all their descendants.
+const categorySubtreeSQL = `
+WITH RECURSIVE subtree AS (
+	SELECT id FROM categories WHERE code IN ?
+	UNION ALL
+	SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+)
+SELECT id FROM subtree`
+
+// categoryPathSQL selects the ancestors of a category, root first, ending
+// with the category itself.
+const categoryPathSQL = `
+WITH RECURSIVE path AS (
+	SELECT id, code, name, parent_id, 0 AS depth FROM categories WHERE id = ?
+	UNION ALL
+	SELECT c.id, c.code, c.name, c.parent_id, p.depth + 1
+	FROM categories c JOIN path p ON c.id = p.parent_id
+)
+SELECT id, code, name, parent_id FROM path ORDER BY depth DESC`
+
+// categoryAncestrySQL pairs each of the given categories with itself and
+// all of its ancestors, at their distance from the category
+const categoryAncestrySQL = `
+WITH RECURSIVE ancestry AS (
+	SELECT id AS category_id, id AS ancestor_id, parent_id, tax_class_id, 0 AS depth FROM categories WHERE id IN ?
+	UNION ALL
+	SELECT a.category_id, c.id, c.parent_id, c.tax_class_id, a.depth + 1
+	FROM categories c JOIN ancestry a ON c.id = a.parent_id
+)
+SELECT category_id, ancestor_id, tax_class_id, depth FROM ancestry`
+
+// categoryAncestor is a row of categoryAncestrySQL
+type categoryAncestor struct {
+	CategoryID uint
+	AncestorID uint
+	TaxClassID *uint
+	Depth      int
 }
 
 type CategoriesRepository struct {
@@ -22,9 +69,12 @@ func NewCategoriesRepository(db *gorm.DB) *CategoriesRepository {
 	return &CategoriesRepository{db: db}
 }
 
+// GetAllCategories retrieves all categories in a stable order: roots first,
+// then siblings by name, so listings and the tree built from them do not
+// change between requests
 func (r *CategoriesRepository) GetAllCategories() ([]Category, error) {
 	var categories []Category
-	if err := r.db.Find(&categories).Error; err != nil {
+	if err := r.db.Preload("TaxClass").Order("parent_id NULLS FIRST, name, id").Find(&categories).Error; err != nil {
 		return nil, err
 	}
 	return categories, nil
@@ -40,8 +90,18 @@ func (r *CategoriesRepository) CreateCategory(category *Category) error {
 		return ErrInvalidCategory
 	}
 
+	if err := resolveParent(r.db, category); err != nil {
+		return err
+	}
+
+	taxClass, err := resolveTaxClass(r.db, category.TaxClass)
+	if err != nil {
+		return err
+	}
+	category.TaxClass, category.TaxClassID = taxClass, taxClass.ref()
+
 	// Attempt to create
-	if err := r.db.Create(category).Error; err != nil {
+	if err := r.db.Omit("Parent", "TaxClass").Create(category).Error; err != nil {
 		// Check for PostgreSQL unique violation error (code 23505)
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -52,3 +112,171 @@ func (r *CategoriesRepository) CreateCategory(category *Category) error {
 
 	return nil
 }
+
+// GetCategoryByCode retrieves a single category by its code
+func (r *CategoriesRepository) GetCategoryByCode(code string) (*Category, error) {
+	return findCategoryByCode(r.db.Preload("Parent").Preload("TaxClass"), code)
+}
+
+// GetCategoryPath returns the breadcrumb of a category: its ancestors from the
+// root down to and including the category itself.
+func (r *CategoriesRepository) GetCategoryPath(id uint) ([]Category, error) {
+	return categoryPath(r.db, id)
+}
+
+// UpdateCategory overwrites code, name, parent and tax class of the category identified
+// by code. Moving a category below itself or one of its descendants fails
+// with ErrInvalidCategoryParent.
+func (r *CategoriesRepository) UpdateCategory(code string, category *Category) error {
+	if category == nil {
+		return ErrInvalidCategory
+	}
+
+	if strings.TrimSpace(category.Code) == "" || strings.TrimSpace(category.Name) == "" {
+		return ErrInvalidCategory
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		existing, err := findCategoryByCode(tx, code)
+		if err != nil {
+			return err
+		}
+
+		if err := resolveParent(tx, category); err != nil {
+			return err
+		}
+
+		if category.ParentID != nil {
+			var subtree []uint
+			if err := tx.Raw(categorySubtreeSQL, []string{existing.Code}).Scan(&subtree).Error; err != nil {
+				return err
+			}
+			for _, id := range subtree {
+				if id == *category.ParentID {
+					return ErrInvalidCategoryParent
+				}
+			}
+		}
+
+		taxClass, err := resolveTaxClass(tx, category.TaxClass)
+		if err != nil {
+			return err
+		}
+		category.TaxClass, category.TaxClassID = taxClass, taxClass.ref()
+
+		if err := tx.Model(&Category{}).Where("id = ?", existing.ID).Updates(map[string]any{
+			"code":         category.Code,
+			"name":         category.Name,
+			"parent_id":    category.ParentID,
+			"tax_class_id": category.TaxClassID,
+			"updated_at":   gorm.Expr("NOW()"),
+		}).Error; err != nil {
+			if _, ok := uniqueViolation(err); ok {
+				return ErrCategoryCodeExists
+			}
+			return err
+		}
+
+		category.ID = existing.ID
+		return nil
+	})
+}
+
+// DeleteCategory removes the category identified by code. Products still
+// referencing it are moved to the reassignTo category; when reassignTo is
+// empty the deletion is refused with ErrCategoryInUse. Child categories are
+// attached to the parent of the deleted category. Attributes and promotions
+// of the category would be removed by the cascading foreign keys, together
+// with the variant options of the attributes, so the deletion is refused
+// with ErrCategoryHasDependents while any exist.
+func (r *CategoriesRepository) DeleteCategory(code, reassignTo string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		category, err := findCategoryByCode(tx, code)
+		if err != nil {
+			return err
+		}
+
+		for _, model := range []any{&Attribute{}, &Promotion{}} {
+			var dependents int64
+			if err := tx.Model(model).Where("category_id = ?", category.ID).Count(&dependents).Error; err != nil {
+				return err
+			}
+			if dependents > 0 {
+				return ErrCategoryHasDependents
+			}
+		}
+
+		var inUse int64
+		if err := tx.Model(&Product{}).Where("category_id = ?", category.ID).Count(&inUse).Error; err != nil {
+			return err
+		}
+
+		if inUse > 0 {
+			if reassignTo == "" {
+				return ErrCategoryInUse
+			}
+			if reassignTo == code {
+				return ErrInvalidCategory
+			}
+
+			target, err := findCategoryByCode(tx, reassignTo)
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&Product{}).Where("category_id = ?", category.ID).
+				Update("category_id", target.ID).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&Category{}).Where("parent_id = ?", category.ID).
+			Update("parent_id", category.ParentID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&Category{}, category.ID).Error
+	})
+}
+
+// resolveParent sets ParentID from the code of category.Parent. A nil parent
+// or an empty code makes the category a root category.
+func resolveParent(tx *gorm.DB, category *Category) error {
+	if category.Parent == nil || category.Parent.Code == "" {
+		category.ParentID = nil
+		category.Parent = nil
+		return nil
+	}
+
+	parent, err := findCategoryByCode(tx, category.Parent.Code)
+	if err != nil {
+		if errors.Is(err, ErrCategoryNotFound) {
+			return ErrInvalidCategoryParent
+		}
+		return err
+	}
+
+	category.ParentID = &parent.ID
+	category.Parent = parent
+	return nil
+}
+
+// categoryPath runs categoryPathSQL for the category with the given id
+func categoryPath(tx *gorm.DB, id uint) ([]Category, error) {
+	var path []Category
+	if err := tx.Raw(categoryPathSQL, id).Scan(&path).Error; err != nil {
+		return nil, err
+	}
+	return path, nil
+}
+
+// categoryAncestry returns the ancestry rows of the given categories
+func categoryAncestry(tx *gorm.DB, categoryIDs []uint) ([]categoryAncestor, error) {
+	var ancestry []categoryAncestor
+	if len(categoryIDs) == 0 {
+		return ancestry, nil
+	}
+	if err := tx.Raw(categoryAncestrySQL, categoryIDs).Scan(&ancestry).Error; err != nil {
+		return nil, err
+	}
+	return ancestry, nil
+}
@@ -26,6 +26,37 @@ func TestGetAllCategories(t *testing.T) {
 		assert.True(t, codes["SHOES"], "Should have SHOES category")
 		assert.True(t, codes["ACCESSORIES"], "Should have ACCESSORIES category")
 	})
+
+	t.Run("orders roots first, then siblings by name", func(t *testing.T) {
+		// Created in reverse name order so heap order would differ
+		for _, code := range []string{"TEST_ORDER_B", "TEST_ORDER_A"} {
+			db.Where("code = ?", code).Delete(&Category{})
+			cleanupCategory(t, db, code)
+		}
+		assert.NoError(t, repo.CreateCategory(&Category{Code: "TEST_ORDER_B", Name: "Zz test order", Parent: &Category{Code: "SHOES"}}))
+		assert.NoError(t, repo.CreateCategory(&Category{Code: "TEST_ORDER_A", Name: "Aa test order", Parent: &Category{Code: "SHOES"}}))
+
+		categories, err := repo.GetAllCategories()
+		assert.NoError(t, err)
+
+		var roots, children []string
+		seenChild := false
+		for _, cat := range categories {
+			if cat.ParentID == nil {
+				assert.False(t, seenChild, "root %s listed after a child", cat.Code)
+				if cat.Code == "ACCESSORIES" || cat.Code == "CLOTHING" || cat.Code == "SHOES" {
+					roots = append(roots, cat.Code)
+				}
+				continue
+			}
+			seenChild = true
+			if cat.Code == "TEST_ORDER_A" || cat.Code == "TEST_ORDER_B" {
+				children = append(children, cat.Code)
+			}
+		}
+		assert.Equal(t, []string{"ACCESSORIES", "CLOTHING", "SHOES"}, roots)
+		assert.Equal(t, []string{"TEST_ORDER_A", "TEST_ORDER_B"}, children)
+	})
 }
 
 func TestCreateCategory(t *testing.T) {
@@ -93,3 +124,207 @@ func TestCreateCategory(t *testing.T) {
 		assert.ErrorIs(t, err, ErrInvalidCategory)
 	})
 }
+
+func TestGetCategoryByCode(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCategoriesRepository(db)
+
+	t.Run("returns category", func(t *testing.T) {
+		category, err := repo.GetCategoryByCode("SHOES")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Shoes", category.Name)
+	})
+
+	t.Run("returns ErrCategoryNotFound for unknown code", func(t *testing.T) {
+		category, err := repo.GetCategoryByCode("NONEXISTENT")
+
+		assert.ErrorIs(t, err, ErrCategoryNotFound)
+		assert.Nil(t, category)
+	})
+}
+
+func TestUpdateCategory(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCategoriesRepository(db)
+
+	t.Run("renames category", func(t *testing.T) {
+		db.Where("code IN ?", []string{"TEST_UPDATE", "TEST_UPDATED"}).Delete(&Category{})
+		cleanupCategory(t, db, "TEST_UPDATE")
+		cleanupCategory(t, db, "TEST_UPDATED")
+		assert.NoError(t, repo.CreateCategory(&Category{Code: "TEST_UPDATE", Name: "Update"}))
+
+		err := repo.UpdateCategory("TEST_UPDATE", &Category{Code: "TEST_UPDATED", Name: "Updated"})
+
+		assert.NoError(t, err)
+		found, err := repo.GetCategoryByCode("TEST_UPDATED")
+		assert.NoError(t, err)
+		assert.Equal(t, "Updated", found.Name)
+	})
+
+	t.Run("returns ErrCategoryCodeExists for duplicate code", func(t *testing.T) {
+		err := repo.UpdateCategory("SHOES", &Category{Code: "CLOTHING", Name: "Shoes"})
+
+		assert.ErrorIs(t, err, ErrCategoryCodeExists)
+	})
+
+	t.Run("returns ErrCategoryNotFound for unknown code", func(t *testing.T) {
+		err := repo.UpdateCategory("NONEXISTENT", &Category{Code: "NONEXISTENT", Name: "X"})
+
+		assert.ErrorIs(t, err, ErrCategoryNotFound)
+	})
+}
+
+func TestDeleteCategory(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCategoriesRepository(db)
+	products := NewProductsRepository(db)
+
+	t.Run("refuses to delete a category in use", func(t *testing.T) {
+		err := repo.DeleteCategory("SHOES", "")
+
+		assert.ErrorIs(t, err, ErrCategoryInUse)
+	})
+
+	t.Run("reassigns products before deleting", func(t *testing.T) {
+		db.Where("code = ?", "TEST_DELETE_CAT").Delete(&Product{})
+		db.Where("code = ?", "TEST_DELETE").Delete(&Category{})
+		cleanupProduct(t, db, "TEST_DELETE_CAT")
+		cleanupCategory(t, db, "TEST_DELETE")
+		assert.NoError(t, repo.CreateCategory(&Category{Code: "TEST_DELETE", Name: "Delete"}))
+		assert.NoError(t, products.CreateProduct(&Product{
+			Code:     "TEST_DELETE_CAT",
+			Price:    mustDecimal("1.00"),
+			Category: Category{Code: "TEST_DELETE"},
+		}))
+
+		err := repo.DeleteCategory("TEST_DELETE", "SHOES")
+
+		assert.NoError(t, err)
+		product, err := products.GetProductByCode("TEST_DELETE_CAT")
+		assert.NoError(t, err)
+		assert.Equal(t, "SHOES", product.Category.Code)
+	})
+
+	t.Run("keeps attributes, options and promotions of the category", func(t *testing.T) {
+		db.Where("code = ?", "TEST_DELETE_DEP").Delete(&Product{})
+		db.Where("code = ?", "TEST_DELETE_DEP").Delete(&Promotion{})
+		db.Where("code = ?", "TEST_DELETE_DEP").Delete(&Category{})
+		cleanupCategory(t, db, "TEST_DELETE_DEP")
+		cleanupProduct(t, db, "TEST_DELETE_DEP")
+		assert.NoError(t, repo.CreateCategory(&Category{Code: "TEST_DELETE_DEP", Name: "Delete"}))
+		size := &Attribute{Code: "size", Name: "Size", DataType: AttributeText, Position: 1}
+		assert.NoError(t, repo.CreateAttribute("TEST_DELETE_DEP", size))
+		assert.NoError(t, products.CreateProduct(&Product{
+			Code:     "TEST_DELETE_DEP",
+			Price:    mustDecimal("1.00"),
+			Category: Category{Code: "TEST_DELETE_DEP"},
+			Variants: []Variant{{Name: "Medium", SKU: "TEST_DELETE_DEP_M", Options: []VariantOption{
+				{Attribute: &Attribute{Code: "size"}, Value: "M"},
+			}}},
+		}))
+
+		err := repo.DeleteCategory("TEST_DELETE_DEP", "SHOES")
+
+		assert.ErrorIs(t, err, ErrCategoryHasDependents)
+		product, err := products.GetProductByCode("TEST_DELETE_DEP")
+		if assert.NoError(t, err) && assert.Len(t, product.Variants, 1) {
+			assert.Equal(t, "TEST_DELETE_DEP", product.Category.Code)
+			value, ok := product.Variants[0].OptionValue("size")
+			assert.True(t, ok)
+			assert.Equal(t, "M", value)
+		}
+
+		assert.NoError(t, repo.DeleteAttribute("TEST_DELETE_DEP", "size"))
+		promotions := NewPromotionsRepository(db)
+		assert.NoError(t, promotions.CreatePromotion(&Promotion{
+			Code:         "TEST_DELETE_DEP",
+			Name:         "Delete",
+			DiscountType: DiscountPercentage,
+			Value:        mustDecimal("10"),
+			Category:     &Category{Code: "TEST_DELETE_DEP"},
+		}))
+
+		err = repo.DeleteCategory("TEST_DELETE_DEP", "SHOES")
+
+		assert.ErrorIs(t, err, ErrCategoryHasDependents)
+		_, err = promotions.GetPromotionByCode("TEST_DELETE_DEP")
+		assert.NoError(t, err)
+	})
+
+	t.Run("returns ErrCategoryNotFound for unknown code", func(t *testing.T) {
+		err := repo.DeleteCategory("NONEXISTENT", "")
+
+		assert.ErrorIs(t, err, ErrCategoryNotFound)
+	})
+}
+
+func TestCategoryHierarchy(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCategoriesRepository(db)
+	products := NewProductsRepository(db)
+
+	db.Where("code = ?", "TEST_HIER_PROD").Delete(&Product{})
+	db.Where("code = ?", "TEST_HIER_LEAF").Delete(&Category{})
+	db.Where("code = ?", "TEST_HIER_ROOT").Delete(&Category{})
+	cleanupCategory(t, db, "TEST_HIER_ROOT")
+	cleanupCategory(t, db, "TEST_HIER_LEAF")
+	cleanupProduct(t, db, "TEST_HIER_PROD")
+
+	root := &Category{Code: "TEST_HIER_ROOT", Name: "Root"}
+	leaf := &Category{Code: "TEST_HIER_LEAF", Name: "Leaf", Parent: &Category{Code: "TEST_HIER_ROOT"}}
+	assert.NoError(t, repo.CreateCategory(root))
+	assert.NoError(t, repo.CreateCategory(leaf))
+	assert.NoError(t, products.CreateProduct(&Product{
+		Code:     "TEST_HIER_PROD",
+		Price:    mustDecimal("1.00"),
+		Category: Category{Code: "TEST_HIER_LEAF"},
+	}))
+
+	t.Run("stores parent id", func(t *testing.T) {
+		if assert.NotNil(t, leaf.ParentID) {
+			assert.Equal(t, root.ID, *leaf.ParentID)
+		}
+	})
+
+	t.Run("returns path from root to category", func(t *testing.T) {
+		path, err := repo.GetCategoryPath(leaf.ID)
+
+		assert.NoError(t, err)
+		if assert.Len(t, path, 2) {
+			assert.Equal(t, "TEST_HIER_ROOT", path[0].Code)
+			assert.Equal(t, "TEST_HIER_LEAF", path[1].Code)
+		}
+	})
+
+	t.Run("filters products of the whole subtree", func(t *testing.T) {
+		direct, _, err := products.GetProductsWithFilters(ProductFilters{Limit: 10, CategoryCode: "TEST_HIER_ROOT"})
+		assert.NoError(t, err)
+		assert.Empty(t, direct)
+
+		subtree, total, err := products.GetProductsWithFilters(ProductFilters{
+			Limit:                10,
+			CategoryCode:         "TEST_HIER_ROOT",
+			IncludeSubcategories: true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, "TEST_HIER_PROD", subtree[0].Code)
+	})
+
+	t.Run("rejects cycles", func(t *testing.T) {
+		err := repo.UpdateCategory("TEST_HIER_ROOT", &Category{
+			Code:   "TEST_HIER_ROOT",
+			Name:   "Root",
+			Parent: &Category{Code: "TEST_HIER_LEAF"},
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidCategoryParent)
+	})
+
+	t.Run("returns ErrInvalidCategoryParent for unknown parent", func(t *testing.T) {
+		err := repo.CreateCategory(&Category{Code: "TEST_HIER_X", Name: "X", Parent: &Category{Code: "NONEXISTENT"}})
+
+		assert.ErrorIs(t, err, ErrInvalidCategoryParent)
+	})
+}
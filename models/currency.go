@@ -0,0 +1,119 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BaseCurrency is the currency product and variant prices are stored in
+const BaseCurrency = "EUR"
+
+// Rounding modes applied to prices converted from the base currency
+const (
+	RoundingHalfUp   = "half_up"
+	RoundingHalfEven = "half_even"
+	RoundingUp       = "up"
+	RoundingDown     = "down"
+)
+
+// Currency represents a currency prices can be served in.
+// Converted prices are rounded to a multiple of RoundingIncrement using RoundingMode.
+type Currency struct {
+	Code              string          `gorm:"primaryKey"`
+	RoundingIncrement decimal.Decimal `gorm:"type:decimal(10,2);not null"`
+	RoundingMode      string          `gorm:"not null"`
+	ExchangeRate      *ExchangeRate   `gorm:"foreignKey:CurrencyCode;references:Code"`
+}
+
+func (c *Currency) TableName() string {
+	return "currencies"
+}
+
+// ExchangeRate converts base currency amounts: amount = base amount * Rate
+type ExchangeRate struct {
+	CurrencyCode string          `gorm:"primaryKey"`
+	Rate         decimal.Decimal `gorm:"type:decimal(18,8);not null"`
+	UpdatedAt    time.Time
+}
+
+func (e *ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
+
+// ProductPrice is an explicit price of a product in a non-base currency
+type ProductPrice struct {
+	ProductID    uint            `gorm:"primaryKey"`
+	CurrencyCode string          `gorm:"primaryKey"`
+	Price        decimal.Decimal `gorm:"type:decimal(10,2);not null"`
+}
+
+func (p *ProductPrice) TableName() string {
+	return "product_prices"
+}
+
+// VariantPrice is an explicit price of a variant in a non-base currency
+type VariantPrice struct {
+	VariantID    uint            `gorm:"primaryKey"`
+	CurrencyCode string          `gorm:"primaryKey"`
+	Price        decimal.Decimal `gorm:"type:decimal(10,2);not null"`
+}
+
+func (p *VariantPrice) TableName() string {
+	return "variant_prices"
+}
+
+// Convert converts a base currency amount and rounds it with the currency rules
+func (c *Currency) Convert(amount decimal.Decimal) decimal.Decimal {
+	if c.Code == BaseCurrency || c.ExchangeRate == nil {
+		return amount
+	}
+	return c.Round(amount.Mul(c.ExchangeRate.Rate))
+}
+
+// Round rounds amount to a multiple of the rounding increment
+func (c *Currency) Round(amount decimal.Decimal) decimal.Decimal {
+	increment := c.RoundingIncrement
+	if !increment.IsPositive() {
+		increment = decimal.New(1, -2)
+	}
+
+	steps := amount.Div(increment)
+	switch c.RoundingMode {
+	case RoundingHalfEven:
+		steps = steps.RoundBank(0)
+	case RoundingUp:
+		steps = steps.Ceil()
+	case RoundingDown:
+		steps = steps.Floor()
+	default:
+		steps = steps.Round(0)
+	}
+	return steps.Mul(increment)
+}
+
+// ProductPrice returns the price of product in this currency: the explicit
+// override when there is one, the converted base price otherwise.
+func (c *Currency) ProductPrice(product *Product) decimal.Decimal {
+	for _, p := range product.Prices {
+		if p.CurrencyCode == c.Code {
+			return p.Price
+		}
+	}
+	return c.Convert(product.Price)
+}
+
+// VariantPrice returns the price of a variant of product in this currency.
+// An explicit variant override wins; a variant with its own base price is
+// converted; otherwise the variant inherits the product price in this currency.
+func (c *Currency) VariantPrice(product *Product, v *Variant) decimal.Decimal {
+	for _, p := range v.Prices {
+		if p.CurrencyCode == c.Code {
+			return p.Price
+		}
+	}
+	if !v.Price.Valid || v.Price.Decimal.IsZero() {
+		return c.ProductPrice(product)
+	}
+	return c.Convert(v.Price.Decimal)
+}
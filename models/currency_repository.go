@@ -0,0 +1,36 @@
+package models
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// CurrencyRepository defines the interface for currency data access
+type CurrencyRepository interface {
+	GetCurrency(code string) (*Currency, error)
+}
+
+type CurrenciesRepository struct {
+	db *gorm.DB
+}
+
+func NewCurrenciesRepository(db *gorm.DB) *CurrenciesRepository {
+	return &CurrenciesRepository{
+		db: db,
+	}
+}
+
+// GetCurrency retrieves a currency with its exchange rate. Currencies
+// without an exchange rate cannot be served and are reported as not found.
+func (r *CurrenciesRepository) GetCurrency(code string) (*Currency, error) {
+	var currency Currency
+	if err := r.db.InnerJoins("ExchangeRate").
+		Where("currencies.code = ?", code).First(&currency).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCurrencyNotFound
+		}
+		return nil, err
+	}
+	return &currency, nil
+}
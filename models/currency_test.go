@@ -0,0 +1,76 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrency_Convert(t *testing.T) {
+	cases := []struct {
+		name      string
+		increment string
+		mode      string
+		amount    string
+		expected  string
+	}{
+		{"half up to cents", "0.01", RoundingHalfUp, "10.05", "8.54"},
+		{"half even to cents", "0.01", RoundingHalfEven, "10.10", "8.58"},
+		{"up to five cents", "0.05", RoundingUp, "10.00", "8.50"},
+		{"up to whole units", "1", RoundingUp, "10.05", "9"},
+		{"down to whole units", "1", RoundingDown, "10.05", "8"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			currency := &Currency{
+				Code:              "GBP",
+				RoundingIncrement: mustDecimal(tc.increment),
+				RoundingMode:      tc.mode,
+				ExchangeRate:      &ExchangeRate{CurrencyCode: "GBP", Rate: mustDecimal("0.85")},
+			}
+
+			converted := currency.Convert(mustDecimal(tc.amount))
+
+			assert.True(t, mustDecimal(tc.expected).Equal(converted), "got %s", converted)
+		})
+	}
+
+	t.Run("base currency is not converted", func(t *testing.T) {
+		currency := &Currency{Code: BaseCurrency}
+
+		assert.Equal(t, "10.05", currency.Convert(mustDecimal("10.05")).String())
+	})
+}
+
+func TestCurrency_VariantPrice(t *testing.T) {
+	currency := &Currency{
+		Code:              "GBP",
+		RoundingIncrement: mustDecimal("0.01"),
+		RoundingMode:      RoundingHalfUp,
+		ExchangeRate:      &ExchangeRate{CurrencyCode: "GBP", Rate: mustDecimal("0.5")},
+	}
+	product := &Product{
+		Price:  mustDecimal("20.00"),
+		Prices: []ProductPrice{{CurrencyCode: "GBP", Price: mustDecimal("9.99")}},
+	}
+
+	t.Run("variant override wins", func(t *testing.T) {
+		v := &Variant{Prices: []VariantPrice{{CurrencyCode: "GBP", Price: mustDecimal("7.49")}}}
+
+		assert.Equal(t, "7.49", currency.VariantPrice(product, v).String())
+	})
+
+	t.Run("variant base price is converted", func(t *testing.T) {
+		v := &Variant{Price: decimal.NullDecimal{Decimal: mustDecimal("30.00"), Valid: true}}
+
+		assert.Equal(t, "15", currency.VariantPrice(product, v).String())
+	})
+
+	t.Run("variant without price inherits the product override", func(t *testing.T) {
+		v := &Variant{}
+
+		assert.Equal(t, "9.99", currency.VariantPrice(product, v).String())
+	})
+}
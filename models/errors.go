@@ -1,18 +1,115 @@
 package models
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
 
 // Domain errors for the models package
 var (
 	// Product errors
-	ErrProductNotFound = errors.New("product not found")
-	ErrInvalidProduct  = errors.New("invalid product data")
+	ErrProductNotFound   = errors.New("product not found")
+	ErrProductCodeExists = errors.New("product code already exists")
+	ErrInvalidProduct    = errors.New("invalid product data")
+
+	// Variant errors
+	ErrVariantNotFound  = errors.New("variant not found")
+	ErrVariantSKUExists = errors.New("variant SKU already exists")
+	ErrInvalidVariant   = errors.New("invalid variant data")
+	ErrVariantReserved  = errors.New("variant is held by pending reservations")
+
+	// Attribute errors
+	ErrAttributeNotFound    = errors.New("attribute not found")
+	ErrAttributeCodeExists  = errors.New("attribute code already exists in the category tree")
+	ErrInvalidAttribute     = errors.New("invalid attribute data")
+	ErrInvalidVariantOption = errors.New("invalid variant option")
 
 	// Category errors
-	ErrCategoryNotFound    = errors.New("category not found")
-	ErrCategoryCodeExists  = errors.New("category code already exists")
-	ErrInvalidCategory     = errors.New("invalid category data")
+	ErrCategoryNotFound      = errors.New("category not found")
+	ErrCategoryCodeExists    = errors.New("category code already exists")
+	ErrInvalidCategory       = errors.New("invalid category data")
+	ErrCategoryInUse         = errors.New("category is referenced by products")
+	ErrCategoryHasDependents = errors.New("category is referenced by attributes or promotions")
+	ErrInvalidCategoryParent = errors.New("invalid parent category")
+
+	// Scheduled price errors
+	ErrScheduledPriceNotFound = errors.New("scheduled price not found")
+	ErrInvalidScheduledPrice  = errors.New("invalid scheduled price")
+
+	// Promotion errors
+	ErrPromotionNotFound   = errors.New("promotion not found")
+	ErrPromotionCodeExists = errors.New("promotion code already exists")
+	ErrInvalidPromotion    = errors.New("invalid promotion data")
+
+	// Tax errors
+	ErrTaxClassNotFound    = errors.New("tax class not found")
+	ErrTaxClassCodeExists  = errors.New("tax class code already exists")
+	ErrInvalidTaxClass     = errors.New("invalid tax class data")
+	ErrVATRateNotFound     = errors.New("VAT rate not found")
+	ErrInvalidVATRate      = errors.New("invalid VAT rate")
+	ErrCountryNotSupported = errors.New("country not supported")
+
+	// Stock errors
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrWarehouseNotFound = errors.New("warehouse not found")
+
+	// Reservation errors
+	ErrReservationNotFound   = errors.New("reservation not found")
+	ErrReservationNotPending = errors.New("reservation is no longer pending")
+	ErrReservationExpired    = errors.New("reservation has expired")
+	ErrInvalidReservation    = errors.New("invalid reservation data")
+	ErrReservedStockMissing  = errors.New("reserved stock no longer exists")
+
+	// Currency errors
+	ErrCurrencyNotFound = errors.New("currency not found")
 
 	// Validation errors
 	ErrInvalidPagination = errors.New("invalid pagination parameters")
+	ErrInvalidSort       = errors.New("invalid sort parameter")
+	ErrInvalidCursor     = errors.New("invalid pagination cursor")
 )
+
+// StockShortageError reports the SKU that could not be reserved.
+// It matches ErrInsufficientStock with errors.Is.
+type StockShortageError struct {
+	SKU string
+}
+
+func (e *StockShortageError) Error() string {
+	return "insufficient stock: " + e.SKU
+}
+
+func (e *StockShortageError) Unwrap() error {
+	return ErrInsufficientStock
+}
+
+// uniqueViolation reports whether err is a PostgreSQL unique violation
+// (code 23505) and returns the name of the violated constraint.
+func uniqueViolation(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return pgErr.ConstraintName, true
+	}
+	return "", false
+}
+
+// checkViolation reports whether err is a PostgreSQL check constraint
+// violation (code 23514) and returns the name of the violated constraint.
+func checkViolation(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23514" {
+		return pgErr.ConstraintName, true
+	}
+	return "", false
+}
+
+// foreignKeyViolation reports whether err is a PostgreSQL foreign key
+// violation (code 23503) and returns the name of the violated constraint.
+func foreignKeyViolation(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return pgErr.ConstraintName, true
+	}
+	return "", false
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PriceChange records a change of the regular price of a product, or of one
+// of its variants when VariantID is set. Rows are written by database
+// triggers. A NULL old price marks the initial price; a NULL variant price
+// means the variant inherits the product price.
+//
+// Changes outlive their product: ProductCode is the code at the time of the
+// change and ProductID is cleared when the product is deleted.
+type PriceChange struct {
+	ID          uint                `gorm:"primaryKey"`
+	ProductID   *uint               `gorm:"index"`
+	ProductCode string              `gorm:"not null"`
+	VariantID   *uint               `gorm:"index"`
+	SKU         *string             `gorm:"column:sku"`
+	OldPrice    decimal.NullDecimal `gorm:"type:decimal(10,2)"`
+	NewPrice    decimal.NullDecimal `gorm:"type:decimal(10,2)"`
+	Actor       string              `gorm:"not null"`
+	ChangedAt   time.Time           `gorm:"not null"`
+}
+
+func (c *PriceChange) TableName() string {
+	return "price_changes"
+}
+
+// PriceHistoryFilters restricts the price history to changes made at or
+// after From and before To. Zero values leave the range open.
+type PriceHistoryFilters struct {
+	From time.Time
+	To   time.Time
+}
@@ -0,0 +1,174 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// Cursor identifies a row of a sorted product listing by the value of its
+// sort column and its id, which together are unique. Filters fingerprints
+// the filters of the listing so the cursor is not reused with other ones.
+type Cursor struct {
+	Sort    string `json:"s"`
+	Filters string `json:"f"`
+	Value   string `json:"v"`
+	ID      uint   `json:"id"`
+}
+
+// ProductPage is a page of a keyset paginated product listing
+type ProductPage struct {
+	Products []Product
+	Total    int64
+
+	// Next points at the last product of the page; nil on the last page
+	Next *Cursor
+	// Prev points at the first product of the page; nil on the first page
+	Prev *Cursor
+}
+
+// GetProductsPage retrieves products with filtering and keyset pagination.
+// Rows strictly after filters.After or strictly before filters.Before are
+// returned; Offset is ignored. Unlike offset pagination, pages stay stable
+// when products are inserted between requests.
+func (r *ProductsRepository) GetProductsPage(filters ProductFilters) (*ProductPage, error) {
+	if filters.Limit <= 0 {
+		return nil, ErrInvalidPagination
+	}
+	if filters.After != nil && filters.Before != nil {
+		return nil, ErrInvalidCursor
+	}
+	// Relevance ranks are floats recomputed per query and cannot be used as keys
+	if filters.rankedBySearch() {
+		return nil, ErrInvalidCursor
+	}
+
+	column, desc, err := filters.sortColumn()
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, backward := filters.After, false
+	if filters.Before != nil {
+		cursor, backward = filters.Before, true
+	}
+	fingerprint := filters.fingerprint()
+	if cursor != nil && (cursor.Sort != filters.Sort || cursor.Filters != fingerprint) {
+		return nil, ErrInvalidCursor
+	}
+
+	page := &ProductPage{}
+	query := applyProductFilters(r.db.Model(&Product{}), filters)
+
+	// Count total with filters, independent of the page position
+	if err := query.Count(&page.Total).Error; err != nil {
+		return nil, err
+	}
+
+	// Walking backwards reverses both the comparison and the order
+	reverse := desc != backward
+	if cursor != nil {
+		operator := ">"
+		if reverse {
+			operator = "<"
+		}
+		query = query.Where("("+column+", products.id) "+operator+" (?, ?)", cursor.Value, cursor.ID)
+	}
+
+	// The page is selected together with the sort values of its rows, so the
+	// cursors hold the values the rows were ordered by even when they change
+	// concurrently. One extra row tells whether another page follows.
+	var keys []struct {
+		ID    uint
+		Value string
+	}
+	if err := query.Select("products.id AS id, (" + column + ")::text AS value").
+		Order(orderByExpr(orderSQL(column, reverse))).
+		Limit(filters.Limit + 1).
+		Scan(&keys).Error; err != nil {
+		return nil, err
+	}
+
+	hasMore := len(keys) > filters.Limit
+	if hasMore {
+		keys = keys[:filters.Limit]
+	}
+	if backward {
+		slices.Reverse(keys)
+	}
+
+	page.Products = make([]Product, 0, len(keys))
+	if len(keys) == 0 {
+		return page, nil
+	}
+
+	ids := make([]uint, len(keys))
+	for i, key := range keys {
+		ids[i] = key.ID
+	}
+	var products []Product
+	if err := r.db.Where("products.id IN ?", ids).
+		Preload("Category").Preload("Variants.Prices").Preload("Variants.Stock").Preload("Prices").
+		Preload("ScheduledPrices", activeScheduledPricesSQL).
+		Find(&products).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]Product, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+	// Products deleted since the page was selected are left out
+	for _, id := range ids {
+		if product, ok := byID[id]; ok {
+			page.Products = append(page.Products, product)
+		}
+	}
+	if err := attachPricingRules(r.db, page.Products); err != nil {
+		return nil, err
+	}
+
+	hasNext, hasPrev := hasMore, cursor != nil
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	first, last := keys[0], keys[len(keys)-1]
+	if hasNext {
+		page.Next = &Cursor{Sort: filters.Sort, Filters: fingerprint, Value: last.Value, ID: last.ID}
+	}
+	if hasPrev {
+		page.Prev = &Cursor{Sort: filters.Sort, Filters: fingerprint, Value: first.Value, ID: first.ID}
+	}
+
+	return page, nil
+}
+
+// fingerprint identifies the rows matched by the filters, regardless of the
+// pagination fields and of the order of repeated values
+func (f ProductFilters) fingerprint() string {
+	key := f
+	key.Offset, key.Limit, key.Sort, key.After, key.Before = 0, 0, "", nil, nil
+	key.CategoryCodes = slices.Sorted(slices.Values(f.categoryCodes()))
+	key.CategoryCode = ""
+	if key.PriceMode == "" {
+		key.PriceMode = PriceModeProduct
+	}
+	if f.Attributes != nil {
+		key.Attributes = make(map[string][]string, len(f.Attributes))
+		for code, values := range f.Attributes {
+			normalized := make([]string, len(values))
+			for i, value := range values {
+				normalized[i] = strings.ToLower(value)
+			}
+			slices.Sort(normalized)
+			key.Attributes[code] = normalized
+		}
+	}
+
+	// Filters hold only strings, numbers and flags, which always marshal
+	data, _ := json.Marshal(key)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
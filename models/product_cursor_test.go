@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductFilters_Fingerprint(t *testing.T) {
+	base := ProductFilters{
+		CategoryCodes: []string{"SHOES", "BAGS"},
+		Attributes:    map[string][]string{"color": {"Black", "white"}},
+	}
+
+	t.Run("ignores pagination and the order of values", func(t *testing.T) {
+		same := ProductFilters{
+			Offset:        10,
+			Limit:         5,
+			Sort:          "-price",
+			After:         &Cursor{ID: 1},
+			CategoryCode:  "BAGS",
+			CategoryCodes: []string{"SHOES"},
+			PriceMode:     PriceModeProduct,
+			Attributes:    map[string][]string{"color": {"WHITE", "black"}},
+		}
+
+		assert.Equal(t, base.fingerprint(), same.fingerprint())
+	})
+
+	t.Run("changes with the filters", func(t *testing.T) {
+		inStock := true
+		for name, other := range map[string]ProductFilters{
+			"category":  {CategoryCodes: []string{"SHOES"}, Attributes: base.Attributes},
+			"attribute": {CategoryCodes: base.CategoryCodes, Attributes: map[string][]string{"color": {"black"}}},
+			"in stock":  {CategoryCodes: base.CategoryCodes, Attributes: base.Attributes, InStock: &inStock},
+			"query":     {CategoryCodes: base.CategoryCodes, Attributes: base.Attributes, Query: "boot"},
+		} {
+			assert.NotEqual(t, base.fingerprint(), other.fingerprint(), name)
+		}
+	})
+}
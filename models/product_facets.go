@@ -0,0 +1,161 @@
+package models
+
+import (
+	"math"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// maxPriceBuckets bounds the number of buckets of the price histogram
+const maxPriceBuckets = 10
+
+// FacetRequest selects the facets computed for a product listing
+type FacetRequest struct {
+	Categories bool
+	Price      bool
+
+	// Attributes lists the attribute codes to count option values for
+	Attributes []string
+}
+
+// Empty reports whether no facet is requested
+func (f FacetRequest) Empty() bool {
+	return !f.Categories && !f.Price && len(f.Attributes) == 0
+}
+
+// Facets holds the aggregations of the products matching a filter set
+type Facets struct {
+	Categories []CategoryFacet
+	Price      []PriceBucket
+
+	// Attributes maps the requested attribute codes to their value counts
+	Attributes map[string][]AttributeValueFacet
+}
+
+// CategoryFacet counts the matching products of a category
+type CategoryFacet struct {
+	Code  string
+	Name  string
+	Count int64
+}
+
+// PriceBucket counts the matching products priced in [From, To), in the
+// base currency like the price filters
+type PriceBucket struct {
+	From  decimal.Decimal
+	To    decimal.Decimal
+	Count int64
+}
+
+// AttributeValueFacet counts the matching products having a variant with
+// an option value
+type AttributeValueFacet struct {
+	Value string
+	Count int64
+}
+
+// GetProductFacets aggregates the products matching filters. Pagination,
+// sorting and cursors of filters are ignored.
+func (r *ProductsRepository) GetProductFacets(filters ProductFilters, request FacetRequest) (*Facets, error) {
+	facets := &Facets{}
+	matching := func() *gorm.DB {
+		return applyProductFilters(r.db.Model(&Product{}), filters)
+	}
+
+	if request.Categories {
+		if err := matching().
+			Select("c.code, c.name, COUNT(*) AS count").
+			Joins("JOIN categories c ON c.id = products.category_id").
+			Group("c.code, c.name").
+			Order("count DESC, c.code").
+			Scan(&facets.Categories).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if request.Price {
+		buckets, err := priceHistogram(matching)
+		if err != nil {
+			return nil, err
+		}
+		facets.Price = buckets
+	}
+
+	if len(request.Attributes) > 0 {
+		facets.Attributes = make(map[string][]AttributeValueFacet, len(request.Attributes))
+	}
+	for _, code := range request.Attributes {
+		values := []AttributeValueFacet{}
+		if err := matching().
+			Select("fo.value, COUNT(DISTINCT products.id) AS count").
+			Joins("JOIN product_variants fv ON fv.product_id = products.id").
+			Joins("JOIN variant_options fo ON fo.variant_id = fv.id").
+			Joins("JOIN attributes fa ON fa.id = fo.attribute_id").
+			Where("fa.code = ?", code).
+			Group("fo.value").
+			Order("count DESC, fo.value").
+			Scan(&values).Error; err != nil {
+			return nil, err
+		}
+		facets.Attributes[code] = values
+	}
+
+	return facets, nil
+}
+
+// priceHistogram counts the matching products per price bucket. Buckets
+// share a width chosen from the price range; empty buckets are left out.
+func priceHistogram(matching func() *gorm.DB) ([]PriceBucket, error) {
+	var bounds struct {
+		Min decimal.NullDecimal
+		Max decimal.NullDecimal
+	}
+	if err := matching().Select("MIN(products.price) AS min, MAX(products.price) AS max").
+		Scan(&bounds).Error; err != nil {
+		return nil, err
+	}
+	if !bounds.Min.Valid {
+		return []PriceBucket{}, nil
+	}
+
+	width := priceBucketWidth(bounds.Min.Decimal, bounds.Max.Decimal)
+
+	var rows []struct {
+		Bucket int64
+		Count  int64
+	}
+	if err := matching().
+		Select("FLOOR(products.price / ?)::BIGINT AS bucket, COUNT(*) AS count", width).
+		Group("bucket").
+		Order("bucket").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	buckets := make([]PriceBucket, len(rows))
+	for i, row := range rows {
+		from := width.Mul(decimal.NewFromInt(row.Bucket))
+		buckets[i] = PriceBucket{From: from, To: from.Add(width), Count: row.Count}
+	}
+	return buckets, nil
+}
+
+// priceBucketWidth picks a round bucket width (1, 2 or 5 times a power of
+// ten, at least 1) splitting [min, max] into at most maxPriceBuckets buckets
+func priceBucketWidth(min, max decimal.Decimal) decimal.Decimal {
+	raw, _ := max.Sub(min).Div(decimal.NewFromInt(maxPriceBuckets)).Float64()
+	magnitude := decimal.NewFromInt(1)
+	if raw > 1 {
+		magnitude = decimal.New(1, int32(math.Floor(math.Log10(raw))))
+	}
+
+	for _, step := range []int64{1, 2, 5, 10} {
+		width := magnitude.Mul(decimal.NewFromInt(step))
+		// Bucket indexes are floored, so max needs a bucket of its own
+		if max.Div(width).Floor().Sub(min.Div(width).Floor()).LessThan(decimal.NewFromInt(maxPriceBuckets)) {
+			return width
+		}
+	}
+	return magnitude.Mul(decimal.NewFromInt(20))
+}
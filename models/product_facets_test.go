@@ -0,0 +1,28 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceBucketWidth(t *testing.T) {
+	cases := []struct {
+		min, max, width string
+	}{
+		{"5.50", "22.99", "2"},
+		{"10.00", "10.00", "1"},
+		{"0.00", "1000.00", "200"},
+		{"120.00", "180.00", "10"},
+		{"0.50", "10.50", "2"},
+	}
+	for _, c := range cases {
+		width := priceBucketWidth(mustDecimal(c.min), mustDecimal(c.max))
+		assert.Equal(t, c.width, width.String(), "range %s-%s", c.min, c.max)
+	}
+}
+
+func TestFacetRequest_Empty(t *testing.T) {
+	assert.True(t, FacetRequest{}.Empty())
+	assert.False(t, FacetRequest{Attributes: []string{"color"}}.Empty())
+}
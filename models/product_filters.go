@@ -0,0 +1,267 @@
+package models
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PriceMode selects which price the price filters are applied to
+type PriceMode string
+
+const (
+	// PriceModeProduct filters on the product price
+	PriceModeProduct PriceMode = "product"
+	// PriceModeVariant matches products having any variant whose effective
+	// price (own price or inherited product price) is within the bounds.
+	// Products without variants are matched on their product price.
+	PriceModeVariant PriceMode = "variant"
+)
+
+// effectivePricesSQL selects the effective prices of a product row: one per
+// variant, applying the same inheritance as Variant.EffectivePrice, or the
+// product price when the product has no variants.
+const effectivePricesSQL = `
+SELECT COALESCE(NULLIF(v.price, 0), products.price) AS price
+FROM product_variants v WHERE v.product_id = products.id
+UNION ALL
+SELECT products.price
+WHERE NOT EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = products.id)`
+
+// ProductFilters contains filtering options for product queries
+type ProductFilters struct {
+	Offset        int
+	Limit         int
+	CategoryCode  string
+	PriceLessThan *decimal.Decimal
+
+	// IncludeSubcategories extends the category filter to all descendants
+	IncludeSubcategories bool
+
+	// Query is a full-text search over product codes, variant names, variant
+	// SKUs and category names. Matches are ordered by relevance.
+	Query string
+
+	// CategoryCodes matches products in any of the given categories,
+	// in addition to CategoryCode
+	CategoryCodes []string
+
+	PriceGreaterThan *decimal.Decimal
+
+	// PriceInclusive turns the price bounds into <= and >=
+	PriceInclusive bool
+
+	// PriceMode defaults to PriceModeProduct
+	PriceMode PriceMode
+
+	// HasVariants, when set, keeps only products with (true) or without
+	// (false) variants
+	HasVariants *bool
+
+	// InStock, when set, keeps only products with (true) or without (false)
+	// a variant that has available stock
+	InStock *bool
+
+	// Attributes maps attribute codes to accepted option values, e.g.
+	// {"color": ["black"], "size": ["42"]}. A product matches when one of its
+	// variants has an accepted value for every attribute. Values are
+	// compared case-insensitively, and numerically for number attributes.
+	Attributes map[string][]string
+
+	// Sort is one of the keys of productSortColumns, prefixed with "-" for
+	// descending order. Results are always tie-broken by products.id.
+	Sort string
+
+	// After and Before position a keyset page (see GetProductsPage)
+	After  *Cursor
+	Before *Cursor
+}
+
+// productSortColumns whitelists the sort keys accepted in ProductFilters.Sort
+var productSortColumns = map[string]string{
+	"price":         "products.price",
+	"code":          "products.code",
+	"created_at":    "products.created_at",
+	"variant_count": "(SELECT COUNT(*) FROM product_variants v WHERE v.product_id = products.id)",
+}
+
+// sortColumn resolves Sort to its whitelisted SQL expression and direction.
+// Without an explicit sort products are ordered by id.
+func (f ProductFilters) sortColumn() (column string, desc bool, err error) {
+	if f.Sort == "" {
+		return "products.id", false, nil
+	}
+
+	key := f.Sort
+	if strings.HasPrefix(key, "-") {
+		key, desc = key[1:], true
+	}
+
+	column, ok := productSortColumns[key]
+	if !ok {
+		return "", false, ErrInvalidSort
+	}
+	return column, desc, nil
+}
+
+// rankedBySearch reports whether results are ordered by search relevance
+func (f ProductFilters) rankedBySearch() bool {
+	return f.Sort == "" && f.Query != ""
+}
+
+// orderBy returns the ORDER BY expression for the filters. Without an
+// explicit sort, search results are ordered by relevance and other listings
+// by id so offset pagination is stable.
+func (f ProductFilters) orderBy() (clause.OrderBy, error) {
+	if f.rankedBySearch() {
+		return orderByExpr("ts_rank(products.search_vector, websearch_to_tsquery('simple', ?)) DESC, products.id", f.Query), nil
+	}
+
+	column, desc, err := f.sortColumn()
+	if err != nil {
+		return clause.OrderBy{}, err
+	}
+	return orderByExpr(orderSQL(column, desc)), nil
+}
+
+// orderSQL orders by column and breaks ties by products.id in the same direction
+func orderSQL(column string, desc bool) string {
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+	if column == "products.id" {
+		return column + " " + direction
+	}
+	return column + " " + direction + ", products.id " + direction
+}
+
+// orderByExpr wraps a raw ORDER BY expression for gorm's Order
+func orderByExpr(sql string, vars ...any) clause.OrderBy {
+	return clause.OrderBy{Expression: clause.Expr{SQL: sql, Vars: vars, WithoutParentheses: true}}
+}
+
+// categoryCodes returns the category codes to filter on
+func (f ProductFilters) categoryCodes() []string {
+	codes := f.CategoryCodes
+	if f.CategoryCode != "" {
+		codes = append([]string{f.CategoryCode}, codes...)
+	}
+	return codes
+}
+
+// priceCondition builds the SQL condition applying the price bounds to column
+func (f ProductFilters) priceCondition(column string) (string, []any) {
+	lt, gt := " < ?", " > ?"
+	if f.PriceInclusive {
+		lt, gt = " <= ?", " >= ?"
+	}
+
+	var conditions []string
+	var args []any
+	if f.PriceLessThan != nil {
+		conditions = append(conditions, column+lt)
+		args = append(args, *f.PriceLessThan)
+	}
+	if f.PriceGreaterThan != nil {
+		conditions = append(conditions, column+gt)
+		args = append(args, *f.PriceGreaterThan)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// attributeCondition builds the SQL condition matching products having a
+// variant with an accepted value for every filtered attribute. Values of
+// number attributes are stored in canonical form, so accepted numbers are
+// canonicalized too and "40.0" matches 40.
+func (f ProductFilters) attributeCondition() (string, []any) {
+	if len(f.Attributes) == 0 {
+		return "", nil
+	}
+
+	codes := make([]string, 0, len(f.Attributes))
+	for code := range f.Attributes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	conditions := make([]string, len(codes))
+	args := make([]any, 0, 4*len(codes))
+	for i, code := range codes {
+		values := make([]string, len(f.Attributes[code]))
+		var numbers []string
+		for j, value := range f.Attributes[code] {
+			values[j] = strings.ToLower(value)
+			if number, ok := canonicalNumber(value); ok {
+				numbers = append(numbers, number)
+			}
+		}
+		match := "LOWER(o.value) IN ?"
+		args = append(args, code, values)
+		if len(numbers) > 0 {
+			match = "(" + match + " OR (a.data_type = ? AND o.value IN ?))"
+			args = append(args, AttributeNumber, numbers)
+		}
+		conditions[i] = "EXISTS (SELECT 1 FROM variant_options o JOIN attributes a ON a.id = o.attribute_id " +
+			"WHERE o.variant_id = v.id AND a.code = ? AND " + match + ")"
+	}
+	return "EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = products.id AND " +
+		strings.Join(conditions, " AND ") + ")", args
+}
+
+// applyProductFilters adds the WHERE clauses of filters to a products query
+func applyProductFilters(query *gorm.DB, filters ProductFilters) *gorm.DB {
+	// Apply category filter
+	if codes := filters.categoryCodes(); len(codes) > 0 {
+		if filters.IncludeSubcategories {
+			query = query.Where("products.category_id IN (?)",
+				query.Session(&gorm.Session{NewDB: true}).Raw(categorySubtreeSQL, codes))
+		} else {
+			query = query.Where("products.category_id IN (?)",
+				query.Session(&gorm.Session{NewDB: true}).Model(&Category{}).Select("id").Where("code IN ?", codes))
+		}
+	}
+
+	// Apply price filter
+	if filters.PriceMode == PriceModeVariant {
+		if condition, args := filters.priceCondition("effective.price"); condition != "" {
+			query = query.Where("EXISTS (SELECT 1 FROM ("+effectivePricesSQL+") effective WHERE "+condition+")", args...)
+		}
+	} else if condition, args := filters.priceCondition("products.price"); condition != "" {
+		query = query.Where(condition, args...)
+	}
+
+	// Apply variant presence filter
+	if filters.HasVariants != nil {
+		exists := "EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = products.id)"
+		if !*filters.HasVariants {
+			exists = "NOT " + exists
+		}
+		query = query.Where(exists)
+	}
+
+	// Apply stock availability filter
+	if filters.InStock != nil {
+		exists := "EXISTS (SELECT 1 FROM product_variants v JOIN variant_stock s ON s.sku = v.sku " +
+			"WHERE v.product_id = products.id AND s.on_hand > s.reserved)"
+		if !*filters.InStock {
+			exists = "NOT " + exists
+		}
+		query = query.Where(exists)
+	}
+
+	// Apply attribute filters
+	if condition, args := filters.attributeCondition(); condition != "" {
+		query = query.Where(condition, args...)
+	}
+
+	// Apply full-text search
+	if filters.Query != "" {
+		query = query.Where("products.search_vector @@ websearch_to_tsquery('simple', ?)", filters.Query)
+	}
+
+	return query
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ImportResult is the outcome of ImportProducts
+type ImportResult struct {
+	Created int
+	Updated int
+
+	// Errors maps the index of every product that was not imported to the
+	// reason it was rejected
+	Errors map[int]error
+}
+
+// errImportRejected rolls back an atomic import with rejected products
+var errImportRejected = errors.New("import rejected")
+
+// ImportProducts upserts products by code. New products are created with
+// their variants. An existing product takes the price and category of the
+// imported one and its variants are upserted by SKU; its other variants,
+// price overrides, tax class and variant options are kept.
+//
+// With a chunkSize of 0 the products are written in a single transaction
+// and nothing is written when a product is rejected. Otherwise every
+// chunkSize products are committed together and rejected products are
+// skipped. Products are rejected for domain errors only, other errors abort
+// the import.
+func (r *ProductsRepository) ImportProducts(products []Product, chunkSize int) (*ImportResult, error) {
+	result := &ImportResult{Errors: map[int]error{}}
+
+	size := chunkSize
+	if size <= 0 || size > len(products) {
+		size = len(products)
+	}
+	for start := 0; start < len(products); start += size {
+		end := min(start+size, len(products))
+
+		var created, updated int
+		err := r.transaction(func(tx *gorm.DB) error {
+			for i := start; i < end; i++ {
+				var existed bool
+				// Each product runs in a savepoint, so a rejected product
+				// leaves the rest of the transaction usable
+				err := tx.Transaction(func(tx *gorm.DB) error {
+					var err error
+					existed, err = importProduct(tx, &products[i])
+					return err
+				})
+				if err != nil {
+					if !isImportRejection(err) {
+						return err
+					}
+					result.Errors[i] = err
+					continue
+				}
+				if existed {
+					updated++
+				} else {
+					created++
+				}
+			}
+			if chunkSize <= 0 && len(result.Errors) > 0 {
+				return errImportRejected
+			}
+			return nil
+		})
+		if errors.Is(err, errImportRejected) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Created += created
+		result.Updated += updated
+	}
+	return result, nil
+}
+
+// importProduct upserts a single product and reports whether it existed
+func importProduct(tx *gorm.DB, product *Product) (bool, error) {
+	if err := validateProduct(product); err != nil {
+		return false, err
+	}
+
+	category, err := findCategoryByCode(tx, product.Category.Code)
+	if err != nil {
+		return false, err
+	}
+	product.CategoryID = category.ID
+	product.Category = *category
+
+	var existing Product
+	err = tx.Preload("Variants").Where("code = ?", product.Code).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if err := tx.Omit("Category", "TaxClass").Create(product).Error; err != nil {
+			return false, mapProductWriteError(err)
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	product.ID = existing.ID
+	if err := tx.Model(&Product{}).Where("id = ?", existing.ID).Updates(map[string]any{
+		"price":       product.Price,
+		"category_id": product.CategoryID,
+		"updated_at":  gorm.Expr("NOW()"),
+	}).Error; err != nil {
+		return false, mapProductWriteError(err)
+	}
+
+	current := make(map[string]uint, len(existing.Variants))
+	for _, v := range existing.Variants {
+		current[v.SKU] = v.ID
+	}
+	for i := range product.Variants {
+		v := &product.Variants[i]
+		v.ProductID = existing.ID
+		if id, ok := current[v.SKU]; ok {
+			v.ID = id
+			if err := tx.Model(&Variant{}).Where("id = ?", id).Updates(map[string]any{
+				"name":       v.Name,
+				"price":      v.Price,
+				"updated_at": gorm.Expr("NOW()"),
+			}).Error; err != nil {
+				return false, mapProductWriteError(err)
+			}
+			continue
+		}
+		v.ID = 0
+		if err := tx.Create(v).Error; err != nil {
+			return false, mapProductWriteError(err)
+		}
+	}
+	return true, nil
+}
+
+// isImportRejection reports whether err rejects a single imported product
+// rather than the whole import
+func isImportRejection(err error) bool {
+	for _, target := range []error{
+		ErrInvalidProduct,
+		ErrCategoryNotFound,
+		ErrProductCodeExists,
+		ErrVariantSKUExists,
+		ErrCurrencyNotFound,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
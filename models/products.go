@@ -6,6 +6,7 @@ import (
 
 // Product represents a product in the catalog.
 // It includes a unique code, a price, and a category.
+// Prices holds explicit overrides of the price in other currencies.
 type Product struct {
 	ID         uint            `gorm:"primaryKey"`
 	Code       string          `gorm:"uniqueIndex;not null"`
@@ -13,6 +14,25 @@ type Product struct {
 	CategoryID uint            `gorm:"not null"`
 	Category   Category        `gorm:"foreignKey:CategoryID"`
 	Variants   []Variant       `gorm:"foreignKey:ProductID"`
+	Prices     []ProductPrice  `gorm:"foreignKey:ProductID"`
+	TaxClassID *uint           `gorm:"index"`
+	TaxClass   *TaxClass       `gorm:"foreignKey:TaxClassID"`
+
+	// ScheduledPrices holds the scheduled prices loaded with the product,
+	// usually only the active ones
+	ScheduledPrices []ScheduledPrice `gorm:"foreignKey:ProductID"`
+
+	// Promotions holds the active promotions targeting the product or its
+	// category tree. They are attached by the repository, not preloaded.
+	Promotions []Promotion `gorm:"-"`
+
+	// EffectiveTaxClass is TaxClass, else the class inherited from the
+	// category tree or the default class. It is attached by the repository.
+	EffectiveTaxClass *TaxClass `gorm:"-"`
+
+	// Attributes holds the attributes defined on the category tree of the
+	// product, ordered by position. They are attached by the repository.
+	Attributes []Attribute `gorm:"-"`
 }
 
 func (p *Product) TableName() string {
@@ -2,28 +2,37 @@ package models
 
 import (
 	"errors"
+	"strings"
 
-	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
-// ProductFilters contains filtering options for product queries
-type ProductFilters struct {
-	Offset        int
-	Limit         int
-	CategoryCode  string
-	PriceLessThan *decimal.Decimal
-}
-
 // ProductRepository defines the interface for product data access
 type ProductRepository interface {
 	GetAllProducts(offset, limit int) ([]Product, int64, error)
 	GetProductByCode(code string) (*Product, error)
+	GetProductsBySKUs(skus []string) ([]Product, error)
 	GetProductsWithFilters(filters ProductFilters) ([]Product, int64, error)
+	GetProductsPage(filters ProductFilters) (*ProductPage, error)
+	GetProductFacets(filters ProductFilters, request FacetRequest) (*Facets, error)
+	GetCategoryPath(categoryID uint) ([]Category, error)
+	GetStockLevels(skus []string, warehouse string) ([]StockLevel, error)
+	GetScheduledPrices(code string) ([]ScheduledPrice, error)
+	CreateScheduledPrice(code, sku string, price *ScheduledPrice) error
+	DeleteScheduledPrice(code string, id uint) error
+	GetPriceHistory(code string, filters PriceHistoryFilters) ([]PriceChange, error)
+	CreateProduct(product *Product) error
+	UpdateProduct(code string, product *Product) error
+	DeleteProduct(code string) error
+	CreateVariant(productCode string, variant *Variant) error
+	UpdateVariant(productCode, sku string, variant *Variant) error
+	DeleteVariant(productCode, sku string) error
+	ImportProducts(products []Product, chunkSize int) (*ImportResult, error)
 }
 
 type ProductsRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	actor string
 }
 
 func NewProductsRepository(db *gorm.DB) *ProductsRepository {
@@ -32,6 +41,28 @@ func NewProductsRepository(db *gorm.DB) *ProductsRepository {
 	}
 }
 
+// WithActor returns a repository whose writes are attributed to actor in
+// the price history
+func (r *ProductsRepository) WithActor(actor string) *ProductsRepository {
+	return &ProductsRepository{
+		db:    r.db,
+		actor: actor,
+	}
+}
+
+// transaction runs fn in a transaction that carries the actor of the
+// repository for the price audit triggers
+func (r *ProductsRepository) transaction(fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if r.actor != "" {
+			if err := tx.Exec("SELECT set_config('app.actor', ?, true)", r.actor).Error; err != nil {
+				return err
+			}
+		}
+		return fn(tx)
+	})
+}
+
 // GetAllProducts retrieves products with pagination
 func (r *ProductsRepository) GetAllProducts(offset, limit int) ([]Product, int64, error) {
 	// Validate pagination parameters
@@ -57,19 +88,49 @@ func (r *ProductsRepository) GetAllProducts(offset, limit int) ([]Product, int64
 	return products, total, nil
 }
 
-// GetProductByCode retrieves a single product by its code
+// GetProductByCode retrieves a single product by its code, with the
+// attributes of its category tree and the options of its variants
 func (r *ProductsRepository) GetProductByCode(code string) (*Product, error) {
 	var product Product
-	if err := r.db.Preload("Category").Preload("Variants").
+	if err := r.db.Preload("Category").Preload("Variants.Prices").Preload("Variants.Stock").Preload("Prices").
+		Preload("ScheduledPrices", activeScheduledPricesSQL).Preload("TaxClass").
 		Where("code = ?", code).First(&product).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrProductNotFound
 		}
 		return nil, err
 	}
+
+	if err := attachProductPricingRules(r.db, &product); err != nil {
+		return nil, err
+	}
+	if err := attachAttributes(r.db, &product); err != nil {
+		return nil, err
+	}
 	return &product, nil
 }
 
+// GetProductsBySKUs retrieves the products owning any of the given variant
+// SKUs, with all their variants. Unknown SKUs are ignored.
+func (r *ProductsRepository) GetProductsBySKUs(skus []string) ([]Product, error) {
+	var products []Product
+	if len(skus) == 0 {
+		return products, nil
+	}
+
+	if err := r.db.Preload("Category").Preload("Variants.Prices").Preload("Variants.Stock").Preload("Prices").
+		Preload("ScheduledPrices", activeScheduledPricesSQL).
+		Where("id IN (?)", r.db.Model(&Variant{}).Select("product_id").Where("sku IN ?", skus)).
+		Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	if err := attachPricingRules(r.db, products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
 // GetProductsWithFilters retrieves products with filtering and pagination
 func (r *ProductsRepository) GetProductsWithFilters(filters ProductFilters) ([]Product, int64, error) {
 	// Validate pagination parameters
@@ -80,18 +141,12 @@ func (r *ProductsRepository) GetProductsWithFilters(filters ProductFilters) ([]P
 	var products []Product
 	var total int64
 
-	query := r.db.Model(&Product{})
-
-	// Apply category filter
-	if filters.CategoryCode != "" {
-		query = query.Joins("JOIN categories ON categories.id = products.category_id").
-			Where("categories.code = ?", filters.CategoryCode)
+	order, err := filters.orderBy()
+	if err != nil {
+		return nil, 0, err
 	}
 
-	// Apply price filter
-	if filters.PriceLessThan != nil {
-		query = query.Where("products.price < ?", filters.PriceLessThan)
-	}
+	query := applyProductFilters(r.db.Model(&Product{}), filters)
 
 	// Count total with filters
 	if err := query.Count(&total).Error; err != nil {
@@ -99,11 +154,593 @@ func (r *ProductsRepository) GetProductsWithFilters(filters ProductFilters) ([]P
 	}
 
 	// Fetch with pagination and preload
-	if err := query.Preload("Category").Preload("Variants").
+	if err := query.Order(order).Preload("Category").Preload("Variants.Prices").Preload("Variants.Stock").Preload("Prices").
+		Preload("ScheduledPrices", activeScheduledPricesSQL).
 		Offset(filters.Offset).Limit(filters.Limit).
 		Find(&products).Error; err != nil {
 		return nil, 0, err
 	}
 
+	if err := attachPricingRules(r.db, products); err != nil {
+		return nil, 0, err
+	}
+
 	return products, total, nil
 }
+
+// GetCategoryPath returns the breadcrumb of a product category, root first
+func (r *ProductsRepository) GetCategoryPath(categoryID uint) ([]Category, error) {
+	return categoryPath(r.db, categoryID)
+}
+
+// GetStockLevels retrieves the per-warehouse stock of the given SKUs with
+// their warehouse loaded. A non-empty warehouse code restricts the levels to
+// that warehouse and fails with ErrWarehouseNotFound when it does not exist.
+func (r *ProductsRepository) GetStockLevels(skus []string, warehouse string) ([]StockLevel, error) {
+	query := r.db.Joins("Warehouse").Where("stock_levels.sku IN ?", skus)
+	if warehouse != "" {
+		wh, err := findWarehouse(r.db, warehouse)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("stock_levels.warehouse_id = ?", wh.ID)
+	}
+
+	var levels []StockLevel
+	if err := query.Order("\"Warehouse\".code").Find(&levels).Error; err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+// CreateProduct inserts a product together with its variants.
+// The category is resolved from product.Category.Code.
+func (r *ProductsRepository) CreateProduct(product *Product) error {
+	if err := validateProduct(product); err != nil {
+		return err
+	}
+
+	return r.transaction(func(tx *gorm.DB) error {
+		category, err := findCategoryByCode(tx, product.Category.Code)
+		if err != nil {
+			return err
+		}
+		product.CategoryID = category.ID
+		product.Category = *category
+
+		taxClass, err := resolveTaxClass(tx, product.TaxClass)
+		if err != nil {
+			return err
+		}
+		product.TaxClass, product.TaxClassID = taxClass, taxClass.ref()
+
+		if err := tx.Omit("Category", "TaxClass").Create(product).Error; err != nil {
+			return mapProductWriteError(err)
+		}
+		if err := writeProductOptions(tx, product); err != nil {
+			return err
+		}
+		return attachProductPricingRules(tx, product)
+	})
+}
+
+// UpdateProduct replaces the product identified by code with the given data.
+// Variants are matched by SKU: existing ones are updated, missing ones are
+// deleted and new ones are inserted. Variants held by pending reservations
+// cannot be deleted.
+func (r *ProductsRepository) UpdateProduct(code string, product *Product) error {
+	if err := validateProduct(product); err != nil {
+		return err
+	}
+
+	return r.transaction(func(tx *gorm.DB) error {
+		var existing Product
+		if err := tx.Preload("Variants").Where("code = ?", code).First(&existing).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProductNotFound
+			}
+			return err
+		}
+
+		category, err := findCategoryByCode(tx, product.Category.Code)
+		if err != nil {
+			return err
+		}
+		product.ID = existing.ID
+		product.CategoryID = category.ID
+		product.Category = *category
+
+		taxClass, err := resolveTaxClass(tx, product.TaxClass)
+		if err != nil {
+			return err
+		}
+		product.TaxClass, product.TaxClassID = taxClass, taxClass.ref()
+
+		if err := tx.Model(&Product{}).Where("id = ?", existing.ID).Updates(map[string]any{
+			"code":         product.Code,
+			"price":        product.Price,
+			"category_id":  product.CategoryID,
+			"tax_class_id": product.TaxClassID,
+			"updated_at":   gorm.Expr("NOW()"),
+		}).Error; err != nil {
+			return mapProductWriteError(err)
+		}
+
+		if err := replaceProductPrices(tx, existing.ID, product.Prices); err != nil {
+			return err
+		}
+
+		if err := syncVariants(tx, existing.ID, existing.Variants, product.Variants); err != nil {
+			return err
+		}
+		if err := writeProductOptions(tx, product); err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ?", existing.ID).Where(activeScheduledPricesSQL).
+			Find(&product.ScheduledPrices).Error; err != nil {
+			return err
+		}
+		if err := attachProductPricingRules(tx, product); err != nil {
+			return err
+		}
+		return attachStock(tx, product.Variants)
+	})
+}
+
+// DeleteProduct removes the product identified by code. Its variants are
+// removed by the ON DELETE CASCADE foreign key. It fails with
+// ErrVariantReserved while a pending reservation holds one of them.
+func (r *ProductsRepository) DeleteProduct(code string) error {
+	return r.transaction(func(tx *gorm.DB) error {
+		var skus []string
+		if err := tx.Model(&Variant{}).
+			Joins("JOIN products ON products.id = product_variants.product_id").
+			Where("products.code = ?", code).
+			Pluck("product_variants.sku", &skus).Error; err != nil {
+			return err
+		}
+		if err := lockUnreservedVariants(tx, skus); err != nil {
+			return err
+		}
+
+		result := tx.Where("code = ?", code).Delete(&Product{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrProductNotFound
+		}
+		return nil
+	})
+}
+
+// CreateVariant adds a variant to the product identified by productCode
+func (r *ProductsRepository) CreateVariant(productCode string, variant *Variant) error {
+	if err := validateVariant(variant); err != nil {
+		return err
+	}
+
+	return r.transaction(func(tx *gorm.DB) error {
+		product, err := findProductByCode(tx, productCode)
+		if err != nil {
+			return err
+		}
+
+		variant.ID = 0
+		variant.ProductID = product.ID
+		if err := tx.Create(variant).Error; err != nil {
+			return mapProductWriteError(err)
+		}
+
+		attributes, err := categoryAttributes(tx, product.CategoryID)
+		if err != nil {
+			return err
+		}
+		return writeVariantOptions(tx, attributes, variant)
+	})
+}
+
+// UpdateVariant overwrites name, SKU, price, price overrides and options of
+// the variant identified by productCode and sku. A variant price with Valid=false
+// is stored as NULL so the variant inherits the product price again. The SKU
+// cannot change while a pending reservation holds the variant.
+func (r *ProductsRepository) UpdateVariant(productCode, sku string, variant *Variant) error {
+	if err := validateVariant(variant); err != nil {
+		return err
+	}
+
+	return r.transaction(func(tx *gorm.DB) error {
+		existing, err := findVariant(tx, productCode, sku)
+		if err != nil {
+			return err
+		}
+		if variant.SKU != existing.SKU {
+			if err := lockUnreservedVariants(tx, []string{existing.SKU}); err != nil {
+				return err
+			}
+		}
+		product, err := findProductByCode(tx, productCode)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&Variant{}).Where("id = ?", existing.ID).Updates(map[string]any{
+			"name":       variant.Name,
+			"sku":        variant.SKU,
+			"price":      variant.Price,
+			"updated_at": gorm.Expr("NOW()"),
+		}).Error; err != nil {
+			return mapProductWriteError(err)
+		}
+
+		variant.ID = existing.ID
+		variant.ProductID = existing.ProductID
+		if err := replaceVariantPrices(tx, existing.ID, variant.Prices); err != nil {
+			return err
+		}
+		attributes, err := categoryAttributes(tx, product.CategoryID)
+		if err != nil {
+			return err
+		}
+		if err := writeVariantOptions(tx, attributes, variant); err != nil {
+			return err
+		}
+		variants := []Variant{*variant}
+		if err := attachStock(tx, variants); err != nil {
+			return err
+		}
+		variant.Stock = variants[0].Stock
+		return nil
+	})
+}
+
+// DeleteVariant removes the variant identified by productCode and sku. It
+// fails with ErrVariantReserved while a pending reservation holds it.
+func (r *ProductsRepository) DeleteVariant(productCode, sku string) error {
+	return r.transaction(func(tx *gorm.DB) error {
+		existing, err := findVariant(tx, productCode, sku)
+		if err != nil {
+			return err
+		}
+		if err := lockUnreservedVariants(tx, []string{existing.SKU}); err != nil {
+			return err
+		}
+		return tx.Delete(&Variant{}, existing.ID).Error
+	})
+}
+
+// findVariant looks up a variant by the code of its product and its SKU
+func findVariant(tx *gorm.DB, productCode, sku string) (*Variant, error) {
+	var variant Variant
+	if err := tx.Joins("JOIN products ON products.id = product_variants.product_id").
+		Where("products.code = ? AND product_variants.sku = ?", productCode, sku).
+		First(&variant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVariantNotFound
+		}
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// validateVariant performs the minimal checks required before writing a variant
+func validateVariant(variant *Variant) error {
+	if variant == nil {
+		return ErrInvalidVariant
+	}
+	if strings.TrimSpace(variant.SKU) == "" || strings.TrimSpace(variant.Name) == "" {
+		return ErrInvalidVariant
+	}
+	return nil
+}
+
+// syncVariants reconciles the stored variants of a product with the desired
+// set. It fails with ErrVariantReserved when a variant to delete is held by a
+// pending reservation.
+func syncVariants(tx *gorm.DB, productID uint, current, desired []Variant) error {
+	keep := make(map[string]bool, len(desired))
+	for _, v := range desired {
+		keep[v.SKU] = true
+	}
+
+	var removed []string
+	for _, v := range current {
+		if !keep[v.SKU] {
+			removed = append(removed, v.SKU)
+		}
+	}
+	if err := lockUnreservedVariants(tx, removed); err != nil {
+		return err
+	}
+
+	// Delete first so a SKU can move between variants of the same product
+	for _, v := range current {
+		if !keep[v.SKU] {
+			if err := tx.Delete(&Variant{}, v.ID).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	existing := make(map[string]uint, len(current))
+	for _, v := range current {
+		existing[v.SKU] = v.ID
+	}
+
+	for i := range desired {
+		v := &desired[i]
+		v.ProductID = productID
+		if id, ok := existing[v.SKU]; ok {
+			v.ID = id
+			if err := tx.Model(&Variant{}).Where("id = ?", id).Updates(map[string]any{
+				"name":       v.Name,
+				"price":      v.Price,
+				"updated_at": gorm.Expr("NOW()"),
+			}).Error; err != nil {
+				return mapProductWriteError(err)
+			}
+			if err := replaceVariantPrices(tx, id, v.Prices); err != nil {
+				return err
+			}
+			continue
+		}
+		v.ID = 0
+		if err := tx.Create(v).Error; err != nil {
+			return mapProductWriteError(err)
+		}
+	}
+
+	return nil
+}
+
+// replaceProductPrices replaces the currency price overrides of a product
+func replaceProductPrices(tx *gorm.DB, productID uint, prices []ProductPrice) error {
+	if err := tx.Where("product_id = ?", productID).Delete(&ProductPrice{}).Error; err != nil {
+		return err
+	}
+	if len(prices) == 0 {
+		return nil
+	}
+	for i := range prices {
+		prices[i].ProductID = productID
+	}
+	if err := tx.Create(&prices).Error; err != nil {
+		return mapProductWriteError(err)
+	}
+	return nil
+}
+
+// replaceVariantPrices replaces the currency price overrides of a variant
+func replaceVariantPrices(tx *gorm.DB, variantID uint, prices []VariantPrice) error {
+	if err := tx.Where("variant_id = ?", variantID).Delete(&VariantPrice{}).Error; err != nil {
+		return err
+	}
+	if len(prices) == 0 {
+		return nil
+	}
+	for i := range prices {
+		prices[i].VariantID = variantID
+	}
+	if err := tx.Create(&prices).Error; err != nil {
+		return mapProductWriteError(err)
+	}
+	return nil
+}
+
+// attachStock loads the stock levels of variants written from request data,
+// which carry no stock of their own
+func attachStock(tx *gorm.DB, variants []Variant) error {
+	if len(variants) == 0 {
+		return nil
+	}
+
+	skus := make([]string, len(variants))
+	for i, v := range variants {
+		skus[i] = v.SKU
+	}
+
+	var levels []VariantStock
+	if err := tx.Where("sku IN ?", skus).Find(&levels).Error; err != nil {
+		return err
+	}
+
+	bySKU := make(map[string]*VariantStock, len(levels))
+	for i := range levels {
+		bySKU[levels[i].SKU] = &levels[i]
+	}
+	for i := range variants {
+		variants[i].Stock = bySKU[variants[i].SKU]
+	}
+	return nil
+}
+
+// validateProduct performs the minimal checks required before writing a product
+func validateProduct(product *Product) error {
+	if product == nil {
+		return ErrInvalidProduct
+	}
+	if strings.TrimSpace(product.Code) == "" || strings.TrimSpace(product.Category.Code) == "" {
+		return ErrInvalidProduct
+	}
+	for i := range product.Variants {
+		if validateVariant(&product.Variants[i]) != nil {
+			return ErrInvalidProduct
+		}
+	}
+	return nil
+}
+
+// findCategoryByCode looks up a category by its code within the given transaction
+func findCategoryByCode(tx *gorm.DB, code string) (*Category, error) {
+	var category Category
+	if err := tx.Where("code = ?", code).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+// mapProductWriteError translates unique violations and unknown override
+// currencies into domain errors
+func mapProductWriteError(err error) error {
+	if constraint, ok := foreignKeyViolation(err); ok && strings.HasSuffix(constraint, "_currency_fkey") {
+		return ErrCurrencyNotFound
+	}
+
+	constraint, ok := uniqueViolation(err)
+	if !ok {
+		return err
+	}
+	if strings.HasPrefix(constraint, "product_variants") {
+		return ErrVariantSKUExists
+	}
+	return ErrProductCodeExists
+}
+
+// GetScheduledPrices retrieves all scheduled prices of the product identified
+// by code, past and future, ordered by start
+func (r *ProductsRepository) GetScheduledPrices(code string) ([]ScheduledPrice, error) {
+	product, err := findProductByCode(r.db, code)
+	if err != nil {
+		return nil, err
+	}
+
+	var prices []ScheduledPrice
+	if err := r.db.Preload("Variant").Where("product_id = ?", product.ID).
+		Order("valid_from, id").Find(&prices).Error; err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+// CreateScheduledPrice schedules a price for the product identified by code,
+// or for its variant sku when sku is not empty
+func (r *ProductsRepository) CreateScheduledPrice(code, sku string, price *ScheduledPrice) error {
+	if price == nil || !price.Price.IsPositive() || (price.ValidTo != nil && !price.ValidTo.After(price.ValidFrom)) {
+		return ErrInvalidScheduledPrice
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		product, err := findProductByCode(tx, code)
+		if err != nil {
+			return err
+		}
+		price.ID = 0
+		price.ProductID = product.ID
+		price.VariantID = nil
+
+		if sku != "" {
+			variant, err := findVariant(tx, code, sku)
+			if err != nil {
+				return err
+			}
+			price.VariantID = &variant.ID
+			price.Variant = variant
+		}
+
+		return tx.Omit("Variant").Create(price).Error
+	})
+}
+
+// DeleteScheduledPrice removes a scheduled price of the product identified by code
+func (r *ProductsRepository) DeleteScheduledPrice(code string, id uint) error {
+	product, err := findProductByCode(r.db, code)
+	if err != nil {
+		return err
+	}
+
+	result := r.db.Where("id = ? AND product_id = ?", id, product.ID).Delete(&ScheduledPrice{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrScheduledPriceNotFound
+	}
+	return nil
+}
+
+// findProductByCode looks up a product without its relations
+func findProductByCode(tx *gorm.DB, code string) (*Product, error) {
+	var product Product
+	if err := tx.Where("code = ?", code).First(&product).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	return &product, nil
+}
+
+// GetPriceHistory retrieves the price changes of the product identified by
+// code and of its variants, oldest first. Once a product is deleted the
+// changes recorded under its code are returned instead; ErrProductNotFound
+// is only returned for codes without any history.
+func (r *ProductsRepository) GetPriceHistory(code string, filters PriceHistoryFilters) ([]PriceChange, error) {
+	var query *gorm.DB
+	product, err := findProductByCode(r.db, code)
+	switch {
+	case err == nil:
+		query = r.db.Where("product_id = ?", product.ID)
+	case errors.Is(err, ErrProductNotFound):
+		// The history of a deleted product is kept under its code
+		var count int64
+		if err := r.db.Model(&PriceChange{}).Where("product_id IS NULL AND product_code = ?", code).
+			Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, ErrProductNotFound
+		}
+		query = r.db.Where("product_id IS NULL AND product_code = ?", code)
+	default:
+		return nil, err
+	}
+
+	if !filters.From.IsZero() {
+		query = query.Where("changed_at >= ?", filters.From)
+	}
+	if !filters.To.IsZero() {
+		query = query.Where("changed_at < ?", filters.To)
+	}
+
+	var changes []PriceChange
+	if err := query.Order("changed_at, id").Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// attachPricingRules loads the active promotions and the effective tax class
+// of products, which both follow the category tree
+func attachPricingRules(tx *gorm.DB, products []Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	categoryIDs := make([]uint, len(products))
+	for i, p := range products {
+		categoryIDs[i] = p.CategoryID
+	}
+	ancestry, err := categoryAncestry(tx, categoryIDs)
+	if err != nil {
+		return err
+	}
+
+	if err := attachPromotions(tx, products, ancestry); err != nil {
+		return err
+	}
+	return attachTaxClasses(tx, products, ancestry)
+}
+
+// attachProductPricingRules loads the pricing rules of a single product
+func attachProductPricingRules(tx *gorm.DB, product *Product) error {
+	products := []Product{*product}
+	if err := attachPricingRules(tx, products); err != nil {
+		return err
+	}
+	product.Promotions = products[0].Promotions
+	product.EffectiveTaxClass = products[0].EffectiveTaxClass
+	return nil
+}
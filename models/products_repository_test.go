@@ -3,6 +3,7 @@ package models
 import (
 	"testing"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -223,3 +224,444 @@ func TestGetProductsWithFilters(t *testing.T) {
 		}
 	})
 }
+
+func TestCreateProduct(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewProductsRepository(db)
+
+	t.Run("creates product with variants and resolves category", func(t *testing.T) {
+		db.Where("code = ?", "TEST_REPO_CREATE").Delete(&Product{})
+		cleanupProduct(t, db, "TEST_REPO_CREATE")
+
+		product := &Product{
+			Code:     "TEST_REPO_CREATE",
+			Price:    mustDecimal("9.99"),
+			Category: Category{Code: "SHOES"},
+			Variants: []Variant{
+				{Name: "A", SKU: "TEST_REPO_CREATE_A"},
+			},
+		}
+
+		err := repo.CreateProduct(product)
+
+		assert.NoError(t, err)
+		assert.NotZero(t, product.ID)
+		assert.NotZero(t, product.CategoryID)
+		assert.Equal(t, "Shoes", product.Category.Name)
+
+		found, err := repo.GetProductByCode("TEST_REPO_CREATE")
+		assert.NoError(t, err)
+		assert.Len(t, found.Variants, 1)
+		assert.False(t, found.Variants[0].Price.Valid, "Variant price should be stored as NULL")
+	})
+
+	t.Run("returns ErrProductCodeExists for duplicate code", func(t *testing.T) {
+		err := repo.CreateProduct(&Product{
+			Code:     "PROD001",
+			Price:    mustDecimal("1.00"),
+			Category: Category{Code: "SHOES"},
+		})
+
+		assert.ErrorIs(t, err, ErrProductCodeExists)
+	})
+
+	t.Run("returns ErrVariantSKUExists for duplicate SKU", func(t *testing.T) {
+		cleanupProduct(t, db, "TEST_REPO_DUP_SKU")
+
+		err := repo.CreateProduct(&Product{
+			Code:     "TEST_REPO_DUP_SKU",
+			Price:    mustDecimal("1.00"),
+			Category: Category{Code: "SHOES"},
+			Variants: []Variant{{Name: "A", SKU: "SKU001A"}},
+		})
+
+		assert.ErrorIs(t, err, ErrVariantSKUExists)
+	})
+
+	t.Run("returns ErrCategoryNotFound for unknown category", func(t *testing.T) {
+		err := repo.CreateProduct(&Product{
+			Code:     "TEST_REPO_BAD_CAT",
+			Price:    mustDecimal("1.00"),
+			Category: Category{Code: "NONEXISTENT"},
+		})
+
+		assert.ErrorIs(t, err, ErrCategoryNotFound)
+	})
+
+	t.Run("returns ErrInvalidProduct for nil product", func(t *testing.T) {
+		err := repo.CreateProduct(nil)
+
+		assert.ErrorIs(t, err, ErrInvalidProduct)
+	})
+}
+
+func TestUpdateProduct(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewProductsRepository(db)
+
+	t.Run("updates fields and syncs variants by SKU", func(t *testing.T) {
+		db.Where("code = ?", "TEST_REPO_UPDATE").Delete(&Product{})
+		cleanupProduct(t, db, "TEST_REPO_UPDATE")
+
+		err := repo.CreateProduct(&Product{
+			Code:     "TEST_REPO_UPDATE",
+			Price:    mustDecimal("5.00"),
+			Category: Category{Code: "SHOES"},
+			Variants: []Variant{
+				{Name: "A", SKU: "TEST_REPO_UPDATE_A"},
+				{Name: "B", SKU: "TEST_REPO_UPDATE_B"},
+			},
+		})
+		assert.NoError(t, err)
+		var before Variant
+		db.Where("sku = ?", "TEST_REPO_UPDATE_A").First(&before)
+
+		err = repo.UpdateProduct("TEST_REPO_UPDATE", &Product{
+			Code:     "TEST_REPO_UPDATE",
+			Price:    mustDecimal("6.00"),
+			Category: Category{Code: "CLOTHING"},
+			Variants: []Variant{
+				{Name: "A2", SKU: "TEST_REPO_UPDATE_A"},
+				{Name: "C", SKU: "TEST_REPO_UPDATE_C"},
+			},
+		})
+		assert.NoError(t, err)
+
+		after, err := repo.GetProductByCode("TEST_REPO_UPDATE")
+		assert.NoError(t, err)
+		assert.True(t, after.Price.Equal(mustDecimal("6.00")))
+		assert.Equal(t, "CLOTHING", after.Category.Code)
+		assert.Len(t, after.Variants, 2)
+
+		skus := make(map[string]Variant)
+		for _, v := range after.Variants {
+			skus[v.SKU] = v
+		}
+		assert.Equal(t, "A2", skus["TEST_REPO_UPDATE_A"].Name)
+		assert.Equal(t, before.ID, skus["TEST_REPO_UPDATE_A"].ID, "Existing variant should keep its ID")
+		assert.Contains(t, skus, "TEST_REPO_UPDATE_C")
+	})
+
+	t.Run("returns ErrProductNotFound for unknown product", func(t *testing.T) {
+		err := repo.UpdateProduct("NONEXISTENT", &Product{
+			Code:     "NONEXISTENT",
+			Price:    mustDecimal("1.00"),
+			Category: Category{Code: "SHOES"},
+		})
+
+		assert.ErrorIs(t, err, ErrProductNotFound)
+	})
+}
+
+func TestDeleteProduct(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewProductsRepository(db)
+
+	t.Run("deletes product", func(t *testing.T) {
+		db.Where("code = ?", "TEST_REPO_DELETE").Delete(&Product{})
+		cleanupProduct(t, db, "TEST_REPO_DELETE")
+
+		err := repo.CreateProduct(&Product{
+			Code:     "TEST_REPO_DELETE",
+			Price:    mustDecimal("1.00"),
+			Category: Category{Code: "SHOES"},
+		})
+		assert.NoError(t, err)
+
+		err = repo.DeleteProduct("TEST_REPO_DELETE")
+		assert.NoError(t, err)
+
+		_, err = repo.GetProductByCode("TEST_REPO_DELETE")
+		assert.ErrorIs(t, err, ErrProductNotFound)
+	})
+
+	t.Run("returns ErrProductNotFound for unknown product", func(t *testing.T) {
+		err := repo.DeleteProduct("NONEXISTENT")
+
+		assert.ErrorIs(t, err, ErrProductNotFound)
+	})
+}
+
+func TestVariantWrites(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewProductsRepository(db)
+
+	db.Where("code = ?", "TEST_REPO_VARIANTS").Delete(&Product{})
+	cleanupProduct(t, db, "TEST_REPO_VARIANTS")
+	err := repo.CreateProduct(&Product{
+		Code:     "TEST_REPO_VARIANTS",
+		Price:    mustDecimal("3.00"),
+		Category: Category{Code: "SHOES"},
+	})
+	assert.NoError(t, err)
+
+	t.Run("creates variant for product", func(t *testing.T) {
+		variant := &Variant{
+			Name:  "A",
+			SKU:   "TEST_REPO_VARIANTS_A",
+			Price: decimal.NewNullDecimal(mustDecimal("4.00")),
+		}
+
+		err := repo.CreateVariant("TEST_REPO_VARIANTS", variant)
+
+		assert.NoError(t, err)
+		assert.NotZero(t, variant.ID)
+	})
+
+	t.Run("returns ErrProductNotFound for unknown product", func(t *testing.T) {
+		err := repo.CreateVariant("NONEXISTENT", &Variant{Name: "A", SKU: "TEST_REPO_VARIANTS_X"})
+
+		assert.ErrorIs(t, err, ErrProductNotFound)
+	})
+
+	t.Run("returns ErrVariantSKUExists for duplicate SKU", func(t *testing.T) {
+		err := repo.CreateVariant("TEST_REPO_VARIANTS", &Variant{Name: "Dup", SKU: "SKU001A"})
+
+		assert.ErrorIs(t, err, ErrVariantSKUExists)
+	})
+
+	t.Run("clears variant price", func(t *testing.T) {
+		err := repo.UpdateVariant("TEST_REPO_VARIANTS", "TEST_REPO_VARIANTS_A", &Variant{
+			Name: "A",
+			SKU:  "TEST_REPO_VARIANTS_A",
+		})
+		assert.NoError(t, err)
+
+		var stored Variant
+		db.Where("sku = ?", "TEST_REPO_VARIANTS_A").First(&stored)
+		assert.False(t, stored.Price.Valid)
+		assert.True(t, stored.EffectivePrice(mustDecimal("3.00")).Equal(mustDecimal("3.00")))
+	})
+
+	t.Run("returns ErrVariantNotFound for SKU of another product", func(t *testing.T) {
+		err := repo.DeleteVariant("TEST_REPO_VARIANTS", "SKU001A")
+
+		assert.ErrorIs(t, err, ErrVariantNotFound)
+	})
+
+	t.Run("deletes variant", func(t *testing.T) {
+		err := repo.DeleteVariant("TEST_REPO_VARIANTS", "TEST_REPO_VARIANTS_A")
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestGetProductsWithFilters_Search(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewProductsRepository(db)
+
+	t.Run("finds product by its code", func(t *testing.T) {
+		products, total, err := repo.GetProductsWithFilters(ProductFilters{Limit: 10, Query: "PROD003"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, "PROD003", products[0].Code)
+	})
+
+	t.Run("ranks code matches before category matches", func(t *testing.T) {
+		products, _, err := repo.GetProductsWithFilters(ProductFilters{Limit: 10, Query: "PROD002 or shoes"})
+
+		assert.NoError(t, err)
+		if assert.NotEmpty(t, products) {
+			assert.Equal(t, "PROD002", products[0].Code)
+		}
+	})
+
+	t.Run("keeps search vector in sync with new variants", func(t *testing.T) {
+		db.Where("code = ?", "TEST_SEARCH").Delete(&Product{})
+		cleanupProduct(t, db, "TEST_SEARCH")
+		assert.NoError(t, repo.CreateProduct(&Product{
+			Code:     "TEST_SEARCH",
+			Price:    mustDecimal("1.00"),
+			Category: Category{Code: "SHOES"},
+		}))
+		assert.NoError(t, repo.CreateVariant("TEST_SEARCH", &Variant{Name: "Zebra pattern", SKU: "TEST_SEARCH_Z"}))
+
+		products, total, err := repo.GetProductsWithFilters(ProductFilters{Limit: 10, Query: "zebra"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, "TEST_SEARCH", products[0].Code)
+	})
+}
+
+func TestGetProductsWithFilters_Extended(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewProductsRepository(db)
+
+	t.Run("filters by multiple categories", func(t *testing.T) {
+		products, total, err := repo.GetProductsWithFilters(ProductFilters{
+			Limit:         100,
+			CategoryCodes: []string{"SHOES", "ACCESSORIES"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(products)), total)
+		assert.Greater(t, total, int64(0))
+		for _, p := range products {
+			assert.Contains(t, []string{"SHOES", "ACCESSORIES"}, p.Category.Code)
+		}
+	})
+
+	t.Run("filters by inclusive price range", func(t *testing.T) {
+		low, high := mustDecimal("10.99"), mustDecimal("15.00")
+		products, _, err := repo.GetProductsWithFilters(ProductFilters{
+			Limit:            100,
+			PriceGreaterThan: &low,
+			PriceLessThan:    &high,
+			PriceInclusive:   true,
+		})
+
+		assert.NoError(t, err)
+		codes := make(map[string]bool)
+		for _, p := range products {
+			codes[p.Code] = true
+			assert.True(t, p.Price.GreaterThanOrEqual(low) && p.Price.LessThanOrEqual(high))
+		}
+		assert.True(t, codes["PROD001"], "Lower bound should be inclusive")
+		assert.True(t, codes["PROD004"], "Upper bound should be inclusive")
+	})
+
+	t.Run("filters by effective variant price", func(t *testing.T) {
+		// PROD004 costs 15.00 but has a variant inheriting it, so it only
+		// matches the variant mode when the bound is above 15.00
+		limit := mustDecimal("15.01")
+		products, _, err := repo.GetProductsWithFilters(ProductFilters{
+			Limit:         100,
+			PriceLessThan: &limit,
+			PriceMode:     PriceModeVariant,
+		})
+
+		assert.NoError(t, err)
+		for _, p := range products {
+			matched := len(p.Variants) == 0 && p.Price.LessThan(limit)
+			for _, v := range p.Variants {
+				matched = matched || v.EffectivePrice(p.Price).LessThan(limit)
+			}
+			assert.True(t, matched, "Product %s should have an effective price below the limit", p.Code)
+		}
+	})
+
+	t.Run("filters by variant presence", func(t *testing.T) {
+		without := false
+		products, _, err := repo.GetProductsWithFilters(ProductFilters{Limit: 100, HasVariants: &without})
+
+		assert.NoError(t, err)
+		codes := make(map[string]bool)
+		for _, p := range products {
+			codes[p.Code] = true
+			assert.Empty(t, p.Variants)
+		}
+		assert.True(t, codes["PROD006"], "PROD006 has no variants")
+	})
+}
+
+func TestGetProductsWithFilters_Sort(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewProductsRepository(db)
+
+	t.Run("sorts by price ascending", func(t *testing.T) {
+		products, _, err := repo.GetProductsWithFilters(ProductFilters{Limit: 100, Sort: "price"})
+
+		assert.NoError(t, err)
+		for i := 1; i < len(products); i++ {
+			assert.True(t, products[i-1].Price.LessThanOrEqual(products[i].Price))
+		}
+	})
+
+	t.Run("sorts by variant count descending", func(t *testing.T) {
+		products, _, err := repo.GetProductsWithFilters(ProductFilters{Limit: 100, Sort: "-variant_count"})
+
+		assert.NoError(t, err)
+		for i := 1; i < len(products); i++ {
+			assert.GreaterOrEqual(t, len(products[i-1].Variants), len(products[i].Variants))
+		}
+	})
+
+	t.Run("pages do not overlap", func(t *testing.T) {
+		first, _, err := repo.GetProductsWithFilters(ProductFilters{Limit: 3, Sort: "code"})
+		assert.NoError(t, err)
+		second, _, err := repo.GetProductsWithFilters(ProductFilters{Offset: 3, Limit: 3, Sort: "code"})
+		assert.NoError(t, err)
+
+		seen := make(map[string]bool)
+		for _, p := range first {
+			seen[p.Code] = true
+		}
+		for _, p := range second {
+			assert.False(t, seen[p.Code], "Product %s returned on two pages", p.Code)
+		}
+	})
+
+	t.Run("returns ErrInvalidSort for unknown key", func(t *testing.T) {
+		products, total, err := repo.GetProductsWithFilters(ProductFilters{Limit: 10, Sort: "name; DROP TABLE products"})
+
+		assert.ErrorIs(t, err, ErrInvalidSort)
+		assert.Nil(t, products)
+		assert.Equal(t, int64(0), total)
+	})
+}
+
+func TestGetProductsPage(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewProductsRepository(db)
+
+	collect := func(t *testing.T, filters ProductFilters) []string {
+		var codes []string
+		for {
+			page, err := repo.GetProductsPage(filters)
+			if !assert.NoError(t, err) {
+				return codes
+			}
+			for _, p := range page.Products {
+				codes = append(codes, p.Code)
+			}
+			if page.Next == nil {
+				return codes
+			}
+			filters.After = page.Next
+		}
+	}
+
+	t.Run("walks all products forward without gaps or duplicates", func(t *testing.T) {
+		all, total, err := repo.GetProductsWithFilters(ProductFilters{Limit: 100, Sort: "-price"})
+		assert.NoError(t, err)
+
+		codes := collect(t, ProductFilters{Limit: 3, Sort: "-price"})
+
+		assert.Len(t, codes, int(total))
+		for i, p := range all {
+			assert.Equal(t, p.Code, codes[i])
+		}
+	})
+
+	t.Run("walks backwards from a cursor", func(t *testing.T) {
+		first, err := repo.GetProductsPage(ProductFilters{Limit: 2, Sort: "code"})
+		assert.NoError(t, err)
+		assert.Nil(t, first.Prev, "First page should not have a previous cursor")
+		second, err := repo.GetProductsPage(ProductFilters{Limit: 2, Sort: "code", After: first.Next})
+		assert.NoError(t, err)
+
+		back, err := repo.GetProductsPage(ProductFilters{Limit: 2, Sort: "code", Before: second.Prev})
+
+		assert.NoError(t, err)
+		assert.Equal(t, first.Products[0].Code, back.Products[0].Code)
+		assert.Equal(t, first.Products[1].Code, back.Products[1].Code)
+		assert.NotNil(t, back.Next)
+	})
+
+	t.Run("rejects a cursor issued for another sort", func(t *testing.T) {
+		first, err := repo.GetProductsPage(ProductFilters{Limit: 2, Sort: "code"})
+		assert.NoError(t, err)
+
+		_, err = repo.GetProductsPage(ProductFilters{Limit: 2, Sort: "price", After: first.Next})
+
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+
+	t.Run("rejects relevance ordered search", func(t *testing.T) {
+		_, err := repo.GetProductsPage(ProductFilters{Limit: 2, Query: "shoes"})
+
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
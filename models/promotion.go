@@ -0,0 +1,94 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Promotion discount types
+const (
+	DiscountPercentage = "percentage"
+	DiscountFixed      = "fixed"
+)
+
+// hundred converts percentages to factors
+var hundred = decimal.NewFromInt(100)
+
+// activePromotionsSQL restricts loaded promotions to the ones active at the
+// database clock
+const activePromotionsSQL = "(promotions.valid_from IS NULL OR promotions.valid_from <= NOW()) AND (promotions.valid_to IS NULL OR promotions.valid_to > NOW())"
+
+// Promotion is a discount rule targeting a product or a category, including
+// its subcategories. Value is a percentage for DiscountPercentage and a base
+// currency amount for DiscountFixed. Nil validity bounds leave the window open.
+type Promotion struct {
+	ID           uint            `gorm:"primaryKey"`
+	Code         string          `gorm:"uniqueIndex;not null"`
+	Name         string          `gorm:"not null"`
+	DiscountType string          `gorm:"not null"`
+	Value        decimal.Decimal `gorm:"type:decimal(10,2);not null"`
+	ProductID    *uint           `gorm:"index"`
+	Product      *Product        `gorm:"foreignKey:ProductID"`
+	CategoryID   *uint           `gorm:"index"`
+	Category     *Category       `gorm:"foreignKey:CategoryID"`
+	Priority     int             `gorm:"not null"`
+	Stackable    bool            `gorm:"not null"`
+	ValidFrom    *time.Time
+	ValidTo      *time.Time
+}
+
+func (p *Promotion) TableName() string {
+	return "promotions"
+}
+
+// ActiveAt reports whether the promotion applies at t
+func (p *Promotion) ActiveAt(t time.Time) bool {
+	return (p.ValidFrom == nil || !t.Before(*p.ValidFrom)) && (p.ValidTo == nil || t.Before(*p.ValidTo))
+}
+
+// Apply discounts amount. fixed is the fixed discount expressed in the
+// currency of amount. The result never drops below zero.
+func (p *Promotion) Apply(amount, fixed decimal.Decimal) decimal.Decimal {
+	var discounted decimal.Decimal
+	if p.DiscountType == DiscountPercentage {
+		discounted = amount.Sub(amount.Mul(p.Value).Div(hundred))
+	} else {
+		discounted = amount.Sub(fixed)
+	}
+	if discounted.IsNegative() {
+		return decimal.Zero
+	}
+	return discounted
+}
+
+// ApplicablePromotions returns the promotions loaded with the product that
+// are active at t, in the order they apply. The promotion with the highest
+// priority always applies; the next ones only apply while it and every
+// promotion applied before are stackable. Ties are broken by ID.
+func (p *Product) ApplicablePromotions(at time.Time) []Promotion {
+	active := make([]Promotion, 0, len(p.Promotions))
+	for _, promotion := range p.Promotions {
+		if promotion.ActiveAt(at) {
+			active = append(active, promotion)
+		}
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		if active[i].Priority != active[j].Priority {
+			return active[i].Priority > active[j].Priority
+		}
+		return active[i].ID < active[j].ID
+	})
+
+	if len(active) == 0 || !active[0].Stackable {
+		return active[:min(len(active), 1)]
+	}
+	applied := []Promotion{active[0]}
+	for _, promotion := range active[1:] {
+		if promotion.Stackable {
+			applied = append(applied, promotion)
+		}
+	}
+	return applied
+}
@@ -0,0 +1,226 @@
+package models
+
+import (
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// PromotionRepository defines the interface for promotion data access
+type PromotionRepository interface {
+	GetAllPromotions() ([]Promotion, error)
+	GetPromotionByCode(code string) (*Promotion, error)
+	CreatePromotion(promotion *Promotion) error
+	UpdatePromotion(code string, promotion *Promotion) error
+	DeletePromotion(code string) error
+}
+
+type PromotionsRepository struct {
+	db *gorm.DB
+}
+
+func NewPromotionsRepository(db *gorm.DB) *PromotionsRepository {
+	return &PromotionsRepository{db: db}
+}
+
+// GetAllPromotions retrieves all promotions, including inactive ones, by
+// descending priority
+func (r *PromotionsRepository) GetAllPromotions() ([]Promotion, error) {
+	var promotions []Promotion
+	if err := r.db.Preload("Product").Preload("Category").
+		Order("priority DESC, id").Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// GetPromotionByCode retrieves a single promotion by its code
+func (r *PromotionsRepository) GetPromotionByCode(code string) (*Promotion, error) {
+	return findPromotionByCode(r.db.Preload("Product").Preload("Category"), code)
+}
+
+// CreatePromotion inserts a promotion. Its target is resolved from
+// promotion.Product.Code or promotion.Category.Code.
+func (r *PromotionsRepository) CreatePromotion(promotion *Promotion) error {
+	if err := validatePromotion(promotion); err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := resolvePromotionTarget(tx, promotion); err != nil {
+			return err
+		}
+		promotion.ID = 0
+		if err := tx.Omit("Product", "Category").Create(promotion).Error; err != nil {
+			return mapPromotionWriteError(err)
+		}
+		return nil
+	})
+}
+
+// UpdatePromotion overwrites the promotion identified by code
+func (r *PromotionsRepository) UpdatePromotion(code string, promotion *Promotion) error {
+	if err := validatePromotion(promotion); err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		existing, err := findPromotionByCode(tx, code)
+		if err != nil {
+			return err
+		}
+		if err := resolvePromotionTarget(tx, promotion); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&Promotion{}).Where("id = ?", existing.ID).Updates(map[string]any{
+			"code":          promotion.Code,
+			"name":          promotion.Name,
+			"discount_type": promotion.DiscountType,
+			"value":         promotion.Value,
+			"product_id":    promotion.ProductID,
+			"category_id":   promotion.CategoryID,
+			"priority":      promotion.Priority,
+			"stackable":     promotion.Stackable,
+			"valid_from":    promotion.ValidFrom,
+			"valid_to":      promotion.ValidTo,
+			"updated_at":    gorm.Expr("NOW()"),
+		}).Error; err != nil {
+			return mapPromotionWriteError(err)
+		}
+
+		promotion.ID = existing.ID
+		return nil
+	})
+}
+
+// DeletePromotion removes the promotion identified by code
+func (r *PromotionsRepository) DeletePromotion(code string) error {
+	result := r.db.Where("code = ?", code).Delete(&Promotion{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPromotionNotFound
+	}
+	return nil
+}
+
+// findPromotionByCode looks up a promotion by its code
+func findPromotionByCode(tx *gorm.DB, code string) (*Promotion, error) {
+	var promotion Promotion
+	if err := tx.Where("code = ?", code).First(&promotion).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPromotionNotFound
+		}
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// validatePromotion performs the minimal checks required before writing a
+// promotion. The database constraints enforce the same rules.
+func validatePromotion(promotion *Promotion) error {
+	if promotion == nil || strings.TrimSpace(promotion.Code) == "" || strings.TrimSpace(promotion.Name) == "" {
+		return ErrInvalidPromotion
+	}
+	switch promotion.DiscountType {
+	case DiscountPercentage:
+		if promotion.Value.GreaterThan(hundred) {
+			return ErrInvalidPromotion
+		}
+	case DiscountFixed:
+	default:
+		return ErrInvalidPromotion
+	}
+	if !promotion.Value.IsPositive() {
+		return ErrInvalidPromotion
+	}
+	if (promotion.Product == nil) == (promotion.Category == nil) {
+		return ErrInvalidPromotion
+	}
+	if promotion.ValidFrom != nil && promotion.ValidTo != nil && !promotion.ValidTo.After(*promotion.ValidFrom) {
+		return ErrInvalidPromotion
+	}
+	return nil
+}
+
+// resolvePromotionTarget sets ProductID or CategoryID from the code of the
+// targeted product or category
+func resolvePromotionTarget(tx *gorm.DB, promotion *Promotion) error {
+	promotion.ProductID, promotion.CategoryID = nil, nil
+	if promotion.Product != nil {
+		product, err := findProductByCode(tx, promotion.Product.Code)
+		if err != nil {
+			return err
+		}
+		promotion.ProductID = &product.ID
+		promotion.Product = product
+		return nil
+	}
+
+	category, err := findCategoryByCode(tx, promotion.Category.Code)
+	if err != nil {
+		return err
+	}
+	promotion.CategoryID = &category.ID
+	promotion.Category = category
+	return nil
+}
+
+// mapPromotionWriteError translates unique violations into domain errors
+func mapPromotionWriteError(err error) error {
+	if _, ok := uniqueViolation(err); ok {
+		return ErrPromotionCodeExists
+	}
+	if _, ok := checkViolation(err); ok {
+		return ErrInvalidPromotion
+	}
+	return err
+}
+
+// attachPromotions loads the active promotions targeting each product, its
+// category or one of the ancestors of its category
+func attachPromotions(tx *gorm.DB, products []Product, ancestry []categoryAncestor) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	productIDs := make([]uint, len(products))
+	for i, p := range products {
+		productIDs[i] = p.ID
+	}
+	ancestorIDs := make([]uint, len(ancestry))
+	for i, a := range ancestry {
+		ancestorIDs[i] = a.AncestorID
+	}
+
+	var promotions []Promotion
+	if err := tx.Where(activePromotionsSQL).
+		Where("product_id IN ? OR category_id IN ?", productIDs, ancestorIDs).
+		Find(&promotions).Error; err != nil {
+		return err
+	}
+
+	byProduct := make(map[uint][]Promotion)
+	byCategory := make(map[uint][]Promotion)
+	for _, promotion := range promotions {
+		if promotion.ProductID != nil {
+			byProduct[*promotion.ProductID] = append(byProduct[*promotion.ProductID], promotion)
+		}
+		if promotion.CategoryID != nil {
+			byCategory[*promotion.CategoryID] = append(byCategory[*promotion.CategoryID], promotion)
+		}
+	}
+	inherited := make(map[uint][]Promotion)
+	for _, a := range ancestry {
+		inherited[a.CategoryID] = append(inherited[a.CategoryID], byCategory[a.AncestorID]...)
+	}
+
+	for i := range products {
+		p := &products[i]
+		p.Promotions = append(append([]Promotion(nil), byProduct[p.ID]...), inherited[p.CategoryID]...)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromotion_Apply(t *testing.T) {
+	percentage := &Promotion{DiscountType: DiscountPercentage, Value: mustDecimal("20")}
+	fixed := &Promotion{DiscountType: DiscountFixed, Value: mustDecimal("5")}
+
+	assert.Equal(t, "40", percentage.Apply(mustDecimal("50.00"), fixed.Value).String())
+	assert.Equal(t, "45", fixed.Apply(mustDecimal("50.00"), mustDecimal("5")).String())
+	assert.True(t, fixed.Apply(mustDecimal("3.00"), mustDecimal("5")).IsZero(), "never below zero")
+}
+
+func TestProduct_ApplicablePromotions(t *testing.T) {
+	now := time.Date(2025, 11, 28, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+
+	codes := func(promotions []Promotion) []string {
+		result := make([]string, len(promotions))
+		for i, p := range promotions {
+			result[i] = p.Code
+		}
+		return result
+	}
+
+	t.Run("exclusive promotion with the highest priority wins", func(t *testing.T) {
+		product := &Product{Promotions: []Promotion{
+			{ID: 1, Code: "LOW", Priority: 1, Stackable: true},
+			{ID: 2, Code: "HIGH", Priority: 5},
+		}}
+
+		assert.Equal(t, []string{"HIGH"}, codes(product.ApplicablePromotions(now)))
+	})
+
+	t.Run("stackable promotions combine and skip exclusive ones", func(t *testing.T) {
+		product := &Product{Promotions: []Promotion{
+			{ID: 3, Code: "C", Priority: 1, Stackable: true},
+			{ID: 2, Code: "EXCLUSIVE", Priority: 2},
+			{ID: 1, Code: "A", Priority: 3, Stackable: true},
+			{ID: 4, Code: "B", Priority: 1, Stackable: true},
+		}}
+
+		assert.Equal(t, []string{"A", "C", "B"}, codes(product.ApplicablePromotions(now)))
+	})
+
+	t.Run("inactive promotions are ignored", func(t *testing.T) {
+		product := &Product{Promotions: []Promotion{
+			{ID: 1, Code: "FUTURE", Priority: 9, ValidFrom: &later},
+			{ID: 2, Code: "ENDED", Priority: 8, ValidTo: &now},
+			{ID: 3, Code: "NOW", Priority: 1},
+		}}
+
+		assert.Equal(t, []string{"NOW"}, codes(product.ApplicablePromotions(now)))
+	})
+
+	t.Run("no promotions", func(t *testing.T) {
+		assert.Empty(t, (&Product{}).ApplicablePromotions(now))
+	})
+}
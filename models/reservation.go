@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+)
+
+// Reservation statuses. Only pending reservations hold stock.
+const (
+	ReservationPending   = "pending"
+	ReservationConfirmed = "confirmed"
+	ReservationReleased  = "released"
+	ReservationExpired   = "expired"
+)
+
+// Reservation holds units of stock for a checkout until it is confirmed,
+// released or expires.
+type Reservation struct {
+	ID        uint              `gorm:"primaryKey"`
+	Status    string            `gorm:"not null"`
+	ExpiresAt time.Time         `gorm:"not null"`
+	Items     []ReservationItem `gorm:"foreignKey:ReservationID"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (r *Reservation) TableName() string {
+	return "reservations"
+}
+
+// ReservationItem is a quantity of a SKU held in one warehouse
+type ReservationItem struct {
+	ID            uint       `gorm:"primaryKey"`
+	ReservationID uint       `gorm:"not null"`
+	SKU           string     `gorm:"not null"`
+	WarehouseID   uint       `gorm:"not null"`
+	Warehouse     *Warehouse `gorm:"foreignKey:WarehouseID"`
+	Quantity      int        `gorm:"not null"`
+}
+
+func (i *ReservationItem) TableName() string {
+	return "reservation_items"
+}
+
+// ReservationRequest asks for a quantity of a SKU, wherever it is in stock
+type ReservationRequest struct {
+	SKU      string
+	Quantity int
+}
@@ -0,0 +1,299 @@
+package models
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReservationRepository defines the interface for reservation data access
+type ReservationRepository interface {
+	CreateReservation(requests []ReservationRequest, expiresAt time.Time) (*Reservation, error)
+	GetReservation(id uint) (*Reservation, error)
+	ConfirmReservation(id uint, now time.Time) (*Reservation, error)
+	ReleaseReservation(id uint) (*Reservation, error)
+	ExpireReservations(now time.Time) (int, error)
+}
+
+type ReservationsRepository struct {
+	db *gorm.DB
+}
+
+func NewReservationsRepository(db *gorm.DB) *ReservationsRepository {
+	return &ReservationsRepository{
+		db: db,
+	}
+}
+
+// expireBatchSize bounds the reservations expired by one ExpireReservations call
+const expireBatchSize = 100
+
+// CreateReservation holds the requested quantities until expiresAt. Each SKU
+// is allocated from the active warehouses, the default one first. The stock
+// rows are locked with SELECT ... FOR UPDATE in SKU order, so concurrent
+// reservations serialize per SKU and never oversell. It fails with
+// ErrInsufficientStock, leaving stock untouched, when any SKU is short.
+func (r *ReservationsRepository) CreateReservation(requests []ReservationRequest, expiresAt time.Time) (*Reservation, error) {
+	quantities, err := mergeReservationRequests(requests)
+	if err != nil {
+		return nil, err
+	}
+
+	skus := make([]string, 0, len(quantities))
+	for sku := range quantities {
+		skus = append(skus, sku)
+	}
+	sort.Strings(skus)
+
+	reservation := &Reservation{Status: ReservationPending, ExpiresAt: expiresAt}
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		// The share lock keeps the variants from being renamed or deleted
+		// until the reservation is committed, see lockUnreservedVariants
+		var ids []uint
+		if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+			Model(&Variant{}).Where("sku IN ?", skus).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) != len(skus) {
+			return ErrVariantNotFound
+		}
+
+		for _, sku := range skus {
+			items, err := allocateStock(tx, sku, quantities[sku])
+			if err != nil {
+				return err
+			}
+			reservation.Items = append(reservation.Items, items...)
+		}
+
+		if err := tx.Omit("Items").Create(reservation).Error; err != nil {
+			return err
+		}
+		for i := range reservation.Items {
+			reservation.Items[i].ReservationID = reservation.ID
+		}
+		return tx.Omit("Warehouse").Create(&reservation.Items).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// GetReservation retrieves a reservation with its items and their warehouses
+func (r *ReservationsRepository) GetReservation(id uint) (*Reservation, error) {
+	var reservation Reservation
+	if err := r.db.Preload("Items.Warehouse").First(&reservation, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReservationNotFound
+		}
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// ConfirmReservation turns the held units into a sale: they leave both the
+// reserved and the on hand stock. Expired reservations cannot be confirmed,
+// even before the sweeper has released them.
+func (r *ReservationsRepository) ConfirmReservation(id uint, now time.Time) (*Reservation, error) {
+	return r.settleReservation(id, func(reservation *Reservation) (string, error) {
+		if !now.Before(reservation.ExpiresAt) {
+			return "", ErrReservationExpired
+		}
+		return ReservationConfirmed, nil
+	})
+}
+
+// ReleaseReservation returns the held units to the available stock
+func (r *ReservationsRepository) ReleaseReservation(id uint) (*Reservation, error) {
+	return r.settleReservation(id, func(*Reservation) (string, error) {
+		return ReservationReleased, nil
+	})
+}
+
+// ExpireReservations releases pending reservations that expired before now
+// and returns how many were expired. Reservations locked by a concurrent
+// confirm or release are skipped and picked up by a later call.
+// Reservations whose stock rows are gone stay pending, see applySettlement.
+func (r *ReservationsRepository) ExpireReservations(now time.Time) (int, error) {
+	expired := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var reservations []Reservation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Preload("Items").
+			Where("status = ? AND expires_at <= ?", ReservationPending, now).
+			Order("expires_at").Limit(expireBatchSize).
+			Find(&reservations).Error; err != nil {
+			return err
+		}
+
+		for i := range reservations {
+			// A savepoint per reservation, so one that cannot be settled
+			// does not hold back the rest of the batch
+			err := tx.Transaction(func(tx *gorm.DB) error {
+				return applySettlement(tx, &reservations[i], ReservationExpired)
+			})
+			if errors.Is(err, ErrReservedStockMissing) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			expired++
+		}
+		return nil
+	})
+	return expired, err
+}
+
+// settleReservation locks a pending reservation and moves it to the status
+// returned by decide, updating the held stock accordingly
+func (r *ReservationsRepository) settleReservation(id uint, decide func(*Reservation) (string, error)) (*Reservation, error) {
+	var reservation Reservation
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&reservation, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrReservationNotFound
+			}
+			return err
+		}
+		if reservation.Status != ReservationPending {
+			return ErrReservationNotPending
+		}
+
+		status, err := decide(&reservation)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Preload("Warehouse").Where("reservation_id = ?", id).Find(&reservation.Items).Error; err != nil {
+			return err
+		}
+		return applySettlement(tx, &reservation, status)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// applySettlement releases the reserved units of a locked reservation and,
+// when it is confirmed, removes them from the stock on hand. It fails with
+// ErrReservedStockMissing when the stock row of an item is gone, rather than
+// settling a reservation whose units were never released.
+func applySettlement(tx *gorm.DB, reservation *Reservation, status string) error {
+	for _, item := range reservation.Items {
+		updates := map[string]any{
+			"reserved":   gorm.Expr("reserved - ?", item.Quantity),
+			"updated_at": gorm.Expr("NOW()"),
+		}
+		if status == ReservationConfirmed {
+			updates["on_hand"] = gorm.Expr("on_hand - ?", item.Quantity)
+		}
+		result := tx.Model(&StockLevel{}).
+			Where("sku = ? AND warehouse_id = ?", item.SKU, item.WarehouseID).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrReservedStockMissing
+		}
+	}
+
+	reservation.Status = status
+	return tx.Model(&Reservation{}).Where("id = ?", reservation.ID).Updates(map[string]any{
+		"status":     status,
+		"updated_at": gorm.Expr("NOW()"),
+	}).Error
+}
+
+// allocateStock locks the stock rows of sku in the active warehouses and
+// reserves quantity from them, default warehouse first
+func allocateStock(tx *gorm.DB, sku string, quantity int) ([]ReservationItem, error) {
+	var levels []StockLevel
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "stock_levels"}}).
+		InnerJoins("Warehouse").
+		Where("stock_levels.sku = ? AND \"Warehouse\".active", sku).
+		Order("\"Warehouse\".is_default DESC, \"Warehouse\".code").
+		Find(&levels).Error; err != nil {
+		return nil, err
+	}
+
+	var items []ReservationItem
+	remaining := quantity
+	for _, level := range levels {
+		take := min(level.Available(), remaining)
+		if take <= 0 {
+			continue
+		}
+
+		if err := tx.Model(&StockLevel{}).
+			Where("sku = ? AND warehouse_id = ?", sku, level.WarehouseID).
+			Updates(map[string]any{
+				"reserved":   gorm.Expr("reserved + ?", take),
+				"updated_at": gorm.Expr("NOW()"),
+			}).Error; err != nil {
+			return nil, err
+		}
+
+		items = append(items, ReservationItem{
+			SKU:         sku,
+			WarehouseID: level.WarehouseID,
+			Warehouse:   level.Warehouse,
+			Quantity:    take,
+		})
+		if remaining -= take; remaining == 0 {
+			return items, nil
+		}
+	}
+
+	return nil, &StockShortageError{SKU: sku}
+}
+
+// lockUnreservedVariants locks the variants with the given SKUs and fails
+// with ErrVariantReserved when a pending reservation holds any of them.
+// Reservation items refer to their stock rows by SKU, so a held variant
+// must keep its SKU until the reservation is settled.
+func lockUnreservedVariants(tx *gorm.DB, skus []string) error {
+	if len(skus) == 0 {
+		return nil
+	}
+
+	var ids []uint
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Model(&Variant{}).Where("sku IN ?", skus).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+
+	var held int64
+	if err := tx.Model(&ReservationItem{}).
+		Joins("JOIN reservations ON reservations.id = reservation_items.reservation_id").
+		Where("reservations.status = ? AND reservation_items.sku IN ?", ReservationPending, skus).
+		Count(&held).Error; err != nil {
+		return err
+	}
+	if held > 0 {
+		return ErrVariantReserved
+	}
+	return nil
+}
+
+// mergeReservationRequests sums the requested quantities per SKU
+func mergeReservationRequests(requests []ReservationRequest) (map[string]int, error) {
+	if len(requests) == 0 {
+		return nil, ErrInvalidReservation
+	}
+
+	quantities := make(map[string]int, len(requests))
+	for _, req := range requests {
+		if req.SKU == "" || req.Quantity <= 0 {
+			return nil, ErrInvalidReservation
+		}
+		quantities[req.SKU] += req.Quantity
+	}
+	return quantities, nil
+}
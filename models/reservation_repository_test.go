@@ -0,0 +1,173 @@
+package models
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// createReservableVariant creates a product with one variant holding onHand
+// units in the default warehouse
+func createReservableVariant(t *testing.T, db *gorm.DB, code, sku string, onHand int) {
+	db.Where("code = ?", code).Delete(&Product{})
+	cleanupProduct(t, db, code)
+
+	product := &Product{
+		Code:     code,
+		Price:    mustDecimal("10.00"),
+		Category: Category{Code: "SHOES"},
+		Variants: []Variant{{Name: "One size", SKU: sku}},
+	}
+	if err := NewProductsRepository(db).CreateProduct(product); err != nil {
+		t.Fatalf("creating product: %v", err)
+	}
+	if _, err := NewStockLevelsRepository(db).SetStock(sku, "", onHand); err != nil {
+		t.Fatalf("setting stock: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Where("id IN (?)", db.Model(&ReservationItem{}).Select("reservation_id").Where("sku = ?", sku)).
+			Delete(&Reservation{})
+	})
+}
+
+// variantStock reads the aggregated stock of sku
+func variantStock(db *gorm.DB, sku string) *VariantStock {
+	var stock VariantStock
+	db.Where("sku = ?", sku).First(&stock)
+	return &stock
+}
+
+func TestReservationsRepository_Lifecycle(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewReservationsRepository(db)
+	createReservableVariant(t, db, "TEST_RES", "TEST_RES_SKU", 5)
+	expiresAt := time.Now().UTC().Add(time.Minute)
+
+	t.Run("reserving holds units", func(t *testing.T) {
+		reservation, err := repo.CreateReservation([]ReservationRequest{{SKU: "TEST_RES_SKU", Quantity: 2}}, expiresAt)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ReservationPending, reservation.Status)
+		assert.Equal(t, 3, variantStock(db, "TEST_RES_SKU").Available())
+
+		t.Run("releasing returns them", func(t *testing.T) {
+			released, err := repo.ReleaseReservation(reservation.ID)
+
+			assert.NoError(t, err)
+			assert.Equal(t, ReservationReleased, released.Status)
+			assert.Equal(t, 5, variantStock(db, "TEST_RES_SKU").Available())
+
+			_, err = repo.ReleaseReservation(reservation.ID)
+			assert.ErrorIs(t, err, ErrReservationNotPending)
+		})
+	})
+
+	t.Run("confirming removes units from stock on hand", func(t *testing.T) {
+		reservation, err := repo.CreateReservation([]ReservationRequest{{SKU: "TEST_RES_SKU", Quantity: 1}}, expiresAt)
+		assert.NoError(t, err)
+
+		confirmed, err := repo.ConfirmReservation(reservation.ID, time.Now().UTC())
+
+		assert.NoError(t, err)
+		assert.Equal(t, ReservationConfirmed, confirmed.Status)
+		stock := variantStock(db, "TEST_RES_SKU")
+		assert.Equal(t, 4, stock.OnHand)
+		assert.Equal(t, 0, stock.Reserved)
+	})
+
+	t.Run("expired reservations cannot be confirmed and are swept", func(t *testing.T) {
+		reservation, err := repo.CreateReservation([]ReservationRequest{{SKU: "TEST_RES_SKU", Quantity: 1}}, expiresAt)
+		assert.NoError(t, err)
+		later := expiresAt.Add(time.Second)
+
+		_, err = repo.ConfirmReservation(reservation.ID, later)
+		assert.ErrorIs(t, err, ErrReservationExpired)
+
+		expired, err := repo.ExpireReservations(later)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, expired, 1)
+
+		stored, err := repo.GetReservation(reservation.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, ReservationExpired, stored.Status)
+		assert.Equal(t, 0, variantStock(db, "TEST_RES_SKU").Reserved)
+	})
+
+	t.Run("fails without touching stock when short", func(t *testing.T) {
+		_, err := repo.CreateReservation([]ReservationRequest{{SKU: "TEST_RES_SKU", Quantity: 99}}, expiresAt)
+
+		assert.ErrorIs(t, err, ErrInsufficientStock)
+		assert.Equal(t, 0, variantStock(db, "TEST_RES_SKU").Reserved)
+	})
+
+	t.Run("fails for unknown SKU", func(t *testing.T) {
+		_, err := repo.CreateReservation([]ReservationRequest{{SKU: "UNKNOWN_SKU", Quantity: 1}}, expiresAt)
+
+		assert.ErrorIs(t, err, ErrVariantNotFound)
+	})
+}
+
+func TestReservationsRepository_HeldVariants(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewReservationsRepository(db)
+	products := NewProductsRepository(db)
+	createReservableVariant(t, db, "TEST_RES_HELD", "TEST_RES_HELD_SKU", 2)
+
+	reservation, err := repo.CreateReservation([]ReservationRequest{{SKU: "TEST_RES_HELD_SKU", Quantity: 1}}, time.Now().UTC().Add(time.Minute))
+	assert.NoError(t, err)
+
+	t.Run("held variants keep their SKU", func(t *testing.T) {
+		err := products.UpdateVariant("TEST_RES_HELD", "TEST_RES_HELD_SKU", &Variant{Name: "One size", SKU: "TEST_RES_HELD_NEW"})
+		assert.ErrorIs(t, err, ErrVariantReserved)
+		assert.ErrorIs(t, products.DeleteVariant("TEST_RES_HELD", "TEST_RES_HELD_SKU"), ErrVariantReserved)
+		assert.ErrorIs(t, products.DeleteProduct("TEST_RES_HELD"), ErrVariantReserved)
+
+		err = products.UpdateVariant("TEST_RES_HELD", "TEST_RES_HELD_SKU", &Variant{Name: "Renamed", SKU: "TEST_RES_HELD_SKU"})
+		assert.NoError(t, err, "other fields of a held variant can change")
+	})
+
+	t.Run("settling fails when the stock row is gone", func(t *testing.T) {
+		db.Where("sku = ?", "TEST_RES_HELD_SKU").Delete(&StockLevel{})
+
+		_, err := repo.ConfirmReservation(reservation.ID, time.Now().UTC())
+		assert.ErrorIs(t, err, ErrReservedStockMissing)
+
+		stored, err := repo.GetReservation(reservation.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, ReservationPending, stored.Status)
+	})
+}
+
+func TestReservationsRepository_ConcurrentReservationsNeverOversell(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewReservationsRepository(db)
+	createReservableVariant(t, db, "TEST_RES_RACE", "TEST_RES_RACE_SKU", 3)
+	expiresAt := time.Now().UTC().Add(time.Minute)
+
+	var wg sync.WaitGroup
+	results := make(chan error, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.CreateReservation([]ReservationRequest{{SKU: "TEST_RES_RACE_SKU", Quantity: 1}}, expiresAt)
+			results <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	succeeded := 0
+	for err := range results {
+		if err == nil {
+			succeeded++
+		} else {
+			assert.ErrorIs(t, err, ErrInsufficientStock)
+		}
+	}
+	assert.Equal(t, 3, succeeded)
+	assert.Equal(t, 0, variantStock(db, "TEST_RES_RACE_SKU").Available())
+}
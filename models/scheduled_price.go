@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// activeScheduledPricesSQL restricts preloaded scheduled prices to the ones
+// active at the database clock
+const activeScheduledPricesSQL = "valid_from <= NOW() AND (valid_to IS NULL OR valid_to > NOW())"
+
+// ScheduledPrice replaces the regular price of a product, or of a single
+// variant when VariantID is set, between ValidFrom and ValidTo (exclusive).
+// A nil ValidTo keeps the price until the entry is removed.
+type ScheduledPrice struct {
+	ID        uint            `gorm:"primaryKey"`
+	ProductID uint            `gorm:"not null"`
+	VariantID *uint           `gorm:"index"`
+	Variant   *Variant        `gorm:"foreignKey:VariantID"`
+	Price     decimal.Decimal `gorm:"type:decimal(10,2);not null"`
+	ValidFrom time.Time       `gorm:"not null"`
+	ValidTo   *time.Time
+}
+
+func (s *ScheduledPrice) TableName() string {
+	return "scheduled_prices"
+}
+
+// ActiveAt reports whether the scheduled price applies at t
+func (s *ScheduledPrice) ActiveAt(t time.Time) bool {
+	return !t.Before(s.ValidFrom) && (s.ValidTo == nil || t.Before(*s.ValidTo))
+}
+
+// SalePrice returns the scheduled product price active at t. When several
+// entries overlap, the one that started last wins.
+func (p *Product) SalePrice(at time.Time) (decimal.Decimal, bool) {
+	return latestActivePrice(p.ScheduledPrices, nil, at)
+}
+
+// VariantSalePrice returns the scheduled price of a variant of p active at t:
+// its own entry, or the product entry when the variant inherits the product price.
+func (p *Product) VariantSalePrice(v *Variant, at time.Time) (decimal.Decimal, bool) {
+	if price, ok := latestActivePrice(p.ScheduledPrices, &v.ID, at); ok {
+		return price, true
+	}
+	if !v.Price.Valid || v.Price.Decimal.IsZero() {
+		return p.SalePrice(at)
+	}
+	return decimal.Decimal{}, false
+}
+
+// latestActivePrice picks the active entry for variantID (nil for the
+// product) with the latest start
+func latestActivePrice(prices []ScheduledPrice, variantID *uint, at time.Time) (decimal.Decimal, bool) {
+	var latest *ScheduledPrice
+	for i := range prices {
+		s := &prices[i]
+		if (s.VariantID == nil) != (variantID == nil) || (variantID != nil && *s.VariantID != *variantID) {
+			continue
+		}
+		if s.ActiveAt(at) && (latest == nil || s.ValidFrom.After(latest.ValidFrom)) {
+			latest = s
+		}
+	}
+	if latest == nil {
+		return decimal.Decimal{}, false
+	}
+	return latest.Price, true
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduledPrice_ActiveAt(t *testing.T) {
+	from := time.Date(2025, 11, 28, 0, 0, 0, 0, time.UTC)
+	to := from.Add(72 * time.Hour)
+
+	bounded := &ScheduledPrice{ValidFrom: from, ValidTo: &to}
+	assert.False(t, bounded.ActiveAt(from.Add(-time.Second)))
+	assert.True(t, bounded.ActiveAt(from))
+	assert.True(t, bounded.ActiveAt(to.Add(-time.Second)))
+	assert.False(t, bounded.ActiveAt(to), "valid_to is exclusive")
+
+	openEnded := &ScheduledPrice{ValidFrom: from}
+	assert.True(t, openEnded.ActiveAt(from.AddDate(1, 0, 0)))
+}
+
+func TestProduct_SalePrice(t *testing.T) {
+	now := time.Date(2025, 11, 28, 12, 0, 0, 0, time.UTC)
+	variantID := uint(7)
+	product := &Product{
+		Price: mustDecimal("100.00"),
+		ScheduledPrices: []ScheduledPrice{
+			{Price: mustDecimal("80.00"), ValidFrom: now.Add(-48 * time.Hour)},
+			{Price: mustDecimal("70.00"), ValidFrom: now.Add(-time.Hour)},
+			{Price: mustDecimal("50.00"), ValidFrom: now.Add(time.Hour)},
+			{VariantID: &variantID, Price: mustDecimal("60.00"), ValidFrom: now.Add(-time.Hour)},
+		},
+	}
+
+	t.Run("overlapping entries resolve to the latest start", func(t *testing.T) {
+		price, ok := product.SalePrice(now)
+
+		assert.True(t, ok)
+		assert.Equal(t, "70", price.String())
+	})
+
+	t.Run("variant entry wins over the product entry", func(t *testing.T) {
+		price, ok := product.VariantSalePrice(&Variant{ID: variantID, Price: decimal.NewNullDecimal(mustDecimal("110.00"))}, now)
+
+		assert.True(t, ok)
+		assert.Equal(t, "60", price.String())
+	})
+
+	t.Run("inheriting variant uses the product entry", func(t *testing.T) {
+		price, ok := product.VariantSalePrice(&Variant{ID: 8}, now)
+
+		assert.True(t, ok)
+		assert.Equal(t, "70", price.String())
+	})
+
+	t.Run("variant with its own price ignores the product entry", func(t *testing.T) {
+		_, ok := product.VariantSalePrice(&Variant{ID: 8, Price: decimal.NewNullDecimal(mustDecimal("110.00"))}, now)
+
+		assert.False(t, ok)
+	})
+
+	t.Run("no active entry", func(t *testing.T) {
+		_, ok := product.SalePrice(now.Add(-72 * time.Hour))
+
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+)
+
+// StockLevel represents the stock of a variant SKU in a warehouse.
+// Reserved units are on hand but promised to pending orders.
+type StockLevel struct {
+	SKU         string     `gorm:"primaryKey"`
+	WarehouseID uint       `gorm:"primaryKey"`
+	Warehouse   *Warehouse `gorm:"foreignKey:WarehouseID"`
+	OnHand      int        `gorm:"not null"`
+	Reserved    int        `gorm:"not null"`
+	UpdatedAt   time.Time
+}
+
+func (s *StockLevel) TableName() string {
+	return "stock_levels"
+}
+
+// Available returns the units that can still be sold from the warehouse
+func (s *StockLevel) Available() int {
+	if s == nil {
+		return 0
+	}
+	return s.OnHand - s.Reserved
+}
+
+// VariantStock is the stock of a variant SKU aggregated over the active
+// warehouses. It is read from the variant_stock view.
+type VariantStock struct {
+	SKU      string `gorm:"primaryKey"`
+	OnHand   int
+	Reserved int
+}
+
+func (s *VariantStock) TableName() string {
+	return "variant_stock"
+}
+
+// Available returns the units that can still be sold
+func (s *VariantStock) Available() int {
+	if s == nil {
+		return 0
+	}
+	return s.OnHand - s.Reserved
+}
+
+// AggregateStock sums stock levels of a SKU over the active warehouses,
+// applying the same rule as the variant_stock view. Warehouses must be loaded.
+func AggregateStock(sku string, levels []StockLevel) VariantStock {
+	total := VariantStock{SKU: sku}
+	for _, level := range levels {
+		if level.Warehouse != nil && level.Warehouse.Active {
+			total.OnHand += level.OnHand
+			total.Reserved += level.Reserved
+		}
+	}
+	return total
+}
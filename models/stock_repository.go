@@ -0,0 +1,123 @@
+package models
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StockRepository defines the interface for stock data access
+type StockRepository interface {
+	GetWarehouses() ([]Warehouse, error)
+	GetStock(sku string) ([]StockLevel, error)
+	SetStock(sku, warehouse string, onHand int) (*StockLevel, error)
+	AdjustStock(sku, warehouse string, delta int) (*StockLevel, error)
+}
+
+type StockLevelsRepository struct {
+	db *gorm.DB
+}
+
+func NewStockLevelsRepository(db *gorm.DB) *StockLevelsRepository {
+	return &StockLevelsRepository{
+		db: db,
+	}
+}
+
+// GetWarehouses retrieves all warehouses ordered by code
+func (r *StockLevelsRepository) GetWarehouses() ([]Warehouse, error) {
+	var warehouses []Warehouse
+	if err := r.db.Order("code").Find(&warehouses).Error; err != nil {
+		return nil, err
+	}
+	return warehouses, nil
+}
+
+// GetStock retrieves the stock levels of a variant SKU per warehouse, with
+// their warehouse loaded. Warehouses without a stock row are omitted.
+func (r *StockLevelsRepository) GetStock(sku string) ([]StockLevel, error) {
+	var variant Variant
+	if err := r.db.Where("sku = ?", sku).First(&variant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVariantNotFound
+		}
+		return nil, err
+	}
+
+	var levels []StockLevel
+	if err := r.db.Joins("Warehouse").Where("stock_levels.sku = ?", sku).
+		Order("\"Warehouse\".code").Find(&levels).Error; err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+// SetStock overwrites the units on hand of a variant SKU in a warehouse,
+// e.g. after a stock count. An empty warehouse code selects the default
+// warehouse. It fails with ErrInsufficientStock below the reserved units.
+func (r *StockLevelsRepository) SetStock(sku, warehouse string, onHand int) (*StockLevel, error) {
+	return r.upsertStock(sku, warehouse, onHand, clause.Assignments(map[string]any{
+		"on_hand":    gorm.Expr("EXCLUDED.on_hand"),
+		"updated_at": gorm.Expr("NOW()"),
+	}))
+}
+
+// AdjustStock adds delta (negative to remove) to the units on hand of a
+// variant SKU in a warehouse in a single atomic statement. An empty
+// warehouse code selects the default warehouse. It fails with
+// ErrInsufficientStock when the result would drop below the reserved units.
+func (r *StockLevelsRepository) AdjustStock(sku, warehouse string, delta int) (*StockLevel, error) {
+	return r.upsertStock(sku, warehouse, delta, clause.Assignments(map[string]any{
+		"on_hand":    gorm.Expr("stock_levels.on_hand + EXCLUDED.on_hand"),
+		"updated_at": gorm.Expr("NOW()"),
+	}))
+}
+
+// upsertStock inserts a stock row for sku in warehouse with onHand units or
+// applies update to the existing one, returning the resulting row
+func (r *StockLevelsRepository) upsertStock(sku, warehouse string, onHand int, update clause.Set) (*StockLevel, error) {
+	wh, err := findWarehouse(r.db, warehouse)
+	if err != nil {
+		return nil, err
+	}
+
+	stock := StockLevel{SKU: sku, WarehouseID: wh.ID, OnHand: onHand}
+	err = r.db.Omit("Warehouse").Clauses(
+		clause.OnConflict{Columns: []clause.Column{{Name: "sku"}, {Name: "warehouse_id"}}, DoUpdates: update},
+		clause.Returning{},
+	).Create(&stock).Error
+	if err != nil {
+		return nil, mapStockWriteError(err)
+	}
+	stock.Warehouse = wh
+	return &stock, nil
+}
+
+// findWarehouse looks up a warehouse by code; an empty code selects the default warehouse
+func findWarehouse(tx *gorm.DB, code string) (*Warehouse, error) {
+	query := tx.Where("is_default")
+	if code != "" {
+		query = tx.Where("code = ?", code)
+	}
+
+	var warehouse Warehouse
+	if err := query.First(&warehouse).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWarehouseNotFound
+		}
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+// mapStockWriteError translates constraint violations of stock writes into domain errors
+func mapStockWriteError(err error) error {
+	if _, ok := foreignKeyViolation(err); ok {
+		return ErrVariantNotFound
+	}
+	if _, ok := checkViolation(err); ok {
+		return ErrInsufficientStock
+	}
+	return err
+}
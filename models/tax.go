@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TaxClass groups products taxed at the same rate, e.g. STANDARD or REDUCED.
+// Products without a class of their own or in their category tree use the
+// default class.
+type TaxClass struct {
+	ID        uint   `gorm:"primaryKey"`
+	Code      string `gorm:"uniqueIndex;not null"`
+	Name      string `gorm:"not null"`
+	IsDefault bool   `gorm:"not null"`
+}
+
+func (c *TaxClass) TableName() string {
+	return "tax_classes"
+}
+
+// ref returns the ID of c for a tax_class_id column, nil for a nil class
+func (c *TaxClass) ref() *uint {
+	if c == nil {
+		return nil
+	}
+	return &c.ID
+}
+
+// VATRate is the VAT rate of a tax class in a country, e.g. 0.19 for 19%
+type VATRate struct {
+	CountryCode string          `gorm:"primaryKey"`
+	TaxClassID  uint            `gorm:"primaryKey"`
+	TaxClass    *TaxClass       `gorm:"foreignKey:TaxClassID"`
+	Rate        decimal.Decimal `gorm:"type:decimal(5,4);not null"`
+	UpdatedAt   time.Time
+}
+
+func (r *VATRate) TableName() string {
+	return "vat_rates"
+}
+
+// CountryVAT holds the VAT rates of a country by tax class ID
+type CountryVAT struct {
+	Country string
+	Rates   map[uint]decimal.Decimal
+}
+
+// Rate returns the VAT rate of class in the country. Classes without a
+// configured rate are not taxed.
+func (c *CountryVAT) Rate(class *TaxClass) decimal.Decimal {
+	if class == nil {
+		return decimal.Zero
+	}
+	return c.Rates[class.ID]
+}
+
+// IncludedVAT returns the VAT contained in a gross amount at rate, rounded to cents
+func IncludedVAT(gross, rate decimal.Decimal) decimal.Decimal {
+	if !rate.IsPositive() {
+		return decimal.Zero
+	}
+	return gross.Sub(gross.Div(decimal.NewFromInt(1).Add(rate))).Round(2)
+}
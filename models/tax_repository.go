@@ -0,0 +1,193 @@
+package models
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// TaxRepository defines the interface for tax class and VAT rate data access
+type TaxRepository interface {
+	GetTaxClasses() ([]TaxClass, error)
+	CreateTaxClass(class *TaxClass) error
+	GetVATRates(country string) ([]VATRate, error)
+	GetCountryVAT(country string) (*CountryVAT, error)
+	SetVATRate(country, classCode string, rate decimal.Decimal) (*VATRate, error)
+	DeleteVATRate(country, classCode string) error
+}
+
+type TaxesRepository struct {
+	db *gorm.DB
+}
+
+func NewTaxesRepository(db *gorm.DB) *TaxesRepository {
+	return &TaxesRepository{db: db}
+}
+
+// GetTaxClasses retrieves all tax classes ordered by code
+func (r *TaxesRepository) GetTaxClasses() ([]TaxClass, error) {
+	var classes []TaxClass
+	if err := r.db.Order("code").Find(&classes).Error; err != nil {
+		return nil, err
+	}
+	return classes, nil
+}
+
+// CreateTaxClass inserts a tax class. New classes are never the default.
+func (r *TaxesRepository) CreateTaxClass(class *TaxClass) error {
+	if class == nil || strings.TrimSpace(class.Code) == "" || strings.TrimSpace(class.Name) == "" {
+		return ErrInvalidTaxClass
+	}
+
+	class.ID = 0
+	class.IsDefault = false
+	if err := r.db.Create(class).Error; err != nil {
+		if _, ok := uniqueViolation(err); ok {
+			return ErrTaxClassCodeExists
+		}
+		return err
+	}
+	return nil
+}
+
+// GetVATRates retrieves the VAT rates of a country, or of all countries when
+// country is empty, ordered by country and tax class
+func (r *TaxesRepository) GetVATRates(country string) ([]VATRate, error) {
+	query := r.db.Joins("TaxClass")
+	if country != "" {
+		query = query.Where("vat_rates.country_code = ?", country)
+	}
+
+	var rates []VATRate
+	if err := query.Order("vat_rates.country_code, \"TaxClass\".code").Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// GetCountryVAT retrieves the VAT rates of a country. Countries without any
+// rate fail with ErrCountryNotSupported.
+func (r *TaxesRepository) GetCountryVAT(country string) (*CountryVAT, error) {
+	var rates []VATRate
+	if err := r.db.Where("country_code = ?", country).Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	if len(rates) == 0 {
+		return nil, ErrCountryNotSupported
+	}
+
+	vat := &CountryVAT{Country: country, Rates: make(map[uint]decimal.Decimal, len(rates))}
+	for _, rate := range rates {
+		vat.Rates[rate.TaxClassID] = rate.Rate
+	}
+	return vat, nil
+}
+
+// SetVATRate creates or replaces the rate of the tax class classCode in country
+func (r *TaxesRepository) SetVATRate(country, classCode string, rate decimal.Decimal) (*VATRate, error) {
+	if len(country) != 2 || strings.ToUpper(country) != country || rate.IsNegative() || !rate.LessThan(decimal.NewFromInt(1)) {
+		return nil, ErrInvalidVATRate
+	}
+
+	class, err := findTaxClassByCode(r.db, classCode)
+	if err != nil {
+		return nil, err
+	}
+
+	vatRate := &VATRate{CountryCode: country, TaxClassID: class.ID, TaxClass: class, Rate: rate}
+	if err := r.db.Exec(`INSERT INTO vat_rates (country_code, tax_class_id, rate) VALUES (?, ?, ?)
+		ON CONFLICT (country_code, tax_class_id) DO UPDATE SET rate = EXCLUDED.rate, updated_at = NOW()`,
+		country, class.ID, rate).Error; err != nil {
+		if _, ok := checkViolation(err); ok {
+			return nil, ErrInvalidVATRate
+		}
+		return nil, err
+	}
+	return vatRate, nil
+}
+
+// DeleteVATRate removes the rate of the tax class classCode in country
+func (r *TaxesRepository) DeleteVATRate(country, classCode string) error {
+	class, err := findTaxClassByCode(r.db, classCode)
+	if err != nil {
+		return err
+	}
+
+	result := r.db.Where("country_code = ? AND tax_class_id = ?", country, class.ID).Delete(&VATRate{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVATRateNotFound
+	}
+	return nil
+}
+
+// findTaxClassByCode looks up a tax class by its code
+func findTaxClassByCode(tx *gorm.DB, code string) (*TaxClass, error) {
+	var class TaxClass
+	if err := tx.Where("code = ?", code).First(&class).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTaxClassNotFound
+		}
+		return nil, err
+	}
+	return &class, nil
+}
+
+// resolveTaxClass looks up the tax class referenced by the code of class.
+// A nil class or an empty code resolves to nil so the class is inherited.
+func resolveTaxClass(tx *gorm.DB, class *TaxClass) (*TaxClass, error) {
+	if class == nil || class.Code == "" {
+		return nil, nil
+	}
+	return findTaxClassByCode(tx, class.Code)
+}
+
+// attachTaxClasses sets the effective tax class of each product: its own
+// class, else the class of the nearest category in its ancestry, else the
+// default class
+func attachTaxClasses(tx *gorm.DB, products []Product, ancestry []categoryAncestor) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	var classes []TaxClass
+	if err := tx.Find(&classes).Error; err != nil {
+		return err
+	}
+	byID := make(map[uint]*TaxClass, len(classes))
+	var fallback *TaxClass
+	for i := range classes {
+		byID[classes[i].ID] = &classes[i]
+		if classes[i].IsDefault {
+			fallback = &classes[i]
+		}
+	}
+
+	// The nearest ancestor with a class wins
+	nearest := make(map[uint]categoryAncestor)
+	for _, a := range ancestry {
+		if a.TaxClassID == nil {
+			continue
+		}
+		if current, ok := nearest[a.CategoryID]; !ok || a.Depth < current.Depth {
+			nearest[a.CategoryID] = a
+		}
+	}
+
+	for i := range products {
+		p := &products[i]
+		switch {
+		case p.TaxClassID != nil:
+			p.EffectiveTaxClass = byID[*p.TaxClassID]
+		case nearest[p.CategoryID].TaxClassID != nil:
+			p.EffectiveTaxClass = byID[*nearest[p.CategoryID].TaxClassID]
+		default:
+			p.EffectiveTaxClass = fallback
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncludedVAT(t *testing.T) {
+	assert.Equal(t, "19", IncludedVAT(mustDecimal("119.00"), mustDecimal("0.19")).String())
+	assert.Equal(t, "1.6", IncludedVAT(mustDecimal("9.99"), mustDecimal("0.19")).String())
+	assert.True(t, IncludedVAT(mustDecimal("100.00"), decimal.Zero).IsZero())
+}
+
+func TestCountryVAT_Rate(t *testing.T) {
+	standard := &TaxClass{ID: 1, Code: "STANDARD"}
+	reduced := &TaxClass{ID: 2, Code: "REDUCED"}
+	vat := &CountryVAT{Country: "DE", Rates: map[uint]decimal.Decimal{standard.ID: mustDecimal("0.19")}}
+
+	assert.Equal(t, "0.19", vat.Rate(standard).String())
+	assert.True(t, vat.Rate(reduced).IsZero(), "classes without a rate are not taxed")
+	assert.True(t, vat.Rate(nil).IsZero())
+}
@@ -8,13 +8,28 @@ import (
 // It includes a unique name, SKU, and an optional price.
 // Variants can be used to represent different configurations or options for a product.
 type Variant struct {
-	ID        uint            `gorm:"primaryKey"`
-	ProductID uint            `gorm:"not null"`
-	Name      string          `gorm:"not null"`
-	SKU       string          `gorm:"uniqueIndex;not null"`
-	Price     decimal.Decimal `gorm:"type:decimal(10,2);null"`
+	ID        uint                `gorm:"primaryKey"`
+	ProductID uint                `gorm:"not null"`
+	Name      string              `gorm:"not null"`
+	SKU       string              `gorm:"uniqueIndex;not null"`
+	Price     decimal.NullDecimal `gorm:"type:decimal(10,2);null"`
+	Prices    []VariantPrice      `gorm:"foreignKey:VariantID"`
+	Stock     *VariantStock       `gorm:"foreignKey:SKU;references:SKU"`
+
+	// Options holds the attribute values of the variant, e.g. size and
+	// color. They are attached by the repository, not preloaded.
+	Options []VariantOption `gorm:"-"`
 }
 
 func (v *Variant) TableName() string {
 	return "product_variants"
 }
+
+// EffectivePrice returns the variant price, falling back to the given
+// product price when the variant has no price of its own (NULL or zero).
+func (v *Variant) EffectivePrice(productPrice decimal.Decimal) decimal.Decimal {
+	if !v.Price.Valid || v.Price.Decimal.IsZero() {
+		return productPrice
+	}
+	return v.Price.Decimal
+}
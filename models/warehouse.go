@@ -0,0 +1,15 @@
+package models
+
+// Warehouse represents a distribution centre holding stock.
+// Only active warehouses count towards the availability shown in the catalog.
+type Warehouse struct {
+	ID        uint   `gorm:"primaryKey"`
+	Code      string `gorm:"uniqueIndex;not null"`
+	Name      string `gorm:"not null"`
+	Active    bool   `gorm:"not null"`
+	IsDefault bool   `gorm:"not null"`
+}
+
+func (w *Warehouse) TableName() string {
+	return "warehouses"
+}
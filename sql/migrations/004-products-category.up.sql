@@ -0,0 +1,2 @@
+-- Add category_id column to products table
+ALTER TABLE products ADD COLUMN category_id INTEGER REFERENCES categories(id);
@@ -0,0 +1 @@
+ALTER TABLE products DROP CONSTRAINT products_code_key;
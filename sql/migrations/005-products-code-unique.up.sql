@@ -0,0 +1,2 @@
+-- Enforce unique product codes so duplicates are rejected by the database
+ALTER TABLE products ADD CONSTRAINT products_code_key UNIQUE (code);
@@ -0,0 +1,4 @@
+-- Allow categories to be nested ("Shoes > Sneakers > Running")
+ALTER TABLE categories ADD COLUMN parent_id INTEGER REFERENCES categories(id);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
@@ -0,0 +1,12 @@
+DROP INDEX idx_products_search_vector;
+
+DROP TRIGGER categories_search_refresh ON categories;
+DROP TRIGGER variants_search_refresh ON product_variants;
+DROP TRIGGER products_search_refresh ON products;
+
+DROP FUNCTION categories_search_refresh();
+DROP FUNCTION variants_search_refresh();
+DROP FUNCTION products_search_refresh();
+DROP FUNCTION product_search_document(INTEGER);
+
+ALTER TABLE products DROP COLUMN search_vector;
@@ -0,0 +1,60 @@
+-- Full-text search over product codes, variant names, variant SKUs and category names.
+-- The document spans several tables, so it is kept up to date by triggers
+-- instead of a generated column.
+ALTER TABLE products ADD COLUMN search_vector tsvector;
+
+CREATE OR REPLACE FUNCTION product_search_document(pid INTEGER) RETURNS tsvector AS $$
+    SELECT
+        setweight(to_tsvector('simple', coalesce(p.code, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(
+            (SELECT string_agg(v.sku, ' ') FROM product_variants v WHERE v.product_id = p.id), '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(
+            (SELECT string_agg(v.name, ' ') FROM product_variants v WHERE v.product_id = p.id), '')), 'B') ||
+        setweight(to_tsvector('simple', coalesce(c.name, '')), 'C')
+    FROM products p
+    LEFT JOIN categories c ON c.id = p.category_id
+    WHERE p.id = pid;
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION products_search_refresh() RETURNS trigger AS $$
+BEGIN
+    UPDATE products SET search_vector = product_search_document(NEW.id) WHERE id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION variants_search_refresh() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE products SET search_vector = product_search_document(OLD.product_id) WHERE id = OLD.product_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE products SET search_vector = product_search_document(NEW.product_id) WHERE id = NEW.product_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION categories_search_refresh() RETURNS trigger AS $$
+BEGIN
+    UPDATE products SET search_vector = product_search_document(id) WHERE category_id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Only fire on the indexed columns so refreshing search_vector does not recurse
+CREATE TRIGGER products_search_refresh
+    AFTER INSERT OR UPDATE OF code, category_id ON products
+    FOR EACH ROW EXECUTE FUNCTION products_search_refresh();
+
+CREATE TRIGGER variants_search_refresh
+    AFTER INSERT OR UPDATE OF name, sku, product_id OR DELETE ON product_variants
+    FOR EACH ROW EXECUTE FUNCTION variants_search_refresh();
+
+CREATE TRIGGER categories_search_refresh
+    AFTER UPDATE OF name ON categories
+    FOR EACH ROW EXECUTE FUNCTION categories_search_refresh();
+
+UPDATE products SET search_vector = product_search_document(id);
+
+CREATE INDEX IF NOT EXISTS idx_products_search_vector ON products USING GIN (search_vector);
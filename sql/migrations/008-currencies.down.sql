@@ -0,0 +1,4 @@
+DROP TABLE variant_prices;
+DROP TABLE product_prices;
+DROP TABLE exchange_rates;
+DROP TABLE currencies;
@@ -0,0 +1,45 @@
+-- Currencies prices can be served in. Stored prices are in the base currency
+-- (EUR); other currencies are converted with the exchange rate and rounded to
+-- a multiple of rounding_increment, unless an explicit override exists.
+CREATE TABLE IF NOT EXISTS currencies (
+    code CHAR(3) PRIMARY KEY,
+    rounding_increment DECIMAL(10, 2) NOT NULL DEFAULT 0.01 CHECK (rounding_increment > 0),
+    rounding_mode VARCHAR(16) NOT NULL DEFAULT 'half_up'
+        CHECK (rounding_mode IN ('half_up', 'half_even', 'up', 'down')),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Amount in currency = amount in EUR * rate
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    currency_code CHAR(3) PRIMARY KEY REFERENCES currencies(code) ON DELETE CASCADE,
+    rate DECIMAL(18, 8) NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Explicit per-currency prices that take precedence over conversion
+CREATE TABLE IF NOT EXISTS product_prices (
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    currency_code CHAR(3) NOT NULL,
+    price DECIMAL(10, 2) NOT NULL,
+    PRIMARY KEY (product_id, currency_code),
+    CONSTRAINT product_prices_currency_fkey FOREIGN KEY (currency_code) REFERENCES currencies(code)
+);
+
+CREATE TABLE IF NOT EXISTS variant_prices (
+    variant_id INTEGER NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    currency_code CHAR(3) NOT NULL,
+    price DECIMAL(10, 2) NOT NULL,
+    PRIMARY KEY (variant_id, currency_code),
+    CONSTRAINT variant_prices_currency_fkey FOREIGN KEY (currency_code) REFERENCES currencies(code)
+);
+
+INSERT INTO currencies (code, rounding_increment, rounding_mode) VALUES
+    ('EUR', 0.01, 'half_up'),
+    ('GBP', 0.01, 'half_up'),
+    ('USD', 0.01, 'half_up');
+
+INSERT INTO exchange_rates (currency_code, rate) VALUES
+    ('EUR', 1),
+    ('GBP', 0.85),
+    ('USD', 1.08);
@@ -0,0 +1,10 @@
+-- Stock levels per variant SKU. Available stock is on_hand - reserved.
+-- Variants without a row have no stock.
+CREATE TABLE IF NOT EXISTS stock_levels (
+    sku VARCHAR(32) PRIMARY KEY
+        REFERENCES product_variants(sku) ON UPDATE CASCADE ON DELETE CASCADE,
+    on_hand INTEGER NOT NULL DEFAULT 0,
+    reserved INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT stock_levels_quantity_check CHECK (reserved >= 0 AND on_hand >= reserved)
+);
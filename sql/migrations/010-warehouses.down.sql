@@ -0,0 +1,10 @@
+DROP VIEW variant_stock;
+
+-- Stock levels go back to one row per SKU: only the stock of the default
+-- warehouse is kept
+DELETE FROM stock_levels WHERE warehouse_id <> (SELECT id FROM warehouses WHERE is_default);
+ALTER TABLE stock_levels DROP CONSTRAINT stock_levels_pkey;
+ALTER TABLE stock_levels ADD PRIMARY KEY (sku);
+ALTER TABLE stock_levels DROP COLUMN warehouse_id;
+
+DROP TABLE warehouses;
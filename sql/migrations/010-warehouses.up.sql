@@ -0,0 +1,34 @@
+-- Distribution centres stock is held in. Only active warehouses count towards
+-- the availability shown in the catalog; the default warehouse receives stock
+-- writes that do not name a warehouse.
+CREATE TABLE IF NOT EXISTS warehouses (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(32) UNIQUE NOT NULL,
+    name VARCHAR(256) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_warehouses_default ON warehouses (is_default) WHERE is_default;
+
+INSERT INTO warehouses (code, name, is_default) VALUES
+    ('MUC', 'Munich DC', TRUE),
+    ('LEJ', 'Leipzig DC', FALSE),
+    ('NYC', 'New York DC', FALSE);
+
+-- Stock levels become per SKU and warehouse; existing stock is in the default warehouse
+ALTER TABLE stock_levels ADD COLUMN warehouse_id INTEGER REFERENCES warehouses(id) ON DELETE CASCADE;
+UPDATE stock_levels SET warehouse_id = (SELECT id FROM warehouses WHERE is_default);
+ALTER TABLE stock_levels ALTER COLUMN warehouse_id SET NOT NULL;
+ALTER TABLE stock_levels DROP CONSTRAINT stock_levels_pkey;
+ALTER TABLE stock_levels ADD PRIMARY KEY (sku, warehouse_id);
+
+-- Stock of each SKU aggregated over the active warehouses
+CREATE VIEW variant_stock AS
+SELECT s.sku, SUM(s.on_hand)::INTEGER AS on_hand, SUM(s.reserved)::INTEGER AS reserved
+FROM stock_levels s
+JOIN warehouses w ON w.id = s.warehouse_id
+WHERE w.active
+GROUP BY s.sku;
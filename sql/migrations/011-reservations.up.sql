@@ -0,0 +1,23 @@
+-- Stock held for checkouts. Pending reservations count towards
+-- stock_levels.reserved until they are confirmed, released or expire.
+CREATE TABLE IF NOT EXISTS reservations (
+    id SERIAL PRIMARY KEY,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'confirmed', 'released', 'expired')),
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reservations_pending_expiry ON reservations (expires_at) WHERE status = 'pending';
+
+-- The units of a reservation, allocated to the warehouses they are held in
+CREATE TABLE IF NOT EXISTS reservation_items (
+    id SERIAL PRIMARY KEY,
+    reservation_id INTEGER NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+    sku VARCHAR(32) NOT NULL,
+    warehouse_id INTEGER NOT NULL REFERENCES warehouses(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reservation_items_reservation_id ON reservation_items(reservation_id);
@@ -0,0 +1,16 @@
+-- Prices scheduled for a time window, e.g. a sale. An entry without
+-- variant_id applies to the product and to the variants inheriting its
+-- price; an entry with variant_id applies to that variant only.
+-- Prices are in the base currency; valid_to is exclusive and open-ended when NULL.
+CREATE TABLE IF NOT EXISTS scheduled_prices (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE,
+    price DECIMAL(10, 2) NOT NULL CHECK (price > 0),
+    valid_from TIMESTAMPTZ NOT NULL,
+    valid_to TIMESTAMPTZ,
+    created_at TIMESTAMP DEFAULT NOW(),
+    CHECK (valid_to IS NULL OR valid_to > valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_prices_product_window ON scheduled_prices (product_id, valid_from, valid_to);
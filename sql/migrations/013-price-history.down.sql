@@ -0,0 +1,8 @@
+DROP TRIGGER variants_price_audit ON product_variants;
+DROP TRIGGER products_price_audit ON products;
+
+DROP FUNCTION variants_price_audit();
+DROP FUNCTION products_price_audit();
+DROP FUNCTION price_change_actor();
+
+DROP TABLE price_changes;
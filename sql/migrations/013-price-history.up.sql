@@ -0,0 +1,50 @@
+-- Audit trail of the regular prices of products and variants. Rows are written
+-- by triggers so every change is recorded, whichever client makes it.
+-- The actor is taken from the app.actor setting of the writing transaction
+-- and falls back to the database user. A NULL variant price means the variant
+-- inherits the product price.
+CREATE TABLE IF NOT EXISTS price_changes (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id INTEGER REFERENCES product_variants(id) ON DELETE SET NULL,
+    sku VARCHAR(256),
+    old_price DECIMAL(10, 2),
+    new_price DECIMAL(10, 2),
+    actor VARCHAR(256) NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_changes_product_changed_at ON price_changes (product_id, changed_at);
+
+CREATE OR REPLACE FUNCTION price_change_actor() RETURNS VARCHAR AS $$
+    SELECT COALESCE(NULLIF(current_setting('app.actor', true), ''), session_user);
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION products_price_audit() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' OR OLD.price IS DISTINCT FROM NEW.price THEN
+        INSERT INTO price_changes (product_id, old_price, new_price, actor)
+        VALUES (NEW.id, CASE WHEN TG_OP = 'UPDATE' THEN OLD.price END, NEW.price, price_change_actor());
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION variants_price_audit() RETURNS trigger AS $$
+BEGIN
+    IF (TG_OP = 'INSERT' AND NEW.price IS NOT NULL)
+        OR (TG_OP = 'UPDATE' AND OLD.price IS DISTINCT FROM NEW.price) THEN
+        INSERT INTO price_changes (product_id, variant_id, sku, old_price, new_price, actor)
+        VALUES (NEW.product_id, NEW.id, NEW.sku, CASE WHEN TG_OP = 'UPDATE' THEN OLD.price END, NEW.price, price_change_actor());
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER products_price_audit
+    AFTER INSERT OR UPDATE OF price ON products
+    FOR EACH ROW EXECUTE FUNCTION products_price_audit();
+
+CREATE TRIGGER variants_price_audit
+    AFTER INSERT OR UPDATE OF price ON product_variants
+    FOR EACH ROW EXECUTE FUNCTION variants_price_audit();
@@ -0,0 +1,26 @@
+-- Discount rules applied to catalog prices. A promotion targets either a
+-- product or a category, including its subcategories. Active promotions are
+-- applied by descending priority: the first one always applies and further
+-- ones only while every applied promotion is stackable.
+-- Percentage values are in percent; fixed values are in the base currency.
+CREATE TABLE IF NOT EXISTS promotions (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(32) UNIQUE NOT NULL,
+    name VARCHAR(256) NOT NULL,
+    discount_type VARCHAR(16) NOT NULL CHECK (discount_type IN ('percentage', 'fixed')),
+    value DECIMAL(10, 2) NOT NULL CHECK (value > 0),
+    product_id INTEGER REFERENCES products(id) ON DELETE CASCADE,
+    category_id INTEGER REFERENCES categories(id) ON DELETE CASCADE,
+    priority INTEGER NOT NULL DEFAULT 0,
+    stackable BOOLEAN NOT NULL DEFAULT FALSE,
+    valid_from TIMESTAMPTZ,
+    valid_to TIMESTAMPTZ,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT promotions_target_check CHECK ((product_id IS NULL) <> (category_id IS NULL)),
+    CONSTRAINT promotions_percentage_check CHECK (discount_type <> 'percentage' OR value <= 100),
+    CONSTRAINT promotions_window_check CHECK (valid_from IS NULL OR valid_to IS NULL OR valid_to > valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotions_product_id ON promotions (product_id);
+CREATE INDEX IF NOT EXISTS idx_promotions_category_id ON promotions (category_id);
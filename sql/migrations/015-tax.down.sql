@@ -0,0 +1,5 @@
+ALTER TABLE products DROP COLUMN tax_class_id;
+ALTER TABLE categories DROP COLUMN tax_class_id;
+
+DROP TABLE vat_rates;
+DROP TABLE tax_classes;
@@ -0,0 +1,44 @@
+-- Catalog prices are gross: they include the VAT of the country they are
+-- sold to. The VAT rate depends on the tax class of a product, taken from the
+-- product itself, else from its category or the nearest ancestor category
+-- with a class, else from the default class.
+CREATE TABLE IF NOT EXISTS tax_classes (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(32) UNIQUE NOT NULL,
+    name VARCHAR(256) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tax_classes_default ON tax_classes (is_default) WHERE is_default;
+
+-- VAT rate of a tax class in a country (ISO 3166-1 alpha-2), e.g. 0.19 for 19%
+CREATE TABLE IF NOT EXISTS vat_rates (
+    country_code CHAR(2) NOT NULL CHECK (country_code ~ '^[A-Z]{2}$'),
+    tax_class_id INTEGER NOT NULL REFERENCES tax_classes(id) ON DELETE CASCADE,
+    rate DECIMAL(5, 4) NOT NULL CHECK (rate >= 0 AND rate < 1),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (country_code, tax_class_id)
+);
+
+ALTER TABLE categories ADD COLUMN tax_class_id INTEGER REFERENCES tax_classes(id);
+ALTER TABLE products ADD COLUMN tax_class_id INTEGER REFERENCES tax_classes(id);
+
+INSERT INTO tax_classes (code, name, is_default) VALUES
+    ('STANDARD', 'Standard rate', TRUE),
+    ('REDUCED', 'Reduced rate', FALSE),
+    ('ZERO', 'Zero rate', FALSE);
+
+INSERT INTO vat_rates (country_code, tax_class_id, rate)
+SELECT r.country_code, c.id, r.rate
+FROM (VALUES
+    ('DE', 'STANDARD', 0.19), ('DE', 'REDUCED', 0.07), ('DE', 'ZERO', 0),
+    ('AT', 'STANDARD', 0.20), ('AT', 'REDUCED', 0.10), ('AT', 'ZERO', 0),
+    ('FR', 'STANDARD', 0.20), ('FR', 'REDUCED', 0.055), ('FR', 'ZERO', 0),
+    ('IT', 'STANDARD', 0.22), ('IT', 'REDUCED', 0.10), ('IT', 'ZERO', 0),
+    ('ES', 'STANDARD', 0.21), ('ES', 'REDUCED', 0.10), ('ES', 'ZERO', 0),
+    ('NL', 'STANDARD', 0.21), ('NL', 'REDUCED', 0.09), ('NL', 'ZERO', 0),
+    ('GB', 'STANDARD', 0.20), ('GB', 'REDUCED', 0.05), ('GB', 'ZERO', 0)
+) AS r (country_code, class_code, rate)
+JOIN tax_classes c ON c.code = r.class_code;
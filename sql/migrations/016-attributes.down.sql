@@ -0,0 +1,2 @@
+DROP TABLE variant_options;
+DROP TABLE attributes;
@@ -0,0 +1,27 @@
+-- Typed variant attributes such as size, color or material. An attribute is
+-- defined on a category and applies to the products of the category and of
+-- its subcategories; its code is unique along every branch of the tree.
+-- Position orders the option axes of a product.
+CREATE TABLE IF NOT EXISTS attributes (
+    id SERIAL PRIMARY KEY,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    code VARCHAR(32) NOT NULL,
+    name VARCHAR(256) NOT NULL,
+    data_type VARCHAR(16) NOT NULL CHECK (data_type IN ('text', 'number')),
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (category_id, code)
+);
+
+-- Option values of variants, e.g. size 42 and color black. Number values are
+-- stored in their canonical decimal form.
+CREATE TABLE IF NOT EXISTS variant_options (
+    variant_id INTEGER NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    attribute_id INTEGER NOT NULL REFERENCES attributes(id) ON DELETE CASCADE,
+    value VARCHAR(64) NOT NULL CHECK (value <> ''),
+    PRIMARY KEY (variant_id, attribute_id)
+);
+
+-- Serves the ?attr.<code>= filters, which match values case-insensitively
+CREATE INDEX IF NOT EXISTS idx_variant_options_value ON variant_options (attribute_id, LOWER(value));
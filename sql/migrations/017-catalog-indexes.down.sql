@@ -0,0 +1,2 @@
+DROP INDEX idx_products_category_id;
+DROP INDEX idx_product_variants_product_id;
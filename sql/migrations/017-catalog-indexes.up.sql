@@ -0,0 +1,4 @@
+-- Foreign keys the catalog joins and filters on. Without them loading the
+-- variants of a page and refreshing search documents scan every variant.
+CREATE INDEX IF NOT EXISTS idx_product_variants_product_id ON product_variants (product_id);
+CREATE INDEX IF NOT EXISTS idx_products_category_id ON products (category_id);
@@ -0,0 +1,32 @@
+CREATE OR REPLACE FUNCTION variants_price_audit() RETURNS trigger AS $$
+BEGIN
+    IF (TG_OP = 'INSERT' AND NEW.price IS NOT NULL)
+        OR (TG_OP = 'UPDATE' AND OLD.price IS DISTINCT FROM NEW.price) THEN
+        INSERT INTO price_changes (product_id, variant_id, sku, old_price, new_price, actor)
+        VALUES (NEW.product_id, NEW.id, NEW.sku, CASE WHEN TG_OP = 'UPDATE' THEN OLD.price END, NEW.price, price_change_actor());
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION products_price_audit() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' OR OLD.price IS DISTINCT FROM NEW.price THEN
+        INSERT INTO price_changes (product_id, old_price, new_price, actor)
+        VALUES (NEW.id, CASE WHEN TG_OP = 'UPDATE' THEN OLD.price END, NEW.price, price_change_actor());
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX idx_price_changes_product_code_changed_at;
+
+-- The history of deleted products cannot reference them anymore
+DELETE FROM price_changes WHERE product_id IS NULL;
+
+ALTER TABLE price_changes DROP CONSTRAINT price_changes_product_id_fkey;
+ALTER TABLE price_changes ADD CONSTRAINT price_changes_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
+ALTER TABLE price_changes ALTER COLUMN product_id SET NOT NULL;
+
+ALTER TABLE price_changes DROP COLUMN product_code;
@@ -0,0 +1,38 @@
+-- Keep the price history of deleted products: disputed prices must stay on
+-- record. Rows carry the product code at the time of the change and lose
+-- their product reference, instead of being removed, when the product is.
+ALTER TABLE price_changes ADD COLUMN product_code VARCHAR(32);
+
+UPDATE price_changes SET product_code = products.code
+FROM products WHERE products.id = price_changes.product_id;
+
+ALTER TABLE price_changes ALTER COLUMN product_code SET NOT NULL;
+
+ALTER TABLE price_changes ALTER COLUMN product_id DROP NOT NULL;
+ALTER TABLE price_changes DROP CONSTRAINT price_changes_product_id_fkey;
+ALTER TABLE price_changes ADD CONSTRAINT price_changes_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_price_changes_product_code_changed_at ON price_changes (product_code, changed_at);
+
+CREATE OR REPLACE FUNCTION products_price_audit() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' OR OLD.price IS DISTINCT FROM NEW.price THEN
+        INSERT INTO price_changes (product_id, product_code, old_price, new_price, actor)
+        VALUES (NEW.id, NEW.code, CASE WHEN TG_OP = 'UPDATE' THEN OLD.price END, NEW.price, price_change_actor());
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION variants_price_audit() RETURNS trigger AS $$
+BEGIN
+    IF (TG_OP = 'INSERT' AND NEW.price IS NOT NULL)
+        OR (TG_OP = 'UPDATE' AND OLD.price IS DISTINCT FROM NEW.price) THEN
+        INSERT INTO price_changes (product_id, product_code, variant_id, sku, old_price, new_price, actor)
+        SELECT NEW.product_id, code, NEW.id, NEW.sku, CASE WHEN TG_OP = 'UPDATE' THEN OLD.price END, NEW.price, price_change_actor()
+        FROM products WHERE id = NEW.product_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
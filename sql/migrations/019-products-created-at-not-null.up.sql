@@ -0,0 +1,6 @@
+-- Keyset pagination on created_at compares (created_at, id) row values, which
+-- are NULL for a NULL created_at: such products would be on no page. Rows
+-- without a creation time take their last update, else the migration time.
+UPDATE products SET created_at = COALESCE(updated_at, NOW()) WHERE created_at IS NULL;
+
+ALTER TABLE products ALTER COLUMN created_at SET NOT NULL;
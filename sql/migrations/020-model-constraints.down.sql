@@ -0,0 +1,14 @@
+DROP INDEX idx_price_changes_variant_id;
+DROP INDEX idx_scheduled_prices_variant_id;
+DROP INDEX idx_categories_tax_class_id;
+DROP INDEX idx_products_tax_class_id;
+
+ALTER TABLE reservations ALTER COLUMN updated_at DROP NOT NULL;
+ALTER TABLE reservations ALTER COLUMN created_at DROP NOT NULL;
+ALTER TABLE stock_levels ALTER COLUMN updated_at DROP NOT NULL;
+ALTER TABLE vat_rates ALTER COLUMN updated_at DROP NOT NULL;
+ALTER TABLE exchange_rates ALTER COLUMN updated_at DROP NOT NULL;
+
+ALTER TABLE products ALTER COLUMN category_id DROP NOT NULL;
+ALTER TABLE product_variants ALTER COLUMN sku DROP NOT NULL;
+ALTER TABLE products ALTER COLUMN code DROP NOT NULL;
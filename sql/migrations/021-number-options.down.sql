@@ -0,0 +1 @@
+-- The original spelling of the values is not kept: the canonical form stays.
@@ -0,0 +1,19 @@
+-- Options of number attributes are compared as text by the catalog filters
+-- and facets, so they are stored in canonical decimal form: "40.0" becomes
+-- "40", like the repository writes them. Values written before the
+-- normalization, or loaded directly, are rewritten; the CASE keeps values
+-- that are not numbers away from the cast.
+CREATE FUNCTION pg_temp.canonical_number(value TEXT) RETURNS TEXT AS $$
+    SELECT CASE
+        WHEN btrim(value) ~ '^[+-]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][+-]?[0-9]+)?$'
+        THEN trim_scale(btrim(value)::numeric)::text
+        ELSE value
+    END;
+$$ LANGUAGE sql IMMUTABLE;
+
+UPDATE variant_options o
+SET value = pg_temp.canonical_number(o.value)
+FROM attributes a
+WHERE a.id = o.attribute_id
+  AND a.data_type = 'number'
+  AND o.value <> pg_temp.canonical_number(o.value);
@@ -0,0 +1,10 @@
+// Package migrations embeds the versioned schema migrations so binaries can
+// migrate the database without shipping the sql directory.
+package migrations
+
+import "embed"
+
+// FS holds the NNN-name.up.sql and NNN-name.down.sql files
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,3 @@
+-- Remove the demo data so seeding can be repeated. Reference data created by
+-- the migrations (currencies, warehouses, tax classes) is kept.
+TRUNCATE products, categories, reservations RESTART IDENTITY CASCADE;
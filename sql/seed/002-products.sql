@@ -1,13 +1,18 @@
 -- Insert 8 products
-INSERT INTO products (code, price) VALUES
-('PROD001', 10.99),
-('PROD002', 12.49),
-('PROD003', 8.75),
-('PROD004', 15.00),
-('PROD005', 22.99),
-('PROD006', 5.50),
-('PROD007', 18.20),
-('PROD008', 9.99);
+INSERT INTO products (code, price, category_id)
+SELECT p.code, p.price, c.id
+FROM (VALUES
+    ('PROD001', 10.99, 'CLOTHING'),
+    ('PROD002', 12.49, 'SHOES'),
+    ('PROD003', 8.75, 'ACCESSORIES'),
+    ('PROD004', 15.00, 'CLOTHING'),
+    ('PROD005', 22.99, 'ACCESSORIES'),
+    ('PROD006', 5.50, 'SHOES'),
+    ('PROD007', 18.20, 'CLOTHING'),
+    ('PROD008', 9.99, 'ACCESSORIES')
+) AS p (code, price, category)
+JOIN categories c ON c.code = p.category
+ORDER BY p.code;
 
 -- Insert variants for each product using product code to look up product_id
 
@@ -0,0 +1,6 @@
+-- 10 units of every variant in the default warehouse
+INSERT INTO stock_levels (sku, warehouse_id, on_hand)
+SELECT v.sku, w.id, 10
+FROM product_variants v
+JOIN warehouses w ON w.is_default
+WHERE v.sku IS NOT NULL;
@@ -0,0 +1,43 @@
+-- Variant attributes of the demo categories and the options of their variants
+INSERT INTO attributes (category_id, code, name, data_type, position)
+SELECT c.id, a.code, a.name, a.data_type, a.position
+FROM (VALUES
+    ('CLOTHING', 'size', 'Size', 'text', 1),
+    ('CLOTHING', 'color', 'Color', 'text', 2),
+    ('CLOTHING', 'material', 'Material', 'text', 3),
+    ('SHOES', 'size', 'Size', 'number', 1),
+    ('SHOES', 'color', 'Color', 'text', 2),
+    ('ACCESSORIES', 'color', 'Color', 'text', 1),
+    ('ACCESSORIES', 'material', 'Material', 'text', 2)
+) AS a (category_code, code, name, data_type, position)
+JOIN categories c ON c.code = a.category_code;
+
+INSERT INTO variant_options (variant_id, attribute_id, value)
+SELECT v.id, a.id, o.value
+FROM (VALUES
+    ('SKU001A', 'size', 'S'), ('SKU001A', 'color', 'black'),
+    ('SKU001B', 'size', 'M'), ('SKU001B', 'color', 'black'),
+    ('SKU001C', 'size', 'L'), ('SKU001C', 'color', 'white'),
+    ('SKU002A', 'size', '41'), ('SKU002A', 'color', 'black'),
+    ('SKU002B', 'size', '42'), ('SKU002B', 'color', 'black'),
+    ('SKU003A', 'color', 'black'), ('SKU003A', 'material', 'leather'),
+    ('SKU004A', 'size', 'S'), ('SKU004A', 'color', 'navy'),
+    ('SKU004B', 'size', 'M'), ('SKU004B', 'color', 'navy'),
+    ('SKU004C', 'size', 'S'), ('SKU004C', 'color', 'white'),
+    ('SKU004D', 'size', 'M'), ('SKU004D', 'color', 'white'),
+    ('SKU005A', 'color', 'black'), ('SKU005A', 'material', 'leather'),
+    ('SKU005B', 'color', 'brown'), ('SKU005B', 'material', 'leather'),
+    ('SKU005C', 'color', 'tan'), ('SKU005C', 'material', 'leather'),
+    ('SKU005D', 'color', 'black'), ('SKU005D', 'material', 'canvas'),
+    ('SKU005E', 'color', 'brown'), ('SKU005E', 'material', 'canvas'),
+    ('SKU005F', 'color', 'tan'), ('SKU005F', 'material', 'canvas'),
+    ('SKU007A', 'size', 'XS'), ('SKU007A', 'color', 'black'),
+    ('SKU007B', 'size', 'S'), ('SKU007B', 'color', 'black'),
+    ('SKU007C', 'size', 'M'), ('SKU007C', 'color', 'black'),
+    ('SKU007D', 'size', 'L'), ('SKU007D', 'color', 'black'),
+    ('SKU007E', 'size', 'XL'), ('SKU007E', 'color', 'black'),
+    ('SKU008A', 'color', 'gold'), ('SKU008A', 'material', 'metal')
+) AS o (sku, attribute_code, value)
+JOIN product_variants v ON v.sku = o.sku
+JOIN products p ON p.id = v.product_id
+JOIN attributes a ON a.category_id = p.category_id AND a.code = o.attribute_code;